@@ -156,6 +156,7 @@ type GetConfigRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	ServiceName   string                 `protobuf:"bytes,1,opt,name=service_name,json=serviceName,proto3" json:"service_name,omitempty"`
 	Key           string                 `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	Linearizable  bool                   `protobuf:"varint,3,opt,name=linearizable,proto3" json:"linearizable,omitempty"` // 可选，绕过服务端缓存直接从 etcd 线性一致地读取
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -204,6 +205,13 @@ func (x *GetConfigRequest) GetKey() string {
 	return ""
 }
 
+func (x *GetConfigRequest) GetLinearizable() bool {
+	if x != nil {
+		return x.Linearizable
+	}
+	return false
+}
+
 // GetConfigResponse 获取配置响应
 type GetConfigResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -261,6 +269,10 @@ func (x *GetConfigResponse) GetFound() bool {
 type GetServiceConfigsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	ServiceName   string                 `protobuf:"bytes,1,opt,name=service_name,json=serviceName,proto3" json:"service_name,omitempty"`
+	Linearizable  bool                   `protobuf:"varint,2,opt,name=linearizable,proto3" json:"linearizable,omitempty"`           // 可选，绕过服务端缓存直接从 etcd 线性一致地读取
+	Limit         int32                  `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`                         // 可选，每页数量，0 表示不分页
+	PageToken     string                 `protobuf:"bytes,4,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"` // 可选，上一页返回的 next_page_token
+	KeysOnly      bool                   `protobuf:"varint,5,opt,name=keys_only,json=keysOnly,proto3" json:"keys_only,omitempty"`   // 可选，只返回配置键（keys），不返回 configs 与 items
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -302,10 +314,41 @@ func (x *GetServiceConfigsRequest) GetServiceName() string {
 	return ""
 }
 
+func (x *GetServiceConfigsRequest) GetLinearizable() bool {
+	if x != nil {
+		return x.Linearizable
+	}
+	return false
+}
+
+func (x *GetServiceConfigsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *GetServiceConfigsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *GetServiceConfigsRequest) GetKeysOnly() bool {
+	if x != nil {
+		return x.KeysOnly
+	}
+	return false
+}
+
 // GetServiceConfigsResponse 获取服务配置响应
 type GetServiceConfigsResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Configs       map[string]*ConfigItem `protobuf:"bytes,1,rep,name=configs,proto3" json:"configs,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Configs       map[string]*ConfigItem `protobuf:"bytes,1,rep,name=configs,proto3" json:"configs,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // 与 items 内容相同，map 不保留顺序，为兼容旧客户端保留
+	Keys          []string               `protobuf:"bytes,2,rep,name=keys,proto3" json:"keys,omitempty"`                                                                                 // keys_only 时按键排序的配置键
+	NextPageToken string                 `protobuf:"bytes,3,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`                                        // 下一页的令牌，没有更多数据时为空；分页按键排序
+	Items         []*ConfigItem          `protobuf:"bytes,4,rep,name=items,proto3" json:"items,omitempty"`                                                                               // 按键排序的配置，以此为准；旧版本的服务端不返回，此时使用 configs
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -347,6 +390,27 @@ func (x *GetServiceConfigsResponse) GetConfigs() map[string]*ConfigItem {
 	return nil
 }
 
+func (x *GetServiceConfigsResponse) GetKeys() []string {
+	if x != nil {
+		return x.Keys
+	}
+	return nil
+}
+
+func (x *GetServiceConfigsResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+func (x *GetServiceConfigsResponse) GetItems() []*ConfigItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
 // DeleteConfigRequest 删除配置请求
 type DeleteConfigRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -554,6 +618,10 @@ func (x *DeleteServiceConfigsResponse) GetMessage() string {
 // ListServicesRequest 列出服务请求
 type ListServicesRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
+	Linearizable  bool                   `protobuf:"varint,1,opt,name=linearizable,proto3" json:"linearizable,omitempty"`                     // 可选，绕过服务端缓存直接从 etcd 线性一致地读取
+	Limit         int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`                                   // 可选，每页数量，0 表示不分页
+	PageToken     string                 `protobuf:"bytes,3,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`           // 可选，上一页返回的 next_page_token
+	WithMetadata  bool                   `protobuf:"varint,4,opt,name=with_metadata,json=withMetadata,proto3" json:"with_metadata,omitempty"` // 可选，在 details 中返回服务元数据与统计信息
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -588,10 +656,40 @@ func (*ListServicesRequest) Descriptor() ([]byte, []int) {
 	return file_api_proto_config_proto_rawDescGZIP(), []int{10}
 }
 
+func (x *ListServicesRequest) GetLinearizable() bool {
+	if x != nil {
+		return x.Linearizable
+	}
+	return false
+}
+
+func (x *ListServicesRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListServicesRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *ListServicesRequest) GetWithMetadata() bool {
+	if x != nil {
+		return x.WithMetadata
+	}
+	return false
+}
+
 // ListServicesResponse 列出服务响应
 type ListServicesResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Services      []string               `protobuf:"bytes,1,rep,name=services,proto3" json:"services,omitempty"`
+	Services      []string               `protobuf:"bytes,1,rep,name=services,proto3" json:"services,omitempty"`                                  // 按 etcd 中的键顺序排列
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"` // 下一页的令牌，没有更多数据时为空
+	Details       []*ServiceInfo         `protobuf:"bytes,3,rep,name=details,proto3" json:"details,omitempty"`                                    // with_metadata 时返回，顺序与 services 一致
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -633,29 +731,50 @@ func (x *ListServicesResponse) GetServices() []string {
 	return nil
 }
 
-// WatchConfigRequest 监听配置请求
-type WatchConfigRequest struct {
+func (x *ListServicesResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+func (x *ListServicesResponse) GetDetails() []*ServiceInfo {
+	if x != nil {
+		return x.Details
+	}
+	return nil
+}
+
+// ServiceMeta 服务元数据
+type ServiceMeta struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	ServiceName   string                 `protobuf:"bytes,1,opt,name=service_name,json=serviceName,proto3" json:"service_name,omitempty"`
-	Key           string                 `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"` // 可选，如果为空则监听整个服务
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Description   string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	Owner         string                 `protobuf:"bytes,3,opt,name=owner,proto3" json:"owner,omitempty"` // 负责的团队
+	Contact       string                 `protobuf:"bytes,4,opt,name=contact,proto3" json:"contact,omitempty"`
+	Labels        map[string]string      `protobuf:"bytes,5,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Seeded        bool                   `protobuf:"varint,6,opt,name=seeded,proto3" json:"seeded,omitempty"`                        // 是否在 envs.toml 中声明，只读
+	CreatedAt     int64                  `protobuf:"varint,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"` // 只读
+	UpdatedAt     int64                  `protobuf:"varint,8,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"` // 只读
+	Protected     *bool                  `protobuf:"varint,9,opt,name=protected,proto3,oneof" json:"protected,omitempty"`            // 受保护的服务只能通过草稿修改配置，只有审批人可以修改此标记；SetServiceMeta 中未设置时保持不变
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *WatchConfigRequest) Reset() {
-	*x = WatchConfigRequest{}
+func (x *ServiceMeta) Reset() {
+	*x = ServiceMeta{}
 	mi := &file_api_proto_config_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *WatchConfigRequest) String() string {
+func (x *ServiceMeta) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*WatchConfigRequest) ProtoMessage() {}
+func (*ServiceMeta) ProtoMessage() {}
 
-func (x *WatchConfigRequest) ProtoReflect() protoreflect.Message {
+func (x *ServiceMeta) ProtoReflect() protoreflect.Message {
 	mi := &file_api_proto_config_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -667,48 +786,99 @@ func (x *WatchConfigRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use WatchConfigRequest.ProtoReflect.Descriptor instead.
-func (*WatchConfigRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use ServiceMeta.ProtoReflect.Descriptor instead.
+func (*ServiceMeta) Descriptor() ([]byte, []int) {
 	return file_api_proto_config_proto_rawDescGZIP(), []int{12}
 }
 
-func (x *WatchConfigRequest) GetServiceName() string {
+func (x *ServiceMeta) GetName() string {
 	if x != nil {
-		return x.ServiceName
+		return x.Name
 	}
 	return ""
 }
 
-func (x *WatchConfigRequest) GetKey() string {
+func (x *ServiceMeta) GetDescription() string {
 	if x != nil {
-		return x.Key
+		return x.Description
 	}
 	return ""
 }
 
-// WatchConfigResponse 监听配置响应
-type WatchConfigResponse struct {
+func (x *ServiceMeta) GetOwner() string {
+	if x != nil {
+		return x.Owner
+	}
+	return ""
+}
+
+func (x *ServiceMeta) GetContact() string {
+	if x != nil {
+		return x.Contact
+	}
+	return ""
+}
+
+func (x *ServiceMeta) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *ServiceMeta) GetSeeded() bool {
+	if x != nil {
+		return x.Seeded
+	}
+	return false
+}
+
+func (x *ServiceMeta) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+func (x *ServiceMeta) GetUpdatedAt() int64 {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return 0
+}
+
+func (x *ServiceMeta) GetProtected() bool {
+	if x != nil && x.Protected != nil {
+		return *x.Protected
+	}
+	return false
+}
+
+// ServiceStats 根据服务配置计算的统计信息
+type ServiceStats struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	EventType     string                 `protobuf:"bytes,1,opt,name=event_type,json=eventType,proto3" json:"event_type,omitempty"` // PUT, DELETE
-	Config        *ConfigItem            `protobuf:"bytes,2,opt,name=config,proto3" json:"config,omitempty"`
+	Keys          int64                  `protobuf:"varint,1,opt,name=keys,proto3" json:"keys,omitempty"`
+	ValueBytes    int64                  `protobuf:"varint,2,opt,name=value_bytes,json=valueBytes,proto3" json:"value_bytes,omitempty"`       // etcd 中存储的值的总字节数
+	LastModified  int64                  `protobuf:"varint,3,opt,name=last_modified,json=lastModified,proto3" json:"last_modified,omitempty"` // 配置项最近一次更新的时间（Unix时间戳）
+	LastRevision  int64                  `protobuf:"varint,4,opt,name=last_revision,json=lastRevision,proto3" json:"last_revision,omitempty"` // 配置项最近一次修改的版本号，不包括删除
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *WatchConfigResponse) Reset() {
-	*x = WatchConfigResponse{}
+func (x *ServiceStats) Reset() {
+	*x = ServiceStats{}
 	mi := &file_api_proto_config_proto_msgTypes[13]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *WatchConfigResponse) String() string {
+func (x *ServiceStats) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*WatchConfigResponse) ProtoMessage() {}
+func (*ServiceStats) ProtoMessage() {}
 
-func (x *WatchConfigResponse) ProtoReflect() protoreflect.Message {
+func (x *ServiceStats) ProtoReflect() protoreflect.Message {
 	mi := &file_api_proto_config_proto_msgTypes[13]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -720,53 +890,63 @@ func (x *WatchConfigResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use WatchConfigResponse.ProtoReflect.Descriptor instead.
-func (*WatchConfigResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use ServiceStats.ProtoReflect.Descriptor instead.
+func (*ServiceStats) Descriptor() ([]byte, []int) {
 	return file_api_proto_config_proto_rawDescGZIP(), []int{13}
 }
 
-func (x *WatchConfigResponse) GetEventType() string {
+func (x *ServiceStats) GetKeys() int64 {
 	if x != nil {
-		return x.EventType
+		return x.Keys
 	}
-	return ""
+	return 0
 }
 
-func (x *WatchConfigResponse) GetConfig() *ConfigItem {
+func (x *ServiceStats) GetValueBytes() int64 {
 	if x != nil {
-		return x.Config
+		return x.ValueBytes
 	}
-	return nil
+	return 0
 }
 
-// ConfigItem 配置项
-type ConfigItem struct {
+func (x *ServiceStats) GetLastModified() int64 {
+	if x != nil {
+		return x.LastModified
+	}
+	return 0
+}
+
+func (x *ServiceStats) GetLastRevision() int64 {
+	if x != nil {
+		return x.LastRevision
+	}
+	return 0
+}
+
+// ServiceInfo 服务的元数据与统计信息
+type ServiceInfo struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
-	Value         string                 `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
-	ServiceName   string                 `protobuf:"bytes,3,opt,name=service_name,json=serviceName,proto3" json:"service_name,omitempty"`
-	Description   string                 `protobuf:"bytes,4,opt,name=description,proto3" json:"description,omitempty"`
-	Encrypt       bool                   `protobuf:"varint,5,opt,name=encrypt,proto3" json:"encrypt,omitempty"`
-	CreatedAt     int64                  `protobuf:"varint,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
-	UpdatedAt     int64                  `protobuf:"varint,7,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Metadata      *ServiceMeta           `protobuf:"bytes,2,opt,name=metadata,proto3" json:"metadata,omitempty"` // 未设置元数据时为空
+	Stats         *ServiceStats          `protobuf:"bytes,3,opt,name=stats,proto3" json:"stats,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ConfigItem) Reset() {
-	*x = ConfigItem{}
+func (x *ServiceInfo) Reset() {
+	*x = ServiceInfo{}
 	mi := &file_api_proto_config_proto_msgTypes[14]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ConfigItem) String() string {
+func (x *ServiceInfo) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ConfigItem) ProtoMessage() {}
+func (*ServiceInfo) ProtoMessage() {}
 
-func (x *ConfigItem) ProtoReflect() protoreflect.Message {
+func (x *ServiceInfo) ProtoReflect() protoreflect.Message {
 	mi := &file_api_proto_config_proto_msgTypes[14]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -778,86 +958,3777 @@ func (x *ConfigItem) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ConfigItem.ProtoReflect.Descriptor instead.
-func (*ConfigItem) Descriptor() ([]byte, []int) {
+// Deprecated: Use ServiceInfo.ProtoReflect.Descriptor instead.
+func (*ServiceInfo) Descriptor() ([]byte, []int) {
 	return file_api_proto_config_proto_rawDescGZIP(), []int{14}
 }
 
-func (x *ConfigItem) GetKey() string {
+func (x *ServiceInfo) GetName() string {
 	if x != nil {
-		return x.Key
+		return x.Name
 	}
 	return ""
 }
 
-func (x *ConfigItem) GetValue() string {
+func (x *ServiceInfo) GetMetadata() *ServiceMeta {
 	if x != nil {
-		return x.Value
+		return x.Metadata
 	}
-	return ""
+	return nil
 }
 
-func (x *ConfigItem) GetServiceName() string {
+func (x *ServiceInfo) GetStats() *ServiceStats {
 	if x != nil {
-		return x.ServiceName
+		return x.Stats
 	}
-	return ""
+	return nil
 }
 
-func (x *ConfigItem) GetDescription() string {
+// SetServiceMetaRequest 设置服务元数据请求
+type SetServiceMetaRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Metadata      *ServiceMeta           `protobuf:"bytes,1,opt,name=metadata,proto3" json:"metadata,omitempty"` // name 必填
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetServiceMetaRequest) Reset() {
+	*x = SetServiceMetaRequest{}
+	mi := &file_api_proto_config_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetServiceMetaRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetServiceMetaRequest) ProtoMessage() {}
+
+func (x *SetServiceMetaRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_config_proto_msgTypes[15]
 	if x != nil {
-		return x.Description
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return ""
+	return mi.MessageOf(x)
 }
 
-func (x *ConfigItem) GetEncrypt() bool {
+// Deprecated: Use SetServiceMetaRequest.ProtoReflect.Descriptor instead.
+func (*SetServiceMetaRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_config_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *SetServiceMetaRequest) GetMetadata() *ServiceMeta {
 	if x != nil {
-		return x.Encrypt
+		return x.Metadata
 	}
-	return false
+	return nil
 }
 
-func (x *ConfigItem) GetCreatedAt() int64 {
+// SetServiceMetaResponse 设置服务元数据响应
+type SetServiceMetaResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Metadata      *ServiceMeta           `protobuf:"bytes,1,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetServiceMetaResponse) Reset() {
+	*x = SetServiceMetaResponse{}
+	mi := &file_api_proto_config_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetServiceMetaResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetServiceMetaResponse) ProtoMessage() {}
+
+func (x *SetServiceMetaResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_config_proto_msgTypes[16]
 	if x != nil {
-		return x.CreatedAt
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return 0
+	return mi.MessageOf(x)
 }
 
-func (x *ConfigItem) GetUpdatedAt() int64 {
+// Deprecated: Use SetServiceMetaResponse.ProtoReflect.Descriptor instead.
+func (*SetServiceMetaResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_config_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *SetServiceMetaResponse) GetMetadata() *ServiceMeta {
 	if x != nil {
-		return x.UpdatedAt
+		return x.Metadata
 	}
-	return 0
+	return nil
 }
 
-var File_api_proto_config_proto protoreflect.FileDescriptor
+// GetServiceMetaRequest 获取服务元数据请求
+type GetServiceMetaRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ServiceName   string                 `protobuf:"bytes,1,opt,name=service_name,json=serviceName,proto3" json:"service_name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
 
-const file_api_proto_config_proto_rawDesc = "" +
-	"\n" +
-	"\x16api/proto/config.proto\x12\x06config\"\x99\x01\n" +
-	"\x10SetConfigRequest\x12!\n" +
-	"\fservice_name\x18\x01 \x01(\tR\vserviceName\x12\x10\n" +
-	"\x03key\x18\x02 \x01(\tR\x03key\x12\x14\n" +
-	"\x05value\x18\x03 \x01(\tR\x05value\x12 \n" +
-	"\vdescription\x18\x04 \x01(\tR\vdescription\x12\x18\n" +
-	"\aencrypt\x18\x05 \x01(\bR\aencrypt\"G\n" +
-	"\x11SetConfigResponse\x12\x18\n" +
-	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
-	"\amessage\x18\x02 \x01(\tR\amessage\"G\n" +
-	"\x10GetConfigRequest\x12!\n" +
-	"\fservice_name\x18\x01 \x01(\tR\vserviceName\x12\x10\n" +
-	"\x03key\x18\x02 \x01(\tR\x03key\"U\n" +
-	"\x11GetConfigResponse\x12*\n" +
-	"\x06config\x18\x01 \x01(\v2\x12.config.ConfigItemR\x06config\x12\x14\n" +
-	"\x05found\x18\x02 \x01(\bR\x05found\"=\n" +
-	"\x18GetServiceConfigsRequest\x12!\n" +
-	"\fservice_name\x18\x01 \x01(\tR\vserviceName\"\xb5\x01\n" +
-	"\x19GetServiceConfigsResponse\x12H\n" +
-	"\aconfigs\x18\x01 \x03(\v2..config.GetServiceConfigsResponse.ConfigsEntryR\aconfigs\x1aN\n" +
-	"\fConfigsEntry\x12\x10\n" +
-	"\x03key\x18\x01 \x01(\tR\x03key\x12(\n" +
+func (x *GetServiceMetaRequest) Reset() {
+	*x = GetServiceMetaRequest{}
+	mi := &file_api_proto_config_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetServiceMetaRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetServiceMetaRequest) ProtoMessage() {}
+
+func (x *GetServiceMetaRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_config_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetServiceMetaRequest.ProtoReflect.Descriptor instead.
+func (*GetServiceMetaRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_config_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *GetServiceMetaRequest) GetServiceName() string {
+	if x != nil {
+		return x.ServiceName
+	}
+	return ""
+}
+
+// GetServiceMetaResponse 获取服务元数据响应
+type GetServiceMetaResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Service       *ServiceInfo           `protobuf:"bytes,1,opt,name=service,proto3" json:"service,omitempty"`
+	Found         bool                   `protobuf:"varint,2,opt,name=found,proto3" json:"found,omitempty"` // 既没有元数据也没有配置时为 false
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetServiceMetaResponse) Reset() {
+	*x = GetServiceMetaResponse{}
+	mi := &file_api_proto_config_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetServiceMetaResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetServiceMetaResponse) ProtoMessage() {}
+
+func (x *GetServiceMetaResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_config_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetServiceMetaResponse.ProtoReflect.Descriptor instead.
+func (*GetServiceMetaResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_config_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *GetServiceMetaResponse) GetService() *ServiceInfo {
+	if x != nil {
+		return x.Service
+	}
+	return nil
+}
+
+func (x *GetServiceMetaResponse) GetFound() bool {
+	if x != nil {
+		return x.Found
+	}
+	return false
+}
+
+// DeleteServiceMetaRequest 删除服务元数据请求
+type DeleteServiceMetaRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ServiceName   string                 `protobuf:"bytes,1,opt,name=service_name,json=serviceName,proto3" json:"service_name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteServiceMetaRequest) Reset() {
+	*x = DeleteServiceMetaRequest{}
+	mi := &file_api_proto_config_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteServiceMetaRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteServiceMetaRequest) ProtoMessage() {}
+
+func (x *DeleteServiceMetaRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_config_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteServiceMetaRequest.ProtoReflect.Descriptor instead.
+func (*DeleteServiceMetaRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_config_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *DeleteServiceMetaRequest) GetServiceName() string {
+	if x != nil {
+		return x.ServiceName
+	}
+	return ""
+}
+
+// DeleteServiceMetaResponse 删除服务元数据响应
+type DeleteServiceMetaResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteServiceMetaResponse) Reset() {
+	*x = DeleteServiceMetaResponse{}
+	mi := &file_api_proto_config_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteServiceMetaResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteServiceMetaResponse) ProtoMessage() {}
+
+func (x *DeleteServiceMetaResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_config_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteServiceMetaResponse.ProtoReflect.Descriptor instead.
+func (*DeleteServiceMetaResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_config_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *DeleteServiceMetaResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *DeleteServiceMetaResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+// SearchRequest 搜索配置请求
+type SearchRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Query         string                 `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"` // 子串，regex 为 true 时为正则表达式
+	Regex         bool                   `protobuf:"varint,2,opt,name=regex,proto3" json:"regex,omitempty"`
+	IgnoreCase    bool                   `protobuf:"varint,3,opt,name=ignore_case,json=ignoreCase,proto3" json:"ignore_case,omitempty"`
+	Fields        []string               `protobuf:"bytes,4,rep,name=fields,proto3" json:"fields,omitempty"`                                                                           // 可选，匹配的字段：key、value、description，为空时全部匹配
+	Services      []string               `protobuf:"bytes,5,rep,name=services,proto3" json:"services,omitempty"`                                                                       // 可选，服务名，支持通配符，为空时搜索所有服务
+	Labels        map[string]string      `protobuf:"bytes,6,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // 可选，服务元数据中必须包含的标签
+	Limit         int32                  `protobuf:"varint,7,opt,name=limit,proto3" json:"limit,omitempty"`                                                                            // 可选，最多返回的结果数，默认 100，最大 1000
+	Linearizable  bool                   `protobuf:"varint,8,opt,name=linearizable,proto3" json:"linearizable,omitempty"`                                                              // 为 true 时从 etcd 读取，不使用缓存
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchRequest) Reset() {
+	*x = SearchRequest{}
+	mi := &file_api_proto_config_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchRequest) ProtoMessage() {}
+
+func (x *SearchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_config_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchRequest.ProtoReflect.Descriptor instead.
+func (*SearchRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_config_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *SearchRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *SearchRequest) GetRegex() bool {
+	if x != nil {
+		return x.Regex
+	}
+	return false
+}
+
+func (x *SearchRequest) GetIgnoreCase() bool {
+	if x != nil {
+		return x.IgnoreCase
+	}
+	return false
+}
+
+func (x *SearchRequest) GetFields() []string {
+	if x != nil {
+		return x.Fields
+	}
+	return nil
+}
+
+func (x *SearchRequest) GetServices() []string {
+	if x != nil {
+		return x.Services
+	}
+	return nil
+}
+
+func (x *SearchRequest) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *SearchRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *SearchRequest) GetLinearizable() bool {
+	if x != nil {
+		return x.Linearizable
+	}
+	return false
+}
+
+// SearchMatch 命中的配置项
+type SearchMatch struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Config        *ConfigItem            `protobuf:"bytes,1,opt,name=config,proto3" json:"config,omitempty"` // 未授权时敏感配置的值为 ******
+	MatchedFields []string               `protobuf:"bytes,2,rep,name=matched_fields,json=matchedFields,proto3" json:"matched_fields,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchMatch) Reset() {
+	*x = SearchMatch{}
+	mi := &file_api_proto_config_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchMatch) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchMatch) ProtoMessage() {}
+
+func (x *SearchMatch) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_config_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchMatch.ProtoReflect.Descriptor instead.
+func (*SearchMatch) Descriptor() ([]byte, []int) {
+	return file_api_proto_config_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *SearchMatch) GetConfig() *ConfigItem {
+	if x != nil {
+		return x.Config
+	}
+	return nil
+}
+
+func (x *SearchMatch) GetMatchedFields() []string {
+	if x != nil {
+		return x.MatchedFields
+	}
+	return nil
+}
+
+// SearchResponse 搜索配置响应
+type SearchResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Matches       []*SearchMatch         `protobuf:"bytes,1,rep,name=matches,proto3" json:"matches,omitempty"`      // 按服务名与配置键排序
+	Truncated     bool                   `protobuf:"varint,2,opt,name=truncated,proto3" json:"truncated,omitempty"` // 命中数超过 limit，结果被截断
+	Revision      int64                  `protobuf:"varint,3,opt,name=revision,proto3" json:"revision,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchResponse) Reset() {
+	*x = SearchResponse{}
+	mi := &file_api_proto_config_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchResponse) ProtoMessage() {}
+
+func (x *SearchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_config_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchResponse.ProtoReflect.Descriptor instead.
+func (*SearchResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_config_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *SearchResponse) GetMatches() []*SearchMatch {
+	if x != nil {
+		return x.Matches
+	}
+	return nil
+}
+
+func (x *SearchResponse) GetTruncated() bool {
+	if x != nil {
+		return x.Truncated
+	}
+	return false
+}
+
+func (x *SearchResponse) GetRevision() int64 {
+	if x != nil {
+		return x.Revision
+	}
+	return 0
+}
+
+// SyncConfig 声明的配置项
+type SyncConfig struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Value         string                 `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"` // JSON，无法解析时按字符串处理
+	Description   string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	Encrypt       bool                   `protobuf:"varint,4,opt,name=encrypt,proto3" json:"encrypt,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SyncConfig) Reset() {
+	*x = SyncConfig{}
+	mi := &file_api_proto_config_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SyncConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SyncConfig) ProtoMessage() {}
+
+func (x *SyncConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_config_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SyncConfig.ProtoReflect.Descriptor instead.
+func (*SyncConfig) Descriptor() ([]byte, []int) {
+	return file_api_proto_config_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *SyncConfig) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *SyncConfig) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+func (x *SyncConfig) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *SyncConfig) GetEncrypt() bool {
+	if x != nil {
+		return x.Encrypt
+	}
+	return false
+}
+
+// SyncService 声明的服务配置
+type SyncService struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Configs       []*SyncConfig          `protobuf:"bytes,2,rep,name=configs,proto3" json:"configs,omitempty"`
+	Unmanaged     []string               `protobuf:"bytes,3,rep,name=unmanaged,proto3" json:"unmanaged,omitempty"` // 本地管理的键，支持通配符，同步时忽略
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SyncService) Reset() {
+	*x = SyncService{}
+	mi := &file_api_proto_config_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SyncService) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SyncService) ProtoMessage() {}
+
+func (x *SyncService) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_config_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SyncService.ProtoReflect.Descriptor instead.
+func (*SyncService) Descriptor() ([]byte, []int) {
+	return file_api_proto_config_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *SyncService) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *SyncService) GetConfigs() []*SyncConfig {
+	if x != nil {
+		return x.Configs
+	}
+	return nil
+}
+
+func (x *SyncService) GetUnmanaged() []string {
+	if x != nil {
+		return x.Unmanaged
+	}
+	return nil
+}
+
+// SyncRequest 同步请求，只涉及声明的服务
+type SyncRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Services      []*SyncService         `protobuf:"bytes,1,rep,name=services,proto3" json:"services,omitempty"`
+	Prune         bool                   `protobuf:"varint,2,opt,name=prune,proto3" json:"prune,omitempty"`                 // 删除没有声明的键
+	DryRun        bool                   `protobuf:"varint,3,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"` // 只返回计划，不写入
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SyncRequest) Reset() {
+	*x = SyncRequest{}
+	mi := &file_api_proto_config_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SyncRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SyncRequest) ProtoMessage() {}
+
+func (x *SyncRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_config_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SyncRequest.ProtoReflect.Descriptor instead.
+func (*SyncRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_config_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *SyncRequest) GetServices() []*SyncService {
+	if x != nil {
+		return x.Services
+	}
+	return nil
+}
+
+func (x *SyncRequest) GetPrune() bool {
+	if x != nil {
+		return x.Prune
+	}
+	return false
+}
+
+func (x *SyncRequest) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+// SyncChange 同步计划中的变更
+type SyncChange struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Action        string                 `protobuf:"bytes,1,opt,name=action,proto3" json:"action,omitempty"` // create, update, delete
+	ServiceName   string                 `protobuf:"bytes,2,opt,name=service_name,json=serviceName,proto3" json:"service_name,omitempty"`
+	Key           string                 `protobuf:"bytes,3,opt,name=key,proto3" json:"key,omitempty"`
+	Fields        []string               `protobuf:"bytes,4,rep,name=fields,proto3" json:"fields,omitempty"`   // 更新时变化的字段：value、description、encrypt
+	Config        *ConfigItem            `protobuf:"bytes,5,opt,name=config,proto3" json:"config,omitempty"`   // 同步后的配置，删除时为空，未授权时敏感配置的值为 ******
+	Current       *ConfigItem            `protobuf:"bytes,6,opt,name=current,proto3" json:"current,omitempty"` // 当前的配置，新建时为空
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SyncChange) Reset() {
+	*x = SyncChange{}
+	mi := &file_api_proto_config_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SyncChange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SyncChange) ProtoMessage() {}
+
+func (x *SyncChange) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_config_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SyncChange.ProtoReflect.Descriptor instead.
+func (*SyncChange) Descriptor() ([]byte, []int) {
+	return file_api_proto_config_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *SyncChange) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *SyncChange) GetServiceName() string {
+	if x != nil {
+		return x.ServiceName
+	}
+	return ""
+}
+
+func (x *SyncChange) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *SyncChange) GetFields() []string {
+	if x != nil {
+		return x.Fields
+	}
+	return nil
+}
+
+func (x *SyncChange) GetConfig() *ConfigItem {
+	if x != nil {
+		return x.Config
+	}
+	return nil
+}
+
+func (x *SyncChange) GetCurrent() *ConfigItem {
+	if x != nil {
+		return x.Current
+	}
+	return nil
+}
+
+// SyncResponse 同步响应
+type SyncResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Changes       []*SyncChange          `protobuf:"bytes,1,rep,name=changes,proto3" json:"changes,omitempty"`    // 按服务名与配置键排序
+	Extra         []string               `protobuf:"bytes,2,rep,name=extra,proto3" json:"extra,omitempty"`        // 没有声明且未删除的键，格式为 service/key
+	Revision      int64                  `protobuf:"varint,3,opt,name=revision,proto3" json:"revision,omitempty"` // 计划所基于的版本号，写入后为最后一次写入的版本号
+	Applied       bool                   `protobuf:"varint,4,opt,name=applied,proto3" json:"applied,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SyncResponse) Reset() {
+	*x = SyncResponse{}
+	mi := &file_api_proto_config_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SyncResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SyncResponse) ProtoMessage() {}
+
+func (x *SyncResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_config_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SyncResponse.ProtoReflect.Descriptor instead.
+func (*SyncResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_config_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *SyncResponse) GetChanges() []*SyncChange {
+	if x != nil {
+		return x.Changes
+	}
+	return nil
+}
+
+func (x *SyncResponse) GetExtra() []string {
+	if x != nil {
+		return x.Extra
+	}
+	return nil
+}
+
+func (x *SyncResponse) GetRevision() int64 {
+	if x != nil {
+		return x.Revision
+	}
+	return 0
+}
+
+func (x *SyncResponse) GetApplied() bool {
+	if x != nil {
+		return x.Applied
+	}
+	return false
+}
+
+// ExportRequest 导出请求
+type ExportRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Format        string                 `protobuf:"bytes,1,opt,name=format,proto3" json:"format,omitempty"`     // json（默认）或 toml
+	Services      []string               `protobuf:"bytes,2,rep,name=services,proto3" json:"services,omitempty"` // 可选，服务名，支持通配符，为空时导出所有服务
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportRequest) Reset() {
+	*x = ExportRequest{}
+	mi := &file_api_proto_config_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportRequest) ProtoMessage() {}
+
+func (x *ExportRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_config_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportRequest.ProtoReflect.Descriptor instead.
+func (*ExportRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_config_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *ExportRequest) GetFormat() string {
+	if x != nil {
+		return x.Format
+	}
+	return ""
+}
+
+func (x *ExportRequest) GetServices() []string {
+	if x != nil {
+		return x.Services
+	}
+	return nil
+}
+
+// ExportResponse 导出响应
+type ExportResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Archive       []byte                 `protobuf:"bytes,1,opt,name=archive,proto3" json:"archive,omitempty"` // 按 format 编码的归档
+	Format        string                 `protobuf:"bytes,2,opt,name=format,proto3" json:"format,omitempty"`
+	Revision      int64                  `protobuf:"varint,3,opt,name=revision,proto3" json:"revision,omitempty"` // 导出所基于的版本号
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportResponse) Reset() {
+	*x = ExportResponse{}
+	mi := &file_api_proto_config_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportResponse) ProtoMessage() {}
+
+func (x *ExportResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_config_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportResponse.ProtoReflect.Descriptor instead.
+func (*ExportResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_config_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *ExportResponse) GetArchive() []byte {
+	if x != nil {
+		return x.Archive
+	}
+	return nil
+}
+
+func (x *ExportResponse) GetFormat() string {
+	if x != nil {
+		return x.Format
+	}
+	return ""
+}
+
+func (x *ExportResponse) GetRevision() int64 {
+	if x != nil {
+		return x.Revision
+	}
+	return 0
+}
+
+// ImportRequest 导入请求
+type ImportRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Archive       []byte                 `protobuf:"bytes,1,opt,name=archive,proto3" json:"archive,omitempty"`
+	Format        string                 `protobuf:"bytes,2,opt,name=format,proto3" json:"format,omitempty"`                // 可选，json 或 toml，为空时按内容判断
+	Mode          string                 `protobuf:"bytes,3,opt,name=mode,proto3" json:"mode,omitempty"`                    // merge（默认）或 replace
+	Services      []string               `protobuf:"bytes,4,rep,name=services,proto3" json:"services,omitempty"`            // 可选，只导入匹配的服务，支持通配符
+	DryRun        bool                   `protobuf:"varint,5,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"` // 只返回计划，不写入
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportRequest) Reset() {
+	*x = ImportRequest{}
+	mi := &file_api_proto_config_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportRequest) ProtoMessage() {}
+
+func (x *ImportRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_config_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportRequest.ProtoReflect.Descriptor instead.
+func (*ImportRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_config_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *ImportRequest) GetArchive() []byte {
+	if x != nil {
+		return x.Archive
+	}
+	return nil
+}
+
+func (x *ImportRequest) GetFormat() string {
+	if x != nil {
+		return x.Format
+	}
+	return ""
+}
+
+func (x *ImportRequest) GetMode() string {
+	if x != nil {
+		return x.Mode
+	}
+	return ""
+}
+
+func (x *ImportRequest) GetServices() []string {
+	if x != nil {
+		return x.Services
+	}
+	return nil
+}
+
+func (x *ImportRequest) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+// ImportResponse 导入响应
+type ImportResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Changes       []*SyncChange          `protobuf:"bytes,1,rep,name=changes,proto3" json:"changes,omitempty"`    // 按服务名与配置键排序
+	Extra         []string               `protobuf:"bytes,2,rep,name=extra,proto3" json:"extra,omitempty"`        // merge 模式下归档中没有且保留的键，格式为 service/key
+	Revision      int64                  `protobuf:"varint,3,opt,name=revision,proto3" json:"revision,omitempty"` // 计划所基于的版本号，写入后为最后一次写入的版本号
+	Applied       bool                   `protobuf:"varint,4,opt,name=applied,proto3" json:"applied,omitempty"`
+	Mode          string                 `protobuf:"bytes,5,opt,name=mode,proto3" json:"mode,omitempty"`
+	Metadata      []string               `protobuf:"bytes,6,rep,name=metadata,proto3" json:"metadata,omitempty"` // 元数据被覆盖的服务
+	Skipped       []string               `protobuf:"bytes,7,rep,name=skipped,proto3" json:"skipped,omitempty"`   // 值未导出而跳过的敏感配置，格式为 service/key
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportResponse) Reset() {
+	*x = ImportResponse{}
+	mi := &file_api_proto_config_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportResponse) ProtoMessage() {}
+
+func (x *ImportResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_config_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportResponse.ProtoReflect.Descriptor instead.
+func (*ImportResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_config_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *ImportResponse) GetChanges() []*SyncChange {
+	if x != nil {
+		return x.Changes
+	}
+	return nil
+}
+
+func (x *ImportResponse) GetExtra() []string {
+	if x != nil {
+		return x.Extra
+	}
+	return nil
+}
+
+func (x *ImportResponse) GetRevision() int64 {
+	if x != nil {
+		return x.Revision
+	}
+	return 0
+}
+
+func (x *ImportResponse) GetApplied() bool {
+	if x != nil {
+		return x.Applied
+	}
+	return false
+}
+
+func (x *ImportResponse) GetMode() string {
+	if x != nil {
+		return x.Mode
+	}
+	return ""
+}
+
+func (x *ImportResponse) GetMetadata() []string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *ImportResponse) GetSkipped() []string {
+	if x != nil {
+		return x.Skipped
+	}
+	return nil
+}
+
+// BackupInfo 备份文件信息
+type BackupInfo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Revision      int64                  `protobuf:"varint,2,opt,name=revision,proto3" json:"revision,omitempty"` // 备份所基于的版本号
+	CreatedAt     int64                  `protobuf:"varint,3,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	Size          int64                  `protobuf:"varint,4,opt,name=size,proto3" json:"size,omitempty"` // 文件大小（字节）
+	Encrypted     bool                   `protobuf:"varint,5,opt,name=encrypted,proto3" json:"encrypted,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BackupInfo) Reset() {
+	*x = BackupInfo{}
+	mi := &file_api_proto_config_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BackupInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BackupInfo) ProtoMessage() {}
+
+func (x *BackupInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_config_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BackupInfo.ProtoReflect.Descriptor instead.
+func (*BackupInfo) Descriptor() ([]byte, []int) {
+	return file_api_proto_config_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *BackupInfo) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *BackupInfo) GetRevision() int64 {
+	if x != nil {
+		return x.Revision
+	}
+	return 0
+}
+
+func (x *BackupInfo) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+func (x *BackupInfo) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *BackupInfo) GetEncrypted() bool {
+	if x != nil {
+		return x.Encrypted
+	}
+	return false
+}
+
+// ListBackupsRequest 列出备份请求
+type ListBackupsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListBackupsRequest) Reset() {
+	*x = ListBackupsRequest{}
+	mi := &file_api_proto_config_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListBackupsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListBackupsRequest) ProtoMessage() {}
+
+func (x *ListBackupsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_config_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListBackupsRequest.ProtoReflect.Descriptor instead.
+func (*ListBackupsRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_config_proto_rawDescGZIP(), []int{34}
+}
+
+// ListBackupsResponse 列出备份响应
+type ListBackupsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Backups       []*BackupInfo          `protobuf:"bytes,1,rep,name=backups,proto3" json:"backups,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListBackupsResponse) Reset() {
+	*x = ListBackupsResponse{}
+	mi := &file_api_proto_config_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListBackupsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListBackupsResponse) ProtoMessage() {}
+
+func (x *ListBackupsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_config_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListBackupsResponse.ProtoReflect.Descriptor instead.
+func (*ListBackupsResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_config_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *ListBackupsResponse) GetBackups() []*BackupInfo {
+	if x != nil {
+		return x.Backups
+	}
+	return nil
+}
+
+// CreateBackupRequest 创建备份请求
+type CreateBackupRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateBackupRequest) Reset() {
+	*x = CreateBackupRequest{}
+	mi := &file_api_proto_config_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateBackupRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateBackupRequest) ProtoMessage() {}
+
+func (x *CreateBackupRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_config_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateBackupRequest.ProtoReflect.Descriptor instead.
+func (*CreateBackupRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_config_proto_rawDescGZIP(), []int{36}
+}
+
+// CreateBackupResponse 创建备份响应
+type CreateBackupResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Backup        *BackupInfo            `protobuf:"bytes,1,opt,name=backup,proto3" json:"backup,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateBackupResponse) Reset() {
+	*x = CreateBackupResponse{}
+	mi := &file_api_proto_config_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateBackupResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateBackupResponse) ProtoMessage() {}
+
+func (x *CreateBackupResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_config_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateBackupResponse.ProtoReflect.Descriptor instead.
+func (*CreateBackupResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_config_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *CreateBackupResponse) GetBackup() *BackupInfo {
+	if x != nil {
+		return x.Backup
+	}
+	return nil
+}
+
+// RestoreBackupRequest 恢复备份请求
+type RestoreBackupRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Services      []string               `protobuf:"bytes,2,rep,name=services,proto3" json:"services,omitempty"`            // 可选，只恢复匹配的服务，支持通配符
+	Mode          string                 `protobuf:"bytes,3,opt,name=mode,proto3" json:"mode,omitempty"`                    // replace（默认）或 merge
+	DryRun        bool                   `protobuf:"varint,4,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"` // 只返回计划，不写入，优先于 confirm
+	Confirm       bool                   `protobuf:"varint,5,opt,name=confirm,proto3" json:"confirm,omitempty"`             // 确认写入，未确认时只返回计划
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RestoreBackupRequest) Reset() {
+	*x = RestoreBackupRequest{}
+	mi := &file_api_proto_config_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestoreBackupRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreBackupRequest) ProtoMessage() {}
+
+func (x *RestoreBackupRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_config_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreBackupRequest.ProtoReflect.Descriptor instead.
+func (*RestoreBackupRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_config_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *RestoreBackupRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *RestoreBackupRequest) GetServices() []string {
+	if x != nil {
+		return x.Services
+	}
+	return nil
+}
+
+func (x *RestoreBackupRequest) GetMode() string {
+	if x != nil {
+		return x.Mode
+	}
+	return ""
+}
+
+func (x *RestoreBackupRequest) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+func (x *RestoreBackupRequest) GetConfirm() bool {
+	if x != nil {
+		return x.Confirm
+	}
+	return false
+}
+
+// DiffRequest 比较请求
+// 每一侧的格式为 [<namespace>/]<service>[@<revision>]，namespace 为空时为本地存储，
+// 为 backup:<name> 时为本地备份，为 file:<path> 时为随请求上传的归档，否则为服务端配置的环境名
+type DiffRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Left          string                 `protobuf:"bytes,1,opt,name=left,proto3" json:"left,omitempty"`
+	Right         string                 `protobuf:"bytes,2,opt,name=right,proto3" json:"right,omitempty"`
+	LeftArchive   []byte                 `protobuf:"bytes,3,opt,name=left_archive,json=leftArchive,proto3" json:"left_archive,omitempty"`    // left 为 file: 时的归档内容
+	RightArchive  []byte                 `protobuf:"bytes,4,opt,name=right_archive,json=rightArchive,proto3" json:"right_archive,omitempty"` // right 为 file: 时的归档内容
+	Unified       bool                   `protobuf:"varint,5,opt,name=unified,proto3" json:"unified,omitempty"`                              // 同时返回统一格式（diff -u）的文本
+	Context       int32                  `protobuf:"varint,6,opt,name=context,proto3" json:"context,omitempty"`                              // 统一格式中的上下文行数，0 时为 3，小于 0 时不输出上下文
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DiffRequest) Reset() {
+	*x = DiffRequest{}
+	mi := &file_api_proto_config_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DiffRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DiffRequest) ProtoMessage() {}
+
+func (x *DiffRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_config_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DiffRequest.ProtoReflect.Descriptor instead.
+func (*DiffRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_config_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *DiffRequest) GetLeft() string {
+	if x != nil {
+		return x.Left
+	}
+	return ""
+}
+
+func (x *DiffRequest) GetRight() string {
+	if x != nil {
+		return x.Right
+	}
+	return ""
+}
+
+func (x *DiffRequest) GetLeftArchive() []byte {
+	if x != nil {
+		return x.LeftArchive
+	}
+	return nil
+}
+
+func (x *DiffRequest) GetRightArchive() []byte {
+	if x != nil {
+		return x.RightArchive
+	}
+	return nil
+}
+
+func (x *DiffRequest) GetUnified() bool {
+	if x != nil {
+		return x.Unified
+	}
+	return false
+}
+
+func (x *DiffRequest) GetContext() int32 {
+	if x != nil {
+		return x.Context
+	}
+	return 0
+}
+
+// DraftOperation 草稿中的一个操作
+type DraftOperation struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Op            string                 `protobuf:"bytes,1,opt,name=op,proto3" json:"op,omitempty"` // set 或 delete
+	ServiceName   string                 `protobuf:"bytes,2,opt,name=service_name,json=serviceName,proto3" json:"service_name,omitempty"`
+	Key           string                 `protobuf:"bytes,3,opt,name=key,proto3" json:"key,omitempty"`
+	Value         string                 `protobuf:"bytes,4,opt,name=value,proto3" json:"value,omitempty"` // set 的值，与 SetConfigRequest 相同，未授权时敏感配置的值为 "******"
+	Description   string                 `protobuf:"bytes,5,opt,name=description,proto3" json:"description,omitempty"`
+	Encrypt       bool                   `protobuf:"varint,6,opt,name=encrypt,proto3" json:"encrypt,omitempty"`
+	BaseRevision  int64                  `protobuf:"varint,7,opt,name=base_revision,json=baseRevision,proto3" json:"base_revision,omitempty"` // 只读，草稿创建或更新时配置的修改版本号，不存在时为 0
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DraftOperation) Reset() {
+	*x = DraftOperation{}
+	mi := &file_api_proto_config_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DraftOperation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DraftOperation) ProtoMessage() {}
+
+func (x *DraftOperation) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_config_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DraftOperation.ProtoReflect.Descriptor instead.
+func (*DraftOperation) Descriptor() ([]byte, []int) {
+	return file_api_proto_config_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *DraftOperation) GetOp() string {
+	if x != nil {
+		return x.Op
+	}
+	return ""
+}
+
+func (x *DraftOperation) GetServiceName() string {
+	if x != nil {
+		return x.ServiceName
+	}
+	return ""
+}
+
+func (x *DraftOperation) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *DraftOperation) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+func (x *DraftOperation) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *DraftOperation) GetEncrypt() bool {
+	if x != nil {
+		return x.Encrypt
+	}
+	return false
+}
+
+func (x *DraftOperation) GetBaseRevision() int64 {
+	if x != nil {
+		return x.BaseRevision
+	}
+	return 0
+}
+
+// DraftApproval 草稿的一个审批
+type DraftApproval struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Approver      string                 `protobuf:"bytes,1,opt,name=approver,proto3" json:"approver,omitempty"`
+	ApprovedAt    int64                  `protobuf:"varint,2,opt,name=approved_at,json=approvedAt,proto3" json:"approved_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DraftApproval) Reset() {
+	*x = DraftApproval{}
+	mi := &file_api_proto_config_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DraftApproval) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DraftApproval) ProtoMessage() {}
+
+func (x *DraftApproval) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_config_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DraftApproval.ProtoReflect.Descriptor instead.
+func (*DraftApproval) Descriptor() ([]byte, []int) {
+	return file_api_proto_config_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *DraftApproval) GetApprover() string {
+	if x != nil {
+		return x.Approver
+	}
+	return ""
+}
+
+func (x *DraftApproval) GetApprovedAt() int64 {
+	if x != nil {
+		return x.ApprovedAt
+	}
+	return 0
+}
+
+// Draft 待审批的一组配置修改
+type Draft struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Id                string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Description       string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	Author            string                 `protobuf:"bytes,3,opt,name=author,proto3" json:"author,omitempty"`
+	Status            string                 `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`         // open, published, abandoned
+	Operations        []*DraftOperation      `protobuf:"bytes,5,rep,name=operations,proto3" json:"operations,omitempty"` // 按服务名与配置键排序
+	Approvals         []*DraftApproval       `protobuf:"bytes,6,rep,name=approvals,proto3" json:"approvals,omitempty"`
+	RequiredApprovals int32                  `protobuf:"varint,7,opt,name=required_approvals,json=requiredApprovals,proto3" json:"required_approvals,omitempty"`
+	CreatedAt         int64                  `protobuf:"varint,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt         int64                  `protobuf:"varint,9,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	ClosedBy          string                 `protobuf:"bytes,10,opt,name=closed_by,json=closedBy,proto3" json:"closed_by,omitempty"` // 发布或放弃草稿的人
+	ClosedAt          int64                  `protobuf:"varint,11,opt,name=closed_at,json=closedAt,proto3" json:"closed_at,omitempty"`
+	Revision          int64                  `protobuf:"varint,12,opt,name=revision,proto3" json:"revision,omitempty"` // 发布时写入配置的版本号
+	Editors           []string               `protobuf:"bytes,13,rep,name=editors,proto3" json:"editors,omitempty"`    // 修改过草稿的人，不含作者
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *Draft) Reset() {
+	*x = Draft{}
+	mi := &file_api_proto_config_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Draft) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Draft) ProtoMessage() {}
+
+func (x *Draft) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_config_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Draft.ProtoReflect.Descriptor instead.
+func (*Draft) Descriptor() ([]byte, []int) {
+	return file_api_proto_config_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *Draft) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Draft) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Draft) GetAuthor() string {
+	if x != nil {
+		return x.Author
+	}
+	return ""
+}
+
+func (x *Draft) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Draft) GetOperations() []*DraftOperation {
+	if x != nil {
+		return x.Operations
+	}
+	return nil
+}
+
+func (x *Draft) GetApprovals() []*DraftApproval {
+	if x != nil {
+		return x.Approvals
+	}
+	return nil
+}
+
+func (x *Draft) GetRequiredApprovals() int32 {
+	if x != nil {
+		return x.RequiredApprovals
+	}
+	return 0
+}
+
+func (x *Draft) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+func (x *Draft) GetUpdatedAt() int64 {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return 0
+}
+
+func (x *Draft) GetClosedBy() string {
+	if x != nil {
+		return x.ClosedBy
+	}
+	return ""
+}
+
+func (x *Draft) GetClosedAt() int64 {
+	if x != nil {
+		return x.ClosedAt
+	}
+	return 0
+}
+
+func (x *Draft) GetRevision() int64 {
+	if x != nil {
+		return x.Revision
+	}
+	return 0
+}
+
+func (x *Draft) GetEditors() []string {
+	if x != nil {
+		return x.Editors
+	}
+	return nil
+}
+
+// CreateDraftRequest 创建草稿请求
+type CreateDraftRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Description   string                 `protobuf:"bytes,1,opt,name=description,proto3" json:"description,omitempty"`
+	Operations    []*DraftOperation      `protobuf:"bytes,3,rep,name=operations,proto3" json:"operations,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateDraftRequest) Reset() {
+	*x = CreateDraftRequest{}
+	mi := &file_api_proto_config_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateDraftRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateDraftRequest) ProtoMessage() {}
+
+func (x *CreateDraftRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_config_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateDraftRequest.ProtoReflect.Descriptor instead.
+func (*CreateDraftRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_config_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *CreateDraftRequest) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *CreateDraftRequest) GetOperations() []*DraftOperation {
+	if x != nil {
+		return x.Operations
+	}
+	return nil
+}
+
+// UpdateDraftRequest 修改草稿请求
+type UpdateDraftRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Description   string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"` // 为空时保持不变
+	Operations    []*DraftOperation      `protobuf:"bytes,3,rep,name=operations,proto3" json:"operations,omitempty"`   // 为空时保留原有的操作
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateDraftRequest) Reset() {
+	*x = UpdateDraftRequest{}
+	mi := &file_api_proto_config_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateDraftRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateDraftRequest) ProtoMessage() {}
+
+func (x *UpdateDraftRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_config_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateDraftRequest.ProtoReflect.Descriptor instead.
+func (*UpdateDraftRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_config_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *UpdateDraftRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UpdateDraftRequest) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *UpdateDraftRequest) GetOperations() []*DraftOperation {
+	if x != nil {
+		return x.Operations
+	}
+	return nil
+}
+
+// DraftActionRequest 审批、发布或放弃草稿的请求
+type DraftActionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DraftActionRequest) Reset() {
+	*x = DraftActionRequest{}
+	mi := &file_api_proto_config_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DraftActionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DraftActionRequest) ProtoMessage() {}
+
+func (x *DraftActionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_config_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DraftActionRequest.ProtoReflect.Descriptor instead.
+func (*DraftActionRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_config_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *DraftActionRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+// DraftResponse 草稿响应
+type DraftResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Draft         *Draft                 `protobuf:"bytes,1,opt,name=draft,proto3" json:"draft,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DraftResponse) Reset() {
+	*x = DraftResponse{}
+	mi := &file_api_proto_config_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DraftResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DraftResponse) ProtoMessage() {}
+
+func (x *DraftResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_config_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DraftResponse.ProtoReflect.Descriptor instead.
+func (*DraftResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_config_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *DraftResponse) GetDraft() *Draft {
+	if x != nil {
+		return x.Draft
+	}
+	return nil
+}
+
+// GetDraftRequest 获取草稿请求
+type GetDraftRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Diff          bool                   `protobuf:"varint,2,opt,name=diff,proto3" json:"diff,omitempty"`       // 同时返回差异
+	Unified       bool                   `protobuf:"varint,3,opt,name=unified,proto3" json:"unified,omitempty"` // 差异同时包含统一格式的文本
+	Context       int32                  `protobuf:"varint,4,opt,name=context,proto3" json:"context,omitempty"` // 统一格式中的上下文行数，0 时为 3，小于 0 时不输出上下文
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDraftRequest) Reset() {
+	*x = GetDraftRequest{}
+	mi := &file_api_proto_config_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDraftRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDraftRequest) ProtoMessage() {}
+
+func (x *GetDraftRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_config_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDraftRequest.ProtoReflect.Descriptor instead.
+func (*GetDraftRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_config_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *GetDraftRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *GetDraftRequest) GetDiff() bool {
+	if x != nil {
+		return x.Diff
+	}
+	return false
+}
+
+func (x *GetDraftRequest) GetUnified() bool {
+	if x != nil {
+		return x.Unified
+	}
+	return false
+}
+
+func (x *GetDraftRequest) GetContext() int32 {
+	if x != nil {
+		return x.Context
+	}
+	return 0
+}
+
+// GetDraftResponse 获取草稿响应
+type GetDraftResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Draft         *Draft                 `protobuf:"bytes,1,opt,name=draft,proto3" json:"draft,omitempty"`
+	Diffs         []*DiffResponse        `protobuf:"bytes,2,rep,name=diffs,proto3" json:"diffs,omitempty"` // 每个服务一项，按服务名排序
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDraftResponse) Reset() {
+	*x = GetDraftResponse{}
+	mi := &file_api_proto_config_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDraftResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDraftResponse) ProtoMessage() {}
+
+func (x *GetDraftResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_config_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDraftResponse.ProtoReflect.Descriptor instead.
+func (*GetDraftResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_config_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *GetDraftResponse) GetDraft() *Draft {
+	if x != nil {
+		return x.Draft
+	}
+	return nil
+}
+
+func (x *GetDraftResponse) GetDiffs() []*DiffResponse {
+	if x != nil {
+		return x.Diffs
+	}
+	return nil
+}
+
+// ListDraftsRequest 列出草稿请求
+type ListDraftsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"` // open, published, abandoned，为空时列出所有草稿
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDraftsRequest) Reset() {
+	*x = ListDraftsRequest{}
+	mi := &file_api_proto_config_proto_msgTypes[49]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDraftsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDraftsRequest) ProtoMessage() {}
+
+func (x *ListDraftsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_config_proto_msgTypes[49]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDraftsRequest.ProtoReflect.Descriptor instead.
+func (*ListDraftsRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_config_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *ListDraftsRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+// ListDraftsResponse 列出草稿响应
+type ListDraftsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Drafts        []*Draft               `protobuf:"bytes,1,rep,name=drafts,proto3" json:"drafts,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDraftsResponse) Reset() {
+	*x = ListDraftsResponse{}
+	mi := &file_api_proto_config_proto_msgTypes[50]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDraftsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDraftsResponse) ProtoMessage() {}
+
+func (x *ListDraftsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_config_proto_msgTypes[50]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDraftsResponse.ProtoReflect.Descriptor instead.
+func (*ListDraftsResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_config_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *ListDraftsResponse) GetDrafts() []*Draft {
+	if x != nil {
+		return x.Drafts
+	}
+	return nil
+}
+
+// DiffChange 一个键的差异
+type DiffChange struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Op            string                 `protobuf:"bytes,2,opt,name=op,proto3" json:"op,omitempty"`          // added, removed, changed
+	Old           string                 `protobuf:"bytes,3,opt,name=old,proto3" json:"old,omitempty"`        // 左侧的值（JSON 文本），新增时为空
+	New           string                 `protobuf:"bytes,4,opt,name=new,proto3" json:"new,omitempty"`        // 右侧的值（JSON 文本），删除时为空
+	Secret        bool                   `protobuf:"varint,5,opt,name=secret,proto3" json:"secret,omitempty"` // 任一侧为敏感配置
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DiffChange) Reset() {
+	*x = DiffChange{}
+	mi := &file_api_proto_config_proto_msgTypes[51]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DiffChange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DiffChange) ProtoMessage() {}
+
+func (x *DiffChange) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_config_proto_msgTypes[51]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DiffChange.ProtoReflect.Descriptor instead.
+func (*DiffChange) Descriptor() ([]byte, []int) {
+	return file_api_proto_config_proto_rawDescGZIP(), []int{51}
+}
+
+func (x *DiffChange) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *DiffChange) GetOp() string {
+	if x != nil {
+		return x.Op
+	}
+	return ""
+}
+
+func (x *DiffChange) GetOld() string {
+	if x != nil {
+		return x.Old
+	}
+	return ""
+}
+
+func (x *DiffChange) GetNew() string {
+	if x != nil {
+		return x.New
+	}
+	return ""
+}
+
+func (x *DiffChange) GetSecret() bool {
+	if x != nil {
+		return x.Secret
+	}
+	return false
+}
+
+// DiffResponse 比较响应
+type DiffResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Left          string                 `protobuf:"bytes,1,opt,name=left,proto3" json:"left,omitempty"`
+	Right         string                 `protobuf:"bytes,2,opt,name=right,proto3" json:"right,omitempty"`
+	Changes       []*DiffChange          `protobuf:"bytes,3,rep,name=changes,proto3" json:"changes,omitempty"` // 按键排序
+	Added         int32                  `protobuf:"varint,4,opt,name=added,proto3" json:"added,omitempty"`
+	Removed       int32                  `protobuf:"varint,5,opt,name=removed,proto3" json:"removed,omitempty"`
+	Changed       int32                  `protobuf:"varint,6,opt,name=changed,proto3" json:"changed,omitempty"`
+	Unified       string                 `protobuf:"bytes,7,opt,name=unified,proto3" json:"unified,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DiffResponse) Reset() {
+	*x = DiffResponse{}
+	mi := &file_api_proto_config_proto_msgTypes[52]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DiffResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DiffResponse) ProtoMessage() {}
+
+func (x *DiffResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_config_proto_msgTypes[52]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DiffResponse.ProtoReflect.Descriptor instead.
+func (*DiffResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_config_proto_rawDescGZIP(), []int{52}
+}
+
+func (x *DiffResponse) GetLeft() string {
+	if x != nil {
+		return x.Left
+	}
+	return ""
+}
+
+func (x *DiffResponse) GetRight() string {
+	if x != nil {
+		return x.Right
+	}
+	return ""
+}
+
+func (x *DiffResponse) GetChanges() []*DiffChange {
+	if x != nil {
+		return x.Changes
+	}
+	return nil
+}
+
+func (x *DiffResponse) GetAdded() int32 {
+	if x != nil {
+		return x.Added
+	}
+	return 0
+}
+
+func (x *DiffResponse) GetRemoved() int32 {
+	if x != nil {
+		return x.Removed
+	}
+	return 0
+}
+
+func (x *DiffResponse) GetChanged() int32 {
+	if x != nil {
+		return x.Changed
+	}
+	return 0
+}
+
+func (x *DiffResponse) GetUnified() string {
+	if x != nil {
+		return x.Unified
+	}
+	return ""
+}
+
+// WatchConfigRequest 监听配置请求
+type WatchConfigRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ServiceName   string                 `protobuf:"bytes,1,opt,name=service_name,json=serviceName,proto3" json:"service_name,omitempty"`        // 可选，与 key 一样按原样匹配，不支持通配符，为空时仅按 selectors 过滤
+	Key           string                 `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`                                           // 可选，如果为空则监听整个服务
+	StartRevision int64                  `protobuf:"varint,3,opt,name=start_revision,json=startRevision,proto3" json:"start_revision,omitempty"` // 可选，从该版本开始（包含）重放变更，用于断线恢复
+	Snapshot      bool                   `protobuf:"varint,4,opt,name=snapshot,proto3" json:"snapshot,omitempty"`                                // 可选，先推送当前配置的快照（SNAPSHOT），以 SNAPSHOT_END 结束后再推送快照版本之后的变更，不能与 start_revision 同时使用
+	Selectors     []string               `protobuf:"bytes,5,rep,name=selectors,proto3" json:"selectors,omitempty"`                               // 可选，附加的订阅条件，格式为 service 或 service/key，支持通配符，如 Heimdallr/Job*；与 service_name 均为空时监听所有服务
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchConfigRequest) Reset() {
+	*x = WatchConfigRequest{}
+	mi := &file_api_proto_config_proto_msgTypes[53]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchConfigRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchConfigRequest) ProtoMessage() {}
+
+func (x *WatchConfigRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_config_proto_msgTypes[53]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchConfigRequest.ProtoReflect.Descriptor instead.
+func (*WatchConfigRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_config_proto_rawDescGZIP(), []int{53}
+}
+
+func (x *WatchConfigRequest) GetServiceName() string {
+	if x != nil {
+		return x.ServiceName
+	}
+	return ""
+}
+
+func (x *WatchConfigRequest) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *WatchConfigRequest) GetStartRevision() int64 {
+	if x != nil {
+		return x.StartRevision
+	}
+	return 0
+}
+
+func (x *WatchConfigRequest) GetSnapshot() bool {
+	if x != nil {
+		return x.Snapshot
+	}
+	return false
+}
+
+func (x *WatchConfigRequest) GetSelectors() []string {
+	if x != nil {
+		return x.Selectors
+	}
+	return nil
+}
+
+// WatchConfigResponse 监听配置响应
+type WatchConfigResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	EventType     string                 `protobuf:"bytes,1,opt,name=event_type,json=eventType,proto3" json:"event_type,omitempty"`    // PUT, DELETE, PROGRESS, SNAPSHOT, SNAPSHOT_END
+	Config        *ConfigItem            `protobuf:"bytes,2,opt,name=config,proto3" json:"config,omitempty"`                           // PUT 为变更后的值，DELETE 为删除前的值，SNAPSHOT 为快照中的值，PROGRESS 与 SNAPSHOT_END 事件为空
+	Revision      int64                  `protobuf:"varint,3,opt,name=revision,proto3" json:"revision,omitempty"`                      // 事件对应的版本号，PROGRESS 事件为当前已同步的版本号，SNAPSHOT 与 SNAPSHOT_END 为快照的版本号
+	PrevConfig    *ConfigItem            `protobuf:"bytes,4,opt,name=prev_config,json=prevConfig,proto3" json:"prev_config,omitempty"` // PUT 事件变更前的值，新建时为空
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchConfigResponse) Reset() {
+	*x = WatchConfigResponse{}
+	mi := &file_api_proto_config_proto_msgTypes[54]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchConfigResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchConfigResponse) ProtoMessage() {}
+
+func (x *WatchConfigResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_config_proto_msgTypes[54]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchConfigResponse.ProtoReflect.Descriptor instead.
+func (*WatchConfigResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_config_proto_rawDescGZIP(), []int{54}
+}
+
+func (x *WatchConfigResponse) GetEventType() string {
+	if x != nil {
+		return x.EventType
+	}
+	return ""
+}
+
+func (x *WatchConfigResponse) GetConfig() *ConfigItem {
+	if x != nil {
+		return x.Config
+	}
+	return nil
+}
+
+func (x *WatchConfigResponse) GetRevision() int64 {
+	if x != nil {
+		return x.Revision
+	}
+	return 0
+}
+
+func (x *WatchConfigResponse) GetPrevConfig() *ConfigItem {
+	if x != nil {
+		return x.PrevConfig
+	}
+	return nil
+}
+
+// ConfigItem 配置项
+type ConfigItem struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Value         string                 `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	ServiceName   string                 `protobuf:"bytes,3,opt,name=service_name,json=serviceName,proto3" json:"service_name,omitempty"`
+	Description   string                 `protobuf:"bytes,4,opt,name=description,proto3" json:"description,omitempty"`
+	Encrypt       bool                   `protobuf:"varint,5,opt,name=encrypt,proto3" json:"encrypt,omitempty"`
+	CreatedAt     int64                  `protobuf:"varint,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     int64                  `protobuf:"varint,7,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConfigItem) Reset() {
+	*x = ConfigItem{}
+	mi := &file_api_proto_config_proto_msgTypes[55]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConfigItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConfigItem) ProtoMessage() {}
+
+func (x *ConfigItem) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_config_proto_msgTypes[55]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConfigItem.ProtoReflect.Descriptor instead.
+func (*ConfigItem) Descriptor() ([]byte, []int) {
+	return file_api_proto_config_proto_rawDescGZIP(), []int{55}
+}
+
+func (x *ConfigItem) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *ConfigItem) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+func (x *ConfigItem) GetServiceName() string {
+	if x != nil {
+		return x.ServiceName
+	}
+	return ""
+}
+
+func (x *ConfigItem) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *ConfigItem) GetEncrypt() bool {
+	if x != nil {
+		return x.Encrypt
+	}
+	return false
+}
+
+func (x *ConfigItem) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+func (x *ConfigItem) GetUpdatedAt() int64 {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return 0
+}
+
+// SessionRequest 会话请求
+type SessionRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Request:
+	//
+	//	*SessionRequest_Hello
+	//	*SessionRequest_Subscribe
+	//	*SessionRequest_Unsubscribe
+	//	*SessionRequest_Ack
+	//	*SessionRequest_Heartbeat
+	Request       isSessionRequest_Request `protobuf_oneof:"request"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SessionRequest) Reset() {
+	*x = SessionRequest{}
+	mi := &file_api_proto_config_proto_msgTypes[56]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SessionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SessionRequest) ProtoMessage() {}
+
+func (x *SessionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_config_proto_msgTypes[56]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SessionRequest.ProtoReflect.Descriptor instead.
+func (*SessionRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_config_proto_rawDescGZIP(), []int{56}
+}
+
+func (x *SessionRequest) GetRequest() isSessionRequest_Request {
+	if x != nil {
+		return x.Request
+	}
+	return nil
+}
+
+func (x *SessionRequest) GetHello() *SessionHello {
+	if x != nil {
+		if x, ok := x.Request.(*SessionRequest_Hello); ok {
+			return x.Hello
+		}
+	}
+	return nil
+}
+
+func (x *SessionRequest) GetSubscribe() *SessionSubscribe {
+	if x != nil {
+		if x, ok := x.Request.(*SessionRequest_Subscribe); ok {
+			return x.Subscribe
+		}
+	}
+	return nil
+}
+
+func (x *SessionRequest) GetUnsubscribe() *SessionUnsubscribe {
+	if x != nil {
+		if x, ok := x.Request.(*SessionRequest_Unsubscribe); ok {
+			return x.Unsubscribe
+		}
+	}
+	return nil
+}
+
+func (x *SessionRequest) GetAck() *SessionAck {
+	if x != nil {
+		if x, ok := x.Request.(*SessionRequest_Ack); ok {
+			return x.Ack
+		}
+	}
+	return nil
+}
+
+func (x *SessionRequest) GetHeartbeat() *SessionHeartbeat {
+	if x != nil {
+		if x, ok := x.Request.(*SessionRequest_Heartbeat); ok {
+			return x.Heartbeat
+		}
+	}
+	return nil
+}
+
+type isSessionRequest_Request interface {
+	isSessionRequest_Request()
+}
+
+type SessionRequest_Hello struct {
+	Hello *SessionHello `protobuf:"bytes,1,opt,name=hello,proto3,oneof"`
+}
+
+type SessionRequest_Subscribe struct {
+	Subscribe *SessionSubscribe `protobuf:"bytes,2,opt,name=subscribe,proto3,oneof"`
+}
+
+type SessionRequest_Unsubscribe struct {
+	Unsubscribe *SessionUnsubscribe `protobuf:"bytes,3,opt,name=unsubscribe,proto3,oneof"`
+}
+
+type SessionRequest_Ack struct {
+	Ack *SessionAck `protobuf:"bytes,4,opt,name=ack,proto3,oneof"`
+}
+
+type SessionRequest_Heartbeat struct {
+	Heartbeat *SessionHeartbeat `protobuf:"bytes,5,opt,name=heartbeat,proto3,oneof"`
+}
+
+func (*SessionRequest_Hello) isSessionRequest_Request() {}
+
+func (*SessionRequest_Subscribe) isSessionRequest_Request() {}
+
+func (*SessionRequest_Unsubscribe) isSessionRequest_Request() {}
+
+func (*SessionRequest_Ack) isSessionRequest_Request() {}
+
+func (*SessionRequest_Heartbeat) isSessionRequest_Request() {}
+
+// SessionHello 会话的首条消息, 声明客户端身份
+type SessionHello struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ClientName    string                 `protobuf:"bytes,1,opt,name=client_name,json=clientName,proto3" json:"client_name,omitempty"`                                                 // 客户端名称，如服务名
+	InstanceId    string                 `protobuf:"bytes,2,opt,name=instance_id,json=instanceId,proto3" json:"instance_id,omitempty"`                                                 // 可选，客户端实例标识
+	Labels        map[string]string      `protobuf:"bytes,3,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // 可选，附加标签
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SessionHello) Reset() {
+	*x = SessionHello{}
+	mi := &file_api_proto_config_proto_msgTypes[57]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SessionHello) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SessionHello) ProtoMessage() {}
+
+func (x *SessionHello) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_config_proto_msgTypes[57]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SessionHello.ProtoReflect.Descriptor instead.
+func (*SessionHello) Descriptor() ([]byte, []int) {
+	return file_api_proto_config_proto_rawDescGZIP(), []int{57}
+}
+
+func (x *SessionHello) GetClientName() string {
+	if x != nil {
+		return x.ClientName
+	}
+	return ""
+}
+
+func (x *SessionHello) GetInstanceId() string {
+	if x != nil {
+		return x.InstanceId
+	}
+	return ""
+}
+
+func (x *SessionHello) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+// SessionSubscribe 订阅配置变化
+type SessionSubscribe struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	SubscriptionId string                 `protobuf:"bytes,1,opt,name=subscription_id,json=subscriptionId,proto3" json:"subscription_id,omitempty"` // 客户端指定的订阅标识，会话内唯一
+	Selectors      []string               `protobuf:"bytes,2,rep,name=selectors,proto3" json:"selectors,omitempty"`                                 // 订阅条件，格式为 service 或 service/key，支持通配符
+	StartRevision  int64                  `protobuf:"varint,3,opt,name=start_revision,json=startRevision,proto3" json:"start_revision,omitempty"`   // 可选，从该版本开始（包含）重放变更
+	Snapshot       bool                   `protobuf:"varint,4,opt,name=snapshot,proto3" json:"snapshot,omitempty"`                                  // 可选，先推送快照，不能与 start_revision 同时使用
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *SessionSubscribe) Reset() {
+	*x = SessionSubscribe{}
+	mi := &file_api_proto_config_proto_msgTypes[58]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SessionSubscribe) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SessionSubscribe) ProtoMessage() {}
+
+func (x *SessionSubscribe) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_config_proto_msgTypes[58]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SessionSubscribe.ProtoReflect.Descriptor instead.
+func (*SessionSubscribe) Descriptor() ([]byte, []int) {
+	return file_api_proto_config_proto_rawDescGZIP(), []int{58}
+}
+
+func (x *SessionSubscribe) GetSubscriptionId() string {
+	if x != nil {
+		return x.SubscriptionId
+	}
+	return ""
+}
+
+func (x *SessionSubscribe) GetSelectors() []string {
+	if x != nil {
+		return x.Selectors
+	}
+	return nil
+}
+
+func (x *SessionSubscribe) GetStartRevision() int64 {
+	if x != nil {
+		return x.StartRevision
+	}
+	return 0
+}
+
+func (x *SessionSubscribe) GetSnapshot() bool {
+	if x != nil {
+		return x.Snapshot
+	}
+	return false
+}
+
+// SessionUnsubscribe 取消订阅
+type SessionUnsubscribe struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	SubscriptionId string                 `protobuf:"bytes,1,opt,name=subscription_id,json=subscriptionId,proto3" json:"subscription_id,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *SessionUnsubscribe) Reset() {
+	*x = SessionUnsubscribe{}
+	mi := &file_api_proto_config_proto_msgTypes[59]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SessionUnsubscribe) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SessionUnsubscribe) ProtoMessage() {}
+
+func (x *SessionUnsubscribe) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_config_proto_msgTypes[59]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SessionUnsubscribe.ProtoReflect.Descriptor instead.
+func (*SessionUnsubscribe) Descriptor() ([]byte, []int) {
+	return file_api_proto_config_proto_rawDescGZIP(), []int{59}
+}
+
+func (x *SessionUnsubscribe) GetSubscriptionId() string {
+	if x != nil {
+		return x.SubscriptionId
+	}
+	return ""
+}
+
+// SessionAck 确认客户端已应用到的版本
+type SessionAck struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Revision      int64                  `protobuf:"varint,1,opt,name=revision,proto3" json:"revision,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SessionAck) Reset() {
+	*x = SessionAck{}
+	mi := &file_api_proto_config_proto_msgTypes[60]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SessionAck) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SessionAck) ProtoMessage() {}
+
+func (x *SessionAck) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_config_proto_msgTypes[60]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SessionAck.ProtoReflect.Descriptor instead.
+func (*SessionAck) Descriptor() ([]byte, []int) {
+	return file_api_proto_config_proto_rawDescGZIP(), []int{60}
+}
+
+func (x *SessionAck) GetRevision() int64 {
+	if x != nil {
+		return x.Revision
+	}
+	return 0
+}
+
+// SessionHeartbeat 心跳
+type SessionHeartbeat struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Timestamp     int64                  `protobuf:"varint,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"` // Unix时间戳（毫秒）
+	Revision      int64                  `protobuf:"varint,2,opt,name=revision,proto3" json:"revision,omitempty"`   // 服务端发送时为当前已分发的版本号
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SessionHeartbeat) Reset() {
+	*x = SessionHeartbeat{}
+	mi := &file_api_proto_config_proto_msgTypes[61]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SessionHeartbeat) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SessionHeartbeat) ProtoMessage() {}
+
+func (x *SessionHeartbeat) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_config_proto_msgTypes[61]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SessionHeartbeat.ProtoReflect.Descriptor instead.
+func (*SessionHeartbeat) Descriptor() ([]byte, []int) {
+	return file_api_proto_config_proto_rawDescGZIP(), []int{61}
+}
+
+func (x *SessionHeartbeat) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *SessionHeartbeat) GetRevision() int64 {
+	if x != nil {
+		return x.Revision
+	}
+	return 0
+}
+
+// SessionResponse 会话响应
+type SessionResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Response:
+	//
+	//	*SessionResponse_Welcome
+	//	*SessionResponse_Event
+	//	*SessionResponse_Subscribed
+	//	*SessionResponse_Unsubscribed
+	//	*SessionResponse_Heartbeat
+	//	*SessionResponse_Error
+	Response      isSessionResponse_Response `protobuf_oneof:"response"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SessionResponse) Reset() {
+	*x = SessionResponse{}
+	mi := &file_api_proto_config_proto_msgTypes[62]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SessionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SessionResponse) ProtoMessage() {}
+
+func (x *SessionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_config_proto_msgTypes[62]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SessionResponse.ProtoReflect.Descriptor instead.
+func (*SessionResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_config_proto_rawDescGZIP(), []int{62}
+}
+
+func (x *SessionResponse) GetResponse() isSessionResponse_Response {
+	if x != nil {
+		return x.Response
+	}
+	return nil
+}
+
+func (x *SessionResponse) GetWelcome() *SessionWelcome {
+	if x != nil {
+		if x, ok := x.Response.(*SessionResponse_Welcome); ok {
+			return x.Welcome
+		}
+	}
+	return nil
+}
+
+func (x *SessionResponse) GetEvent() *SessionEvent {
+	if x != nil {
+		if x, ok := x.Response.(*SessionResponse_Event); ok {
+			return x.Event
+		}
+	}
+	return nil
+}
+
+func (x *SessionResponse) GetSubscribed() *SessionSubscribed {
+	if x != nil {
+		if x, ok := x.Response.(*SessionResponse_Subscribed); ok {
+			return x.Subscribed
+		}
+	}
+	return nil
+}
+
+func (x *SessionResponse) GetUnsubscribed() *SessionUnsubscribed {
+	if x != nil {
+		if x, ok := x.Response.(*SessionResponse_Unsubscribed); ok {
+			return x.Unsubscribed
+		}
+	}
+	return nil
+}
+
+func (x *SessionResponse) GetHeartbeat() *SessionHeartbeat {
+	if x != nil {
+		if x, ok := x.Response.(*SessionResponse_Heartbeat); ok {
+			return x.Heartbeat
+		}
+	}
+	return nil
+}
+
+func (x *SessionResponse) GetError() *SessionError {
+	if x != nil {
+		if x, ok := x.Response.(*SessionResponse_Error); ok {
+			return x.Error
+		}
+	}
+	return nil
+}
+
+type isSessionResponse_Response interface {
+	isSessionResponse_Response()
+}
+
+type SessionResponse_Welcome struct {
+	Welcome *SessionWelcome `protobuf:"bytes,1,opt,name=welcome,proto3,oneof"`
+}
+
+type SessionResponse_Event struct {
+	Event *SessionEvent `protobuf:"bytes,2,opt,name=event,proto3,oneof"`
+}
+
+type SessionResponse_Subscribed struct {
+	Subscribed *SessionSubscribed `protobuf:"bytes,3,opt,name=subscribed,proto3,oneof"`
+}
+
+type SessionResponse_Unsubscribed struct {
+	Unsubscribed *SessionUnsubscribed `protobuf:"bytes,4,opt,name=unsubscribed,proto3,oneof"`
+}
+
+type SessionResponse_Heartbeat struct {
+	Heartbeat *SessionHeartbeat `protobuf:"bytes,5,opt,name=heartbeat,proto3,oneof"`
+}
+
+type SessionResponse_Error struct {
+	Error *SessionError `protobuf:"bytes,6,opt,name=error,proto3,oneof"`
+}
+
+func (*SessionResponse_Welcome) isSessionResponse_Response() {}
+
+func (*SessionResponse_Event) isSessionResponse_Response() {}
+
+func (*SessionResponse_Subscribed) isSessionResponse_Response() {}
+
+func (*SessionResponse_Unsubscribed) isSessionResponse_Response() {}
+
+func (*SessionResponse_Heartbeat) isSessionResponse_Response() {}
+
+func (*SessionResponse_Error) isSessionResponse_Response() {}
+
+// SessionWelcome 会话建立
+type SessionWelcome struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	SessionId         string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	HeartbeatInterval int64                  `protobuf:"varint,2,opt,name=heartbeat_interval,json=heartbeatInterval,proto3" json:"heartbeat_interval,omitempty"` // 心跳间隔（秒）
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *SessionWelcome) Reset() {
+	*x = SessionWelcome{}
+	mi := &file_api_proto_config_proto_msgTypes[63]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SessionWelcome) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SessionWelcome) ProtoMessage() {}
+
+func (x *SessionWelcome) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_config_proto_msgTypes[63]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SessionWelcome.ProtoReflect.Descriptor instead.
+func (*SessionWelcome) Descriptor() ([]byte, []int) {
+	return file_api_proto_config_proto_rawDescGZIP(), []int{63}
+}
+
+func (x *SessionWelcome) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *SessionWelcome) GetHeartbeatInterval() int64 {
+	if x != nil {
+		return x.HeartbeatInterval
+	}
+	return 0
+}
+
+// SessionEvent 订阅的配置变更事件
+type SessionEvent struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	SubscriptionId string                 `protobuf:"bytes,1,opt,name=subscription_id,json=subscriptionId,proto3" json:"subscription_id,omitempty"`
+	Event          *WatchConfigResponse   `protobuf:"bytes,2,opt,name=event,proto3" json:"event,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *SessionEvent) Reset() {
+	*x = SessionEvent{}
+	mi := &file_api_proto_config_proto_msgTypes[64]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SessionEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SessionEvent) ProtoMessage() {}
+
+func (x *SessionEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_config_proto_msgTypes[64]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SessionEvent.ProtoReflect.Descriptor instead.
+func (*SessionEvent) Descriptor() ([]byte, []int) {
+	return file_api_proto_config_proto_rawDescGZIP(), []int{64}
+}
+
+func (x *SessionEvent) GetSubscriptionId() string {
+	if x != nil {
+		return x.SubscriptionId
+	}
+	return ""
+}
+
+func (x *SessionEvent) GetEvent() *WatchConfigResponse {
+	if x != nil {
+		return x.Event
+	}
+	return nil
+}
+
+// SessionSubscribed 订阅已生效
+type SessionSubscribed struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	SubscriptionId string                 `protobuf:"bytes,1,opt,name=subscription_id,json=subscriptionId,proto3" json:"subscription_id,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *SessionSubscribed) Reset() {
+	*x = SessionSubscribed{}
+	mi := &file_api_proto_config_proto_msgTypes[65]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SessionSubscribed) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SessionSubscribed) ProtoMessage() {}
+
+func (x *SessionSubscribed) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_config_proto_msgTypes[65]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SessionSubscribed.ProtoReflect.Descriptor instead.
+func (*SessionSubscribed) Descriptor() ([]byte, []int) {
+	return file_api_proto_config_proto_rawDescGZIP(), []int{65}
+}
+
+func (x *SessionSubscribed) GetSubscriptionId() string {
+	if x != nil {
+		return x.SubscriptionId
+	}
+	return ""
+}
+
+// SessionUnsubscribed 订阅已取消
+type SessionUnsubscribed struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	SubscriptionId string                 `protobuf:"bytes,1,opt,name=subscription_id,json=subscriptionId,proto3" json:"subscription_id,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *SessionUnsubscribed) Reset() {
+	*x = SessionUnsubscribed{}
+	mi := &file_api_proto_config_proto_msgTypes[66]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SessionUnsubscribed) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SessionUnsubscribed) ProtoMessage() {}
+
+func (x *SessionUnsubscribed) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_config_proto_msgTypes[66]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SessionUnsubscribed.ProtoReflect.Descriptor instead.
+func (*SessionUnsubscribed) Descriptor() ([]byte, []int) {
+	return file_api_proto_config_proto_rawDescGZIP(), []int{66}
+}
+
+func (x *SessionUnsubscribed) GetSubscriptionId() string {
+	if x != nil {
+		return x.SubscriptionId
+	}
+	return ""
+}
+
+// SessionError 订阅或请求出错, 订阅出错后即被取消, 会话本身保持
+type SessionError struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	SubscriptionId string                 `protobuf:"bytes,1,opt,name=subscription_id,json=subscriptionId,proto3" json:"subscription_id,omitempty"` // 与订阅无关的错误为空
+	Code           string                 `protobuf:"bytes,2,opt,name=code,proto3" json:"code,omitempty"`                                           // gRPC状态码名称，如 OutOfRange
+	Message        string                 `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *SessionError) Reset() {
+	*x = SessionError{}
+	mi := &file_api_proto_config_proto_msgTypes[67]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SessionError) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SessionError) ProtoMessage() {}
+
+func (x *SessionError) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_config_proto_msgTypes[67]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SessionError.ProtoReflect.Descriptor instead.
+func (*SessionError) Descriptor() ([]byte, []int) {
+	return file_api_proto_config_proto_rawDescGZIP(), []int{67}
+}
+
+func (x *SessionError) GetSubscriptionId() string {
+	if x != nil {
+		return x.SubscriptionId
+	}
+	return ""
+}
+
+func (x *SessionError) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *SessionError) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+// ListClientsRequest 列出客户端请求
+type ListClientsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DriftOnly     bool                   `protobuf:"varint,1,opt,name=drift_only,json=driftOnly,proto3" json:"drift_only,omitempty"` // 只返回版本落后的客户端
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListClientsRequest) Reset() {
+	*x = ListClientsRequest{}
+	mi := &file_api_proto_config_proto_msgTypes[68]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListClientsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListClientsRequest) ProtoMessage() {}
+
+func (x *ListClientsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_config_proto_msgTypes[68]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListClientsRequest.ProtoReflect.Descriptor instead.
+func (*ListClientsRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_config_proto_rawDescGZIP(), []int{68}
+}
+
+func (x *ListClientsRequest) GetDriftOnly() bool {
+	if x != nil {
+		return x.DriftOnly
+	}
+	return false
+}
+
+// ClientInfo 已连接的客户端
+type ClientInfo struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Id                string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Kind              string                 `protobuf:"bytes,2,opt,name=kind,proto3" json:"kind,omitempty"` // watch, sse, session
+	Name              string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"` // 会话为 client_name，其余取自 User-Agent
+	InstanceId        string                 `protobuf:"bytes,4,opt,name=instance_id,json=instanceId,proto3" json:"instance_id,omitempty"`
+	Labels            map[string]string      `protobuf:"bytes,5,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Transport         string                 `protobuf:"bytes,6,opt,name=transport,proto3" json:"transport,omitempty"` // tcp, uds, http
+	Peer              string                 `protobuf:"bytes,7,opt,name=peer,proto3" json:"peer,omitempty"`           // 对端地址，UDS 连接为空
+	Pid               int32                  `protobuf:"varint,8,opt,name=pid,proto3" json:"pid,omitempty"`            // UDS 连接的对端进程号
+	Selectors         []string               `protobuf:"bytes,9,rep,name=selectors,proto3" json:"selectors,omitempty"` // 订阅条件，* 表示所有服务
+	ConnectedAt       int64                  `protobuf:"varint,10,opt,name=connected_at,json=connectedAt,proto3" json:"connected_at,omitempty"`
+	DeliveredRevision int64                  `protobuf:"varint,11,opt,name=delivered_revision,json=deliveredRevision,proto3" json:"delivered_revision,omitempty"` // 已推送的最新版本号
+	AckedRevision     int64                  `protobuf:"varint,12,opt,name=acked_revision,json=ackedRevision,proto3" json:"acked_revision,omitempty"`             // 会话确认已应用的版本号
+	AppliedRevision   int64                  `protobuf:"varint,13,opt,name=applied_revision,json=appliedRevision,proto3" json:"applied_revision,omitempty"`       // 判断漂移使用的版本号，会话为 acked_revision，其余为 delivered_revision
+	RequiredRevision  int64                  `protobuf:"varint,14,opt,name=required_revision,json=requiredRevision,proto3" json:"required_revision,omitempty"`    // 订阅范围内配置的最新版本号
+	BehindServices    []string               `protobuf:"bytes,15,rep,name=behind_services,json=behindServices,proto3" json:"behind_services,omitempty"`           // 版本落后的服务
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *ClientInfo) Reset() {
+	*x = ClientInfo{}
+	mi := &file_api_proto_config_proto_msgTypes[69]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClientInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClientInfo) ProtoMessage() {}
+
+func (x *ClientInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_config_proto_msgTypes[69]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClientInfo.ProtoReflect.Descriptor instead.
+func (*ClientInfo) Descriptor() ([]byte, []int) {
+	return file_api_proto_config_proto_rawDescGZIP(), []int{69}
+}
+
+func (x *ClientInfo) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ClientInfo) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *ClientInfo) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ClientInfo) GetInstanceId() string {
+	if x != nil {
+		return x.InstanceId
+	}
+	return ""
+}
+
+func (x *ClientInfo) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *ClientInfo) GetTransport() string {
+	if x != nil {
+		return x.Transport
+	}
+	return ""
+}
+
+func (x *ClientInfo) GetPeer() string {
+	if x != nil {
+		return x.Peer
+	}
+	return ""
+}
+
+func (x *ClientInfo) GetPid() int32 {
+	if x != nil {
+		return x.Pid
+	}
+	return 0
+}
+
+func (x *ClientInfo) GetSelectors() []string {
+	if x != nil {
+		return x.Selectors
+	}
+	return nil
+}
+
+func (x *ClientInfo) GetConnectedAt() int64 {
+	if x != nil {
+		return x.ConnectedAt
+	}
+	return 0
+}
+
+func (x *ClientInfo) GetDeliveredRevision() int64 {
+	if x != nil {
+		return x.DeliveredRevision
+	}
+	return 0
+}
+
+func (x *ClientInfo) GetAckedRevision() int64 {
+	if x != nil {
+		return x.AckedRevision
+	}
+	return 0
+}
+
+func (x *ClientInfo) GetAppliedRevision() int64 {
+	if x != nil {
+		return x.AppliedRevision
+	}
+	return 0
+}
+
+func (x *ClientInfo) GetRequiredRevision() int64 {
+	if x != nil {
+		return x.RequiredRevision
+	}
+	return 0
+}
+
+func (x *ClientInfo) GetBehindServices() []string {
+	if x != nil {
+		return x.BehindServices
+	}
+	return nil
+}
+
+// ListClientsResponse 列出客户端响应
+type ListClientsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Clients       []*ClientInfo          `protobuf:"bytes,1,rep,name=clients,proto3" json:"clients,omitempty"`
+	Revision      int64                  `protobuf:"varint,2,opt,name=revision,proto3" json:"revision,omitempty"` // 当前版本号
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListClientsResponse) Reset() {
+	*x = ListClientsResponse{}
+	mi := &file_api_proto_config_proto_msgTypes[70]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListClientsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListClientsResponse) ProtoMessage() {}
+
+func (x *ListClientsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_config_proto_msgTypes[70]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListClientsResponse.ProtoReflect.Descriptor instead.
+func (*ListClientsResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_config_proto_rawDescGZIP(), []int{70}
+}
+
+func (x *ListClientsResponse) GetClients() []*ClientInfo {
+	if x != nil {
+		return x.Clients
+	}
+	return nil
+}
+
+func (x *ListClientsResponse) GetRevision() int64 {
+	if x != nil {
+		return x.Revision
+	}
+	return 0
+}
+
+var File_api_proto_config_proto protoreflect.FileDescriptor
+
+const file_api_proto_config_proto_rawDesc = "" +
+	"\n" +
+	"\x16api/proto/config.proto\x12\x06config\"\x99\x01\n" +
+	"\x10SetConfigRequest\x12!\n" +
+	"\fservice_name\x18\x01 \x01(\tR\vserviceName\x12\x10\n" +
+	"\x03key\x18\x02 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x03 \x01(\tR\x05value\x12 \n" +
+	"\vdescription\x18\x04 \x01(\tR\vdescription\x12\x18\n" +
+	"\aencrypt\x18\x05 \x01(\bR\aencrypt\"G\n" +
+	"\x11SetConfigResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"k\n" +
+	"\x10GetConfigRequest\x12!\n" +
+	"\fservice_name\x18\x01 \x01(\tR\vserviceName\x12\x10\n" +
+	"\x03key\x18\x02 \x01(\tR\x03key\x12\"\n" +
+	"\flinearizable\x18\x03 \x01(\bR\flinearizable\"U\n" +
+	"\x11GetConfigResponse\x12*\n" +
+	"\x06config\x18\x01 \x01(\v2\x12.config.ConfigItemR\x06config\x12\x14\n" +
+	"\x05found\x18\x02 \x01(\bR\x05found\"\xb3\x01\n" +
+	"\x18GetServiceConfigsRequest\x12!\n" +
+	"\fservice_name\x18\x01 \x01(\tR\vserviceName\x12\"\n" +
+	"\flinearizable\x18\x02 \x01(\bR\flinearizable\x12\x14\n" +
+	"\x05limit\x18\x03 \x01(\x05R\x05limit\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x04 \x01(\tR\tpageToken\x12\x1b\n" +
+	"\tkeys_only\x18\x05 \x01(\bR\bkeysOnly\"\x9b\x02\n" +
+	"\x19GetServiceConfigsResponse\x12H\n" +
+	"\aconfigs\x18\x01 \x03(\v2..config.GetServiceConfigsResponse.ConfigsEntryR\aconfigs\x12\x12\n" +
+	"\x04keys\x18\x02 \x03(\tR\x04keys\x12&\n" +
+	"\x0fnext_page_token\x18\x03 \x01(\tR\rnextPageToken\x12(\n" +
+	"\x05items\x18\x04 \x03(\v2\x12.config.ConfigItemR\x05items\x1aN\n" +
+	"\fConfigsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12(\n" +
 	"\x05value\x18\x02 \x01(\v2\x12.config.ConfigItemR\x05value:\x028\x01\"J\n" +
 	"\x13DeleteConfigRequest\x12!\n" +
 	"\fservice_name\x18\x01 \x01(\tR\vserviceName\x12\x10\n" +
@@ -869,17 +4740,239 @@ const file_api_proto_config_proto_rawDesc = "" +
 	"\fservice_name\x18\x01 \x01(\tR\vserviceName\"R\n" +
 	"\x1cDeleteServiceConfigsResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
-	"\amessage\x18\x02 \x01(\tR\amessage\"\x15\n" +
-	"\x13ListServicesRequest\"2\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"\x93\x01\n" +
+	"\x13ListServicesRequest\x12\"\n" +
+	"\flinearizable\x18\x01 \x01(\bR\flinearizable\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x03 \x01(\tR\tpageToken\x12#\n" +
+	"\rwith_metadata\x18\x04 \x01(\bR\fwithMetadata\"\x89\x01\n" +
 	"\x14ListServicesResponse\x12\x1a\n" +
-	"\bservices\x18\x01 \x03(\tR\bservices\"I\n" +
+	"\bservices\x18\x01 \x03(\tR\bservices\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\x12-\n" +
+	"\adetails\x18\x03 \x03(\v2\x13.config.ServiceInfoR\adetails\"\xee\x02\n" +
+	"\vServiceMeta\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x02 \x01(\tR\vdescription\x12\x14\n" +
+	"\x05owner\x18\x03 \x01(\tR\x05owner\x12\x18\n" +
+	"\acontact\x18\x04 \x01(\tR\acontact\x127\n" +
+	"\x06labels\x18\x05 \x03(\v2\x1f.config.ServiceMeta.LabelsEntryR\x06labels\x12\x16\n" +
+	"\x06seeded\x18\x06 \x01(\bR\x06seeded\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\a \x01(\x03R\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\b \x01(\x03R\tupdatedAt\x12!\n" +
+	"\tprotected\x18\t \x01(\bH\x00R\tprotected\x88\x01\x01\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01B\f\n" +
+	"\n" +
+	"_protected\"\x8d\x01\n" +
+	"\fServiceStats\x12\x12\n" +
+	"\x04keys\x18\x01 \x01(\x03R\x04keys\x12\x1f\n" +
+	"\vvalue_bytes\x18\x02 \x01(\x03R\n" +
+	"valueBytes\x12#\n" +
+	"\rlast_modified\x18\x03 \x01(\x03R\flastModified\x12#\n" +
+	"\rlast_revision\x18\x04 \x01(\x03R\flastRevision\"~\n" +
+	"\vServiceInfo\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12/\n" +
+	"\bmetadata\x18\x02 \x01(\v2\x13.config.ServiceMetaR\bmetadata\x12*\n" +
+	"\x05stats\x18\x03 \x01(\v2\x14.config.ServiceStatsR\x05stats\"H\n" +
+	"\x15SetServiceMetaRequest\x12/\n" +
+	"\bmetadata\x18\x01 \x01(\v2\x13.config.ServiceMetaR\bmetadata\"I\n" +
+	"\x16SetServiceMetaResponse\x12/\n" +
+	"\bmetadata\x18\x01 \x01(\v2\x13.config.ServiceMetaR\bmetadata\":\n" +
+	"\x15GetServiceMetaRequest\x12!\n" +
+	"\fservice_name\x18\x01 \x01(\tR\vserviceName\"]\n" +
+	"\x16GetServiceMetaResponse\x12-\n" +
+	"\aservice\x18\x01 \x01(\v2\x13.config.ServiceInfoR\aservice\x12\x14\n" +
+	"\x05found\x18\x02 \x01(\bR\x05found\"=\n" +
+	"\x18DeleteServiceMetaRequest\x12!\n" +
+	"\fservice_name\x18\x01 \x01(\tR\vserviceName\"O\n" +
+	"\x19DeleteServiceMetaResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"\xc0\x02\n" +
+	"\rSearchRequest\x12\x14\n" +
+	"\x05query\x18\x01 \x01(\tR\x05query\x12\x14\n" +
+	"\x05regex\x18\x02 \x01(\bR\x05regex\x12\x1f\n" +
+	"\vignore_case\x18\x03 \x01(\bR\n" +
+	"ignoreCase\x12\x16\n" +
+	"\x06fields\x18\x04 \x03(\tR\x06fields\x12\x1a\n" +
+	"\bservices\x18\x05 \x03(\tR\bservices\x129\n" +
+	"\x06labels\x18\x06 \x03(\v2!.config.SearchRequest.LabelsEntryR\x06labels\x12\x14\n" +
+	"\x05limit\x18\a \x01(\x05R\x05limit\x12\"\n" +
+	"\flinearizable\x18\b \x01(\bR\flinearizable\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"`\n" +
+	"\vSearchMatch\x12*\n" +
+	"\x06config\x18\x01 \x01(\v2\x12.config.ConfigItemR\x06config\x12%\n" +
+	"\x0ematched_fields\x18\x02 \x03(\tR\rmatchedFields\"y\n" +
+	"\x0eSearchResponse\x12-\n" +
+	"\amatches\x18\x01 \x03(\v2\x13.config.SearchMatchR\amatches\x12\x1c\n" +
+	"\ttruncated\x18\x02 \x01(\bR\ttruncated\x12\x1a\n" +
+	"\brevision\x18\x03 \x01(\x03R\brevision\"p\n" +
+	"\n" +
+	"SyncConfig\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x12\x18\n" +
+	"\aencrypt\x18\x04 \x01(\bR\aencrypt\"m\n" +
+	"\vSyncService\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12,\n" +
+	"\aconfigs\x18\x02 \x03(\v2\x12.config.SyncConfigR\aconfigs\x12\x1c\n" +
+	"\tunmanaged\x18\x03 \x03(\tR\tunmanaged\"m\n" +
+	"\vSyncRequest\x12/\n" +
+	"\bservices\x18\x01 \x03(\v2\x13.config.SyncServiceR\bservices\x12\x14\n" +
+	"\x05prune\x18\x02 \x01(\bR\x05prune\x12\x17\n" +
+	"\adry_run\x18\x03 \x01(\bR\x06dryRun\"\xcb\x01\n" +
+	"\n" +
+	"SyncChange\x12\x16\n" +
+	"\x06action\x18\x01 \x01(\tR\x06action\x12!\n" +
+	"\fservice_name\x18\x02 \x01(\tR\vserviceName\x12\x10\n" +
+	"\x03key\x18\x03 \x01(\tR\x03key\x12\x16\n" +
+	"\x06fields\x18\x04 \x03(\tR\x06fields\x12*\n" +
+	"\x06config\x18\x05 \x01(\v2\x12.config.ConfigItemR\x06config\x12,\n" +
+	"\acurrent\x18\x06 \x01(\v2\x12.config.ConfigItemR\acurrent\"\x88\x01\n" +
+	"\fSyncResponse\x12,\n" +
+	"\achanges\x18\x01 \x03(\v2\x12.config.SyncChangeR\achanges\x12\x14\n" +
+	"\x05extra\x18\x02 \x03(\tR\x05extra\x12\x1a\n" +
+	"\brevision\x18\x03 \x01(\x03R\brevision\x12\x18\n" +
+	"\aapplied\x18\x04 \x01(\bR\aapplied\"C\n" +
+	"\rExportRequest\x12\x16\n" +
+	"\x06format\x18\x01 \x01(\tR\x06format\x12\x1a\n" +
+	"\bservices\x18\x02 \x03(\tR\bservices\"^\n" +
+	"\x0eExportResponse\x12\x18\n" +
+	"\aarchive\x18\x01 \x01(\fR\aarchive\x12\x16\n" +
+	"\x06format\x18\x02 \x01(\tR\x06format\x12\x1a\n" +
+	"\brevision\x18\x03 \x01(\x03R\brevision\"\x8a\x01\n" +
+	"\rImportRequest\x12\x18\n" +
+	"\aarchive\x18\x01 \x01(\fR\aarchive\x12\x16\n" +
+	"\x06format\x18\x02 \x01(\tR\x06format\x12\x12\n" +
+	"\x04mode\x18\x03 \x01(\tR\x04mode\x12\x1a\n" +
+	"\bservices\x18\x04 \x03(\tR\bservices\x12\x17\n" +
+	"\adry_run\x18\x05 \x01(\bR\x06dryRun\"\xd4\x01\n" +
+	"\x0eImportResponse\x12,\n" +
+	"\achanges\x18\x01 \x03(\v2\x12.config.SyncChangeR\achanges\x12\x14\n" +
+	"\x05extra\x18\x02 \x03(\tR\x05extra\x12\x1a\n" +
+	"\brevision\x18\x03 \x01(\x03R\brevision\x12\x18\n" +
+	"\aapplied\x18\x04 \x01(\bR\aapplied\x12\x12\n" +
+	"\x04mode\x18\x05 \x01(\tR\x04mode\x12\x1a\n" +
+	"\bmetadata\x18\x06 \x03(\tR\bmetadata\x12\x18\n" +
+	"\askipped\x18\a \x03(\tR\askipped\"\x8d\x01\n" +
+	"\n" +
+	"BackupInfo\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1a\n" +
+	"\brevision\x18\x02 \x01(\x03R\brevision\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x03 \x01(\x03R\tcreatedAt\x12\x12\n" +
+	"\x04size\x18\x04 \x01(\x03R\x04size\x12\x1c\n" +
+	"\tencrypted\x18\x05 \x01(\bR\tencrypted\"\x14\n" +
+	"\x12ListBackupsRequest\"C\n" +
+	"\x13ListBackupsResponse\x12,\n" +
+	"\abackups\x18\x01 \x03(\v2\x12.config.BackupInfoR\abackups\"\x15\n" +
+	"\x13CreateBackupRequest\"B\n" +
+	"\x14CreateBackupResponse\x12*\n" +
+	"\x06backup\x18\x01 \x01(\v2\x12.config.BackupInfoR\x06backup\"\x8d\x01\n" +
+	"\x14RestoreBackupRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1a\n" +
+	"\bservices\x18\x02 \x03(\tR\bservices\x12\x12\n" +
+	"\x04mode\x18\x03 \x01(\tR\x04mode\x12\x17\n" +
+	"\adry_run\x18\x04 \x01(\bR\x06dryRun\x12\x18\n" +
+	"\aconfirm\x18\x05 \x01(\bR\aconfirm\"\xb3\x01\n" +
+	"\vDiffRequest\x12\x12\n" +
+	"\x04left\x18\x01 \x01(\tR\x04left\x12\x14\n" +
+	"\x05right\x18\x02 \x01(\tR\x05right\x12!\n" +
+	"\fleft_archive\x18\x03 \x01(\fR\vleftArchive\x12#\n" +
+	"\rright_archive\x18\x04 \x01(\fR\frightArchive\x12\x18\n" +
+	"\aunified\x18\x05 \x01(\bR\aunified\x12\x18\n" +
+	"\acontext\x18\x06 \x01(\x05R\acontext\"\xcc\x01\n" +
+	"\x0eDraftOperation\x12\x0e\n" +
+	"\x02op\x18\x01 \x01(\tR\x02op\x12!\n" +
+	"\fservice_name\x18\x02 \x01(\tR\vserviceName\x12\x10\n" +
+	"\x03key\x18\x03 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x04 \x01(\tR\x05value\x12 \n" +
+	"\vdescription\x18\x05 \x01(\tR\vdescription\x12\x18\n" +
+	"\aencrypt\x18\x06 \x01(\bR\aencrypt\x12#\n" +
+	"\rbase_revision\x18\a \x01(\x03R\fbaseRevision\"L\n" +
+	"\rDraftApproval\x12\x1a\n" +
+	"\bapprover\x18\x01 \x01(\tR\bapprover\x12\x1f\n" +
+	"\vapproved_at\x18\x02 \x01(\x03R\n" +
+	"approvedAt\"\xb3\x03\n" +
+	"\x05Draft\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12 \n" +
+	"\vdescription\x18\x02 \x01(\tR\vdescription\x12\x16\n" +
+	"\x06author\x18\x03 \x01(\tR\x06author\x12\x16\n" +
+	"\x06status\x18\x04 \x01(\tR\x06status\x126\n" +
+	"\n" +
+	"operations\x18\x05 \x03(\v2\x16.config.DraftOperationR\n" +
+	"operations\x123\n" +
+	"\tapprovals\x18\x06 \x03(\v2\x15.config.DraftApprovalR\tapprovals\x12-\n" +
+	"\x12required_approvals\x18\a \x01(\x05R\x11requiredApprovals\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\b \x01(\x03R\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\t \x01(\x03R\tupdatedAt\x12\x1b\n" +
+	"\tclosed_by\x18\n" +
+	" \x01(\tR\bclosedBy\x12\x1b\n" +
+	"\tclosed_at\x18\v \x01(\x03R\bclosedAt\x12\x1a\n" +
+	"\brevision\x18\f \x01(\x03R\brevision\x12\x18\n" +
+	"\aeditors\x18\r \x03(\tR\aeditors\"t\n" +
+	"\x12CreateDraftRequest\x12 \n" +
+	"\vdescription\x18\x01 \x01(\tR\vdescription\x126\n" +
+	"\n" +
+	"operations\x18\x03 \x03(\v2\x16.config.DraftOperationR\n" +
+	"operationsJ\x04\b\x02\x10\x03\"~\n" +
+	"\x12UpdateDraftRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12 \n" +
+	"\vdescription\x18\x02 \x01(\tR\vdescription\x126\n" +
+	"\n" +
+	"operations\x18\x03 \x03(\v2\x16.config.DraftOperationR\n" +
+	"operations\"*\n" +
+	"\x12DraftActionRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02idJ\x04\b\x02\x10\x03\"4\n" +
+	"\rDraftResponse\x12#\n" +
+	"\x05draft\x18\x01 \x01(\v2\r.config.DraftR\x05draft\"i\n" +
+	"\x0fGetDraftRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04diff\x18\x02 \x01(\bR\x04diff\x12\x18\n" +
+	"\aunified\x18\x03 \x01(\bR\aunified\x12\x18\n" +
+	"\acontext\x18\x04 \x01(\x05R\acontext\"c\n" +
+	"\x10GetDraftResponse\x12#\n" +
+	"\x05draft\x18\x01 \x01(\v2\r.config.DraftR\x05draft\x12*\n" +
+	"\x05diffs\x18\x02 \x03(\v2\x14.config.DiffResponseR\x05diffs\"+\n" +
+	"\x11ListDraftsRequest\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\tR\x06status\";\n" +
+	"\x12ListDraftsResponse\x12%\n" +
+	"\x06drafts\x18\x01 \x03(\v2\r.config.DraftR\x06drafts\"j\n" +
+	"\n" +
+	"DiffChange\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x0e\n" +
+	"\x02op\x18\x02 \x01(\tR\x02op\x12\x10\n" +
+	"\x03old\x18\x03 \x01(\tR\x03old\x12\x10\n" +
+	"\x03new\x18\x04 \x01(\tR\x03new\x12\x16\n" +
+	"\x06secret\x18\x05 \x01(\bR\x06secret\"\xca\x01\n" +
+	"\fDiffResponse\x12\x12\n" +
+	"\x04left\x18\x01 \x01(\tR\x04left\x12\x14\n" +
+	"\x05right\x18\x02 \x01(\tR\x05right\x12,\n" +
+	"\achanges\x18\x03 \x03(\v2\x12.config.DiffChangeR\achanges\x12\x14\n" +
+	"\x05added\x18\x04 \x01(\x05R\x05added\x12\x18\n" +
+	"\aremoved\x18\x05 \x01(\x05R\aremoved\x12\x18\n" +
+	"\achanged\x18\x06 \x01(\x05R\achanged\x12\x18\n" +
+	"\aunified\x18\a \x01(\tR\aunified\"\xaa\x01\n" +
 	"\x12WatchConfigRequest\x12!\n" +
 	"\fservice_name\x18\x01 \x01(\tR\vserviceName\x12\x10\n" +
-	"\x03key\x18\x02 \x01(\tR\x03key\"`\n" +
+	"\x03key\x18\x02 \x01(\tR\x03key\x12%\n" +
+	"\x0estart_revision\x18\x03 \x01(\x03R\rstartRevision\x12\x1a\n" +
+	"\bsnapshot\x18\x04 \x01(\bR\bsnapshot\x12\x1c\n" +
+	"\tselectors\x18\x05 \x03(\tR\tselectors\"\xb1\x01\n" +
 	"\x13WatchConfigResponse\x12\x1d\n" +
 	"\n" +
 	"event_type\x18\x01 \x01(\tR\teventType\x12*\n" +
-	"\x06config\x18\x02 \x01(\v2\x12.config.ConfigItemR\x06config\"\xd1\x01\n" +
+	"\x06config\x18\x02 \x01(\v2\x12.config.ConfigItemR\x06config\x12\x1a\n" +
+	"\brevision\x18\x03 \x01(\x03R\brevision\x123\n" +
+	"\vprev_config\x18\x04 \x01(\v2\x12.config.ConfigItemR\n" +
+	"prevConfig\"\xd1\x01\n" +
 	"\n" +
 	"ConfigItem\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
@@ -890,7 +4983,90 @@ const file_api_proto_config_proto_rawDesc = "" +
 	"\n" +
 	"created_at\x18\x06 \x01(\x03R\tcreatedAt\x12\x1d\n" +
 	"\n" +
-	"updated_at\x18\a \x01(\x03R\tupdatedAt2\xb0\x04\n" +
+	"updated_at\x18\a \x01(\x03R\tupdatedAt\"\xa5\x02\n" +
+	"\x0eSessionRequest\x12,\n" +
+	"\x05hello\x18\x01 \x01(\v2\x14.config.SessionHelloH\x00R\x05hello\x128\n" +
+	"\tsubscribe\x18\x02 \x01(\v2\x18.config.SessionSubscribeH\x00R\tsubscribe\x12>\n" +
+	"\vunsubscribe\x18\x03 \x01(\v2\x1a.config.SessionUnsubscribeH\x00R\vunsubscribe\x12&\n" +
+	"\x03ack\x18\x04 \x01(\v2\x12.config.SessionAckH\x00R\x03ack\x128\n" +
+	"\theartbeat\x18\x05 \x01(\v2\x18.config.SessionHeartbeatH\x00R\theartbeatB\t\n" +
+	"\arequest\"\xc5\x01\n" +
+	"\fSessionHello\x12\x1f\n" +
+	"\vclient_name\x18\x01 \x01(\tR\n" +
+	"clientName\x12\x1f\n" +
+	"\vinstance_id\x18\x02 \x01(\tR\n" +
+	"instanceId\x128\n" +
+	"\x06labels\x18\x03 \x03(\v2 .config.SessionHello.LabelsEntryR\x06labels\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x9c\x01\n" +
+	"\x10SessionSubscribe\x12'\n" +
+	"\x0fsubscription_id\x18\x01 \x01(\tR\x0esubscriptionId\x12\x1c\n" +
+	"\tselectors\x18\x02 \x03(\tR\tselectors\x12%\n" +
+	"\x0estart_revision\x18\x03 \x01(\x03R\rstartRevision\x12\x1a\n" +
+	"\bsnapshot\x18\x04 \x01(\bR\bsnapshot\"=\n" +
+	"\x12SessionUnsubscribe\x12'\n" +
+	"\x0fsubscription_id\x18\x01 \x01(\tR\x0esubscriptionId\"(\n" +
+	"\n" +
+	"SessionAck\x12\x1a\n" +
+	"\brevision\x18\x01 \x01(\x03R\brevision\"L\n" +
+	"\x10SessionHeartbeat\x12\x1c\n" +
+	"\ttimestamp\x18\x01 \x01(\x03R\ttimestamp\x12\x1a\n" +
+	"\brevision\x18\x02 \x01(\x03R\brevision\"\xe7\x02\n" +
+	"\x0fSessionResponse\x122\n" +
+	"\awelcome\x18\x01 \x01(\v2\x16.config.SessionWelcomeH\x00R\awelcome\x12,\n" +
+	"\x05event\x18\x02 \x01(\v2\x14.config.SessionEventH\x00R\x05event\x12;\n" +
+	"\n" +
+	"subscribed\x18\x03 \x01(\v2\x19.config.SessionSubscribedH\x00R\n" +
+	"subscribed\x12A\n" +
+	"\funsubscribed\x18\x04 \x01(\v2\x1b.config.SessionUnsubscribedH\x00R\funsubscribed\x128\n" +
+	"\theartbeat\x18\x05 \x01(\v2\x18.config.SessionHeartbeatH\x00R\theartbeat\x12,\n" +
+	"\x05error\x18\x06 \x01(\v2\x14.config.SessionErrorH\x00R\x05errorB\n" +
+	"\n" +
+	"\bresponse\"^\n" +
+	"\x0eSessionWelcome\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x01 \x01(\tR\tsessionId\x12-\n" +
+	"\x12heartbeat_interval\x18\x02 \x01(\x03R\x11heartbeatInterval\"j\n" +
+	"\fSessionEvent\x12'\n" +
+	"\x0fsubscription_id\x18\x01 \x01(\tR\x0esubscriptionId\x121\n" +
+	"\x05event\x18\x02 \x01(\v2\x1b.config.WatchConfigResponseR\x05event\"<\n" +
+	"\x11SessionSubscribed\x12'\n" +
+	"\x0fsubscription_id\x18\x01 \x01(\tR\x0esubscriptionId\">\n" +
+	"\x13SessionUnsubscribed\x12'\n" +
+	"\x0fsubscription_id\x18\x01 \x01(\tR\x0esubscriptionId\"e\n" +
+	"\fSessionError\x12'\n" +
+	"\x0fsubscription_id\x18\x01 \x01(\tR\x0esubscriptionId\x12\x12\n" +
+	"\x04code\x18\x02 \x01(\tR\x04code\x12\x18\n" +
+	"\amessage\x18\x03 \x01(\tR\amessage\"3\n" +
+	"\x12ListClientsRequest\x12\x1d\n" +
+	"\n" +
+	"drift_only\x18\x01 \x01(\bR\tdriftOnly\"\xb4\x04\n" +
+	"\n" +
+	"ClientInfo\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04kind\x18\x02 \x01(\tR\x04kind\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12\x1f\n" +
+	"\vinstance_id\x18\x04 \x01(\tR\n" +
+	"instanceId\x126\n" +
+	"\x06labels\x18\x05 \x03(\v2\x1e.config.ClientInfo.LabelsEntryR\x06labels\x12\x1c\n" +
+	"\ttransport\x18\x06 \x01(\tR\ttransport\x12\x12\n" +
+	"\x04peer\x18\a \x01(\tR\x04peer\x12\x10\n" +
+	"\x03pid\x18\b \x01(\x05R\x03pid\x12\x1c\n" +
+	"\tselectors\x18\t \x03(\tR\tselectors\x12!\n" +
+	"\fconnected_at\x18\n" +
+	" \x01(\x03R\vconnectedAt\x12-\n" +
+	"\x12delivered_revision\x18\v \x01(\x03R\x11deliveredRevision\x12%\n" +
+	"\x0eacked_revision\x18\f \x01(\x03R\rackedRevision\x12)\n" +
+	"\x10applied_revision\x18\r \x01(\x03R\x0fappliedRevision\x12+\n" +
+	"\x11required_revision\x18\x0e \x01(\x03R\x10requiredRevision\x12'\n" +
+	"\x0fbehind_services\x18\x0f \x03(\tR\x0ebehindServices\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"_\n" +
+	"\x13ListClientsResponse\x12,\n" +
+	"\aclients\x18\x01 \x03(\v2\x12.config.ClientInfoR\aclients\x12\x1a\n" +
+	"\brevision\x18\x02 \x01(\x03R\brevision2\xf0\x0e\n" +
 	"\rConfigService\x12@\n" +
 	"\tSetConfig\x12\x18.config.SetConfigRequest\x1a\x19.config.SetConfigResponse\x12@\n" +
 	"\tGetConfig\x12\x18.config.GetConfigRequest\x1a\x19.config.GetConfigResponse\x12X\n" +
@@ -898,7 +5074,28 @@ const file_api_proto_config_proto_rawDesc = "" +
 	"\fDeleteConfig\x12\x1b.config.DeleteConfigRequest\x1a\x1c.config.DeleteConfigResponse\x12a\n" +
 	"\x14DeleteServiceConfigs\x12#.config.DeleteServiceConfigsRequest\x1a$.config.DeleteServiceConfigsResponse\x12I\n" +
 	"\fListServices\x12\x1b.config.ListServicesRequest\x1a\x1c.config.ListServicesResponse\x12H\n" +
-	"\vWatchConfig\x12\x1a.config.WatchConfigRequest\x1a\x1b.config.WatchConfigResponse0\x01B\x1dZ\x1bnidavellir/api/proto/configb\x06proto3"
+	"\vWatchConfig\x12\x1a.config.WatchConfigRequest\x1a\x1b.config.WatchConfigResponse0\x01\x12>\n" +
+	"\aSession\x12\x16.config.SessionRequest\x1a\x17.config.SessionResponse(\x010\x01\x12F\n" +
+	"\vListClients\x12\x1a.config.ListClientsRequest\x1a\x1b.config.ListClientsResponse\x12O\n" +
+	"\x0eSetServiceMeta\x12\x1d.config.SetServiceMetaRequest\x1a\x1e.config.SetServiceMetaResponse\x12O\n" +
+	"\x0eGetServiceMeta\x12\x1d.config.GetServiceMetaRequest\x1a\x1e.config.GetServiceMetaResponse\x12X\n" +
+	"\x11DeleteServiceMeta\x12 .config.DeleteServiceMetaRequest\x1a!.config.DeleteServiceMetaResponse\x127\n" +
+	"\x06Search\x12\x15.config.SearchRequest\x1a\x16.config.SearchResponse\x121\n" +
+	"\x04Sync\x12\x13.config.SyncRequest\x1a\x14.config.SyncResponse\x127\n" +
+	"\x06Export\x12\x15.config.ExportRequest\x1a\x16.config.ExportResponse\x127\n" +
+	"\x06Import\x12\x15.config.ImportRequest\x1a\x16.config.ImportResponse\x12F\n" +
+	"\vListBackups\x12\x1a.config.ListBackupsRequest\x1a\x1b.config.ListBackupsResponse\x12I\n" +
+	"\fCreateBackup\x12\x1b.config.CreateBackupRequest\x1a\x1c.config.CreateBackupResponse\x12E\n" +
+	"\rRestoreBackup\x12\x1c.config.RestoreBackupRequest\x1a\x16.config.ImportResponse\x121\n" +
+	"\x04Diff\x12\x13.config.DiffRequest\x1a\x14.config.DiffResponse\x12@\n" +
+	"\vCreateDraft\x12\x1a.config.CreateDraftRequest\x1a\x15.config.DraftResponse\x12=\n" +
+	"\bGetDraft\x12\x17.config.GetDraftRequest\x1a\x18.config.GetDraftResponse\x12C\n" +
+	"\n" +
+	"ListDrafts\x12\x19.config.ListDraftsRequest\x1a\x1a.config.ListDraftsResponse\x12@\n" +
+	"\vUpdateDraft\x12\x1a.config.UpdateDraftRequest\x1a\x15.config.DraftResponse\x12A\n" +
+	"\fApproveDraft\x12\x1a.config.DraftActionRequest\x1a\x15.config.DraftResponse\x12A\n" +
+	"\fPublishDraft\x12\x1a.config.DraftActionRequest\x1a\x15.config.DraftResponse\x12A\n" +
+	"\fAbandonDraft\x12\x1a.config.DraftActionRequest\x1a\x15.config.DraftResponseB\x1dZ\x1bnidavellir/api/proto/configb\x06proto3"
 
 var (
 	file_api_proto_config_proto_rawDescOnce sync.Once
@@ -912,7 +5109,7 @@ func file_api_proto_config_proto_rawDescGZIP() []byte {
 	return file_api_proto_config_proto_rawDescData
 }
 
-var file_api_proto_config_proto_msgTypes = make([]protoimpl.MessageInfo, 16)
+var file_api_proto_config_proto_msgTypes = make([]protoimpl.MessageInfo, 76)
 var file_api_proto_config_proto_goTypes = []any{
 	(*SetConfigRequest)(nil),             // 0: config.SetConfigRequest
 	(*SetConfigResponse)(nil),            // 1: config.SetConfigResponse
@@ -926,35 +5123,179 @@ var file_api_proto_config_proto_goTypes = []any{
 	(*DeleteServiceConfigsResponse)(nil), // 9: config.DeleteServiceConfigsResponse
 	(*ListServicesRequest)(nil),          // 10: config.ListServicesRequest
 	(*ListServicesResponse)(nil),         // 11: config.ListServicesResponse
-	(*WatchConfigRequest)(nil),           // 12: config.WatchConfigRequest
-	(*WatchConfigResponse)(nil),          // 13: config.WatchConfigResponse
-	(*ConfigItem)(nil),                   // 14: config.ConfigItem
-	nil,                                  // 15: config.GetServiceConfigsResponse.ConfigsEntry
+	(*ServiceMeta)(nil),                  // 12: config.ServiceMeta
+	(*ServiceStats)(nil),                 // 13: config.ServiceStats
+	(*ServiceInfo)(nil),                  // 14: config.ServiceInfo
+	(*SetServiceMetaRequest)(nil),        // 15: config.SetServiceMetaRequest
+	(*SetServiceMetaResponse)(nil),       // 16: config.SetServiceMetaResponse
+	(*GetServiceMetaRequest)(nil),        // 17: config.GetServiceMetaRequest
+	(*GetServiceMetaResponse)(nil),       // 18: config.GetServiceMetaResponse
+	(*DeleteServiceMetaRequest)(nil),     // 19: config.DeleteServiceMetaRequest
+	(*DeleteServiceMetaResponse)(nil),    // 20: config.DeleteServiceMetaResponse
+	(*SearchRequest)(nil),                // 21: config.SearchRequest
+	(*SearchMatch)(nil),                  // 22: config.SearchMatch
+	(*SearchResponse)(nil),               // 23: config.SearchResponse
+	(*SyncConfig)(nil),                   // 24: config.SyncConfig
+	(*SyncService)(nil),                  // 25: config.SyncService
+	(*SyncRequest)(nil),                  // 26: config.SyncRequest
+	(*SyncChange)(nil),                   // 27: config.SyncChange
+	(*SyncResponse)(nil),                 // 28: config.SyncResponse
+	(*ExportRequest)(nil),                // 29: config.ExportRequest
+	(*ExportResponse)(nil),               // 30: config.ExportResponse
+	(*ImportRequest)(nil),                // 31: config.ImportRequest
+	(*ImportResponse)(nil),               // 32: config.ImportResponse
+	(*BackupInfo)(nil),                   // 33: config.BackupInfo
+	(*ListBackupsRequest)(nil),           // 34: config.ListBackupsRequest
+	(*ListBackupsResponse)(nil),          // 35: config.ListBackupsResponse
+	(*CreateBackupRequest)(nil),          // 36: config.CreateBackupRequest
+	(*CreateBackupResponse)(nil),         // 37: config.CreateBackupResponse
+	(*RestoreBackupRequest)(nil),         // 38: config.RestoreBackupRequest
+	(*DiffRequest)(nil),                  // 39: config.DiffRequest
+	(*DraftOperation)(nil),               // 40: config.DraftOperation
+	(*DraftApproval)(nil),                // 41: config.DraftApproval
+	(*Draft)(nil),                        // 42: config.Draft
+	(*CreateDraftRequest)(nil),           // 43: config.CreateDraftRequest
+	(*UpdateDraftRequest)(nil),           // 44: config.UpdateDraftRequest
+	(*DraftActionRequest)(nil),           // 45: config.DraftActionRequest
+	(*DraftResponse)(nil),                // 46: config.DraftResponse
+	(*GetDraftRequest)(nil),              // 47: config.GetDraftRequest
+	(*GetDraftResponse)(nil),             // 48: config.GetDraftResponse
+	(*ListDraftsRequest)(nil),            // 49: config.ListDraftsRequest
+	(*ListDraftsResponse)(nil),           // 50: config.ListDraftsResponse
+	(*DiffChange)(nil),                   // 51: config.DiffChange
+	(*DiffResponse)(nil),                 // 52: config.DiffResponse
+	(*WatchConfigRequest)(nil),           // 53: config.WatchConfigRequest
+	(*WatchConfigResponse)(nil),          // 54: config.WatchConfigResponse
+	(*ConfigItem)(nil),                   // 55: config.ConfigItem
+	(*SessionRequest)(nil),               // 56: config.SessionRequest
+	(*SessionHello)(nil),                 // 57: config.SessionHello
+	(*SessionSubscribe)(nil),             // 58: config.SessionSubscribe
+	(*SessionUnsubscribe)(nil),           // 59: config.SessionUnsubscribe
+	(*SessionAck)(nil),                   // 60: config.SessionAck
+	(*SessionHeartbeat)(nil),             // 61: config.SessionHeartbeat
+	(*SessionResponse)(nil),              // 62: config.SessionResponse
+	(*SessionWelcome)(nil),               // 63: config.SessionWelcome
+	(*SessionEvent)(nil),                 // 64: config.SessionEvent
+	(*SessionSubscribed)(nil),            // 65: config.SessionSubscribed
+	(*SessionUnsubscribed)(nil),          // 66: config.SessionUnsubscribed
+	(*SessionError)(nil),                 // 67: config.SessionError
+	(*ListClientsRequest)(nil),           // 68: config.ListClientsRequest
+	(*ClientInfo)(nil),                   // 69: config.ClientInfo
+	(*ListClientsResponse)(nil),          // 70: config.ListClientsResponse
+	nil,                                  // 71: config.GetServiceConfigsResponse.ConfigsEntry
+	nil,                                  // 72: config.ServiceMeta.LabelsEntry
+	nil,                                  // 73: config.SearchRequest.LabelsEntry
+	nil,                                  // 74: config.SessionHello.LabelsEntry
+	nil,                                  // 75: config.ClientInfo.LabelsEntry
 }
 var file_api_proto_config_proto_depIdxs = []int32{
-	14, // 0: config.GetConfigResponse.config:type_name -> config.ConfigItem
-	15, // 1: config.GetServiceConfigsResponse.configs:type_name -> config.GetServiceConfigsResponse.ConfigsEntry
-	14, // 2: config.WatchConfigResponse.config:type_name -> config.ConfigItem
-	14, // 3: config.GetServiceConfigsResponse.ConfigsEntry.value:type_name -> config.ConfigItem
-	0,  // 4: config.ConfigService.SetConfig:input_type -> config.SetConfigRequest
-	2,  // 5: config.ConfigService.GetConfig:input_type -> config.GetConfigRequest
-	4,  // 6: config.ConfigService.GetServiceConfigs:input_type -> config.GetServiceConfigsRequest
-	6,  // 7: config.ConfigService.DeleteConfig:input_type -> config.DeleteConfigRequest
-	8,  // 8: config.ConfigService.DeleteServiceConfigs:input_type -> config.DeleteServiceConfigsRequest
-	10, // 9: config.ConfigService.ListServices:input_type -> config.ListServicesRequest
-	12, // 10: config.ConfigService.WatchConfig:input_type -> config.WatchConfigRequest
-	1,  // 11: config.ConfigService.SetConfig:output_type -> config.SetConfigResponse
-	3,  // 12: config.ConfigService.GetConfig:output_type -> config.GetConfigResponse
-	5,  // 13: config.ConfigService.GetServiceConfigs:output_type -> config.GetServiceConfigsResponse
-	7,  // 14: config.ConfigService.DeleteConfig:output_type -> config.DeleteConfigResponse
-	9,  // 15: config.ConfigService.DeleteServiceConfigs:output_type -> config.DeleteServiceConfigsResponse
-	11, // 16: config.ConfigService.ListServices:output_type -> config.ListServicesResponse
-	13, // 17: config.ConfigService.WatchConfig:output_type -> config.WatchConfigResponse
-	11, // [11:18] is the sub-list for method output_type
-	4,  // [4:11] is the sub-list for method input_type
-	4,  // [4:4] is the sub-list for extension type_name
-	4,  // [4:4] is the sub-list for extension extendee
-	0,  // [0:4] is the sub-list for field type_name
+	55, // 0: config.GetConfigResponse.config:type_name -> config.ConfigItem
+	71, // 1: config.GetServiceConfigsResponse.configs:type_name -> config.GetServiceConfigsResponse.ConfigsEntry
+	55, // 2: config.GetServiceConfigsResponse.items:type_name -> config.ConfigItem
+	14, // 3: config.ListServicesResponse.details:type_name -> config.ServiceInfo
+	72, // 4: config.ServiceMeta.labels:type_name -> config.ServiceMeta.LabelsEntry
+	12, // 5: config.ServiceInfo.metadata:type_name -> config.ServiceMeta
+	13, // 6: config.ServiceInfo.stats:type_name -> config.ServiceStats
+	12, // 7: config.SetServiceMetaRequest.metadata:type_name -> config.ServiceMeta
+	12, // 8: config.SetServiceMetaResponse.metadata:type_name -> config.ServiceMeta
+	14, // 9: config.GetServiceMetaResponse.service:type_name -> config.ServiceInfo
+	73, // 10: config.SearchRequest.labels:type_name -> config.SearchRequest.LabelsEntry
+	55, // 11: config.SearchMatch.config:type_name -> config.ConfigItem
+	22, // 12: config.SearchResponse.matches:type_name -> config.SearchMatch
+	24, // 13: config.SyncService.configs:type_name -> config.SyncConfig
+	25, // 14: config.SyncRequest.services:type_name -> config.SyncService
+	55, // 15: config.SyncChange.config:type_name -> config.ConfigItem
+	55, // 16: config.SyncChange.current:type_name -> config.ConfigItem
+	27, // 17: config.SyncResponse.changes:type_name -> config.SyncChange
+	27, // 18: config.ImportResponse.changes:type_name -> config.SyncChange
+	33, // 19: config.ListBackupsResponse.backups:type_name -> config.BackupInfo
+	33, // 20: config.CreateBackupResponse.backup:type_name -> config.BackupInfo
+	40, // 21: config.Draft.operations:type_name -> config.DraftOperation
+	41, // 22: config.Draft.approvals:type_name -> config.DraftApproval
+	40, // 23: config.CreateDraftRequest.operations:type_name -> config.DraftOperation
+	40, // 24: config.UpdateDraftRequest.operations:type_name -> config.DraftOperation
+	42, // 25: config.DraftResponse.draft:type_name -> config.Draft
+	42, // 26: config.GetDraftResponse.draft:type_name -> config.Draft
+	52, // 27: config.GetDraftResponse.diffs:type_name -> config.DiffResponse
+	42, // 28: config.ListDraftsResponse.drafts:type_name -> config.Draft
+	51, // 29: config.DiffResponse.changes:type_name -> config.DiffChange
+	55, // 30: config.WatchConfigResponse.config:type_name -> config.ConfigItem
+	55, // 31: config.WatchConfigResponse.prev_config:type_name -> config.ConfigItem
+	57, // 32: config.SessionRequest.hello:type_name -> config.SessionHello
+	58, // 33: config.SessionRequest.subscribe:type_name -> config.SessionSubscribe
+	59, // 34: config.SessionRequest.unsubscribe:type_name -> config.SessionUnsubscribe
+	60, // 35: config.SessionRequest.ack:type_name -> config.SessionAck
+	61, // 36: config.SessionRequest.heartbeat:type_name -> config.SessionHeartbeat
+	74, // 37: config.SessionHello.labels:type_name -> config.SessionHello.LabelsEntry
+	63, // 38: config.SessionResponse.welcome:type_name -> config.SessionWelcome
+	64, // 39: config.SessionResponse.event:type_name -> config.SessionEvent
+	65, // 40: config.SessionResponse.subscribed:type_name -> config.SessionSubscribed
+	66, // 41: config.SessionResponse.unsubscribed:type_name -> config.SessionUnsubscribed
+	61, // 42: config.SessionResponse.heartbeat:type_name -> config.SessionHeartbeat
+	67, // 43: config.SessionResponse.error:type_name -> config.SessionError
+	54, // 44: config.SessionEvent.event:type_name -> config.WatchConfigResponse
+	75, // 45: config.ClientInfo.labels:type_name -> config.ClientInfo.LabelsEntry
+	69, // 46: config.ListClientsResponse.clients:type_name -> config.ClientInfo
+	55, // 47: config.GetServiceConfigsResponse.ConfigsEntry.value:type_name -> config.ConfigItem
+	0,  // 48: config.ConfigService.SetConfig:input_type -> config.SetConfigRequest
+	2,  // 49: config.ConfigService.GetConfig:input_type -> config.GetConfigRequest
+	4,  // 50: config.ConfigService.GetServiceConfigs:input_type -> config.GetServiceConfigsRequest
+	6,  // 51: config.ConfigService.DeleteConfig:input_type -> config.DeleteConfigRequest
+	8,  // 52: config.ConfigService.DeleteServiceConfigs:input_type -> config.DeleteServiceConfigsRequest
+	10, // 53: config.ConfigService.ListServices:input_type -> config.ListServicesRequest
+	53, // 54: config.ConfigService.WatchConfig:input_type -> config.WatchConfigRequest
+	56, // 55: config.ConfigService.Session:input_type -> config.SessionRequest
+	68, // 56: config.ConfigService.ListClients:input_type -> config.ListClientsRequest
+	15, // 57: config.ConfigService.SetServiceMeta:input_type -> config.SetServiceMetaRequest
+	17, // 58: config.ConfigService.GetServiceMeta:input_type -> config.GetServiceMetaRequest
+	19, // 59: config.ConfigService.DeleteServiceMeta:input_type -> config.DeleteServiceMetaRequest
+	21, // 60: config.ConfigService.Search:input_type -> config.SearchRequest
+	26, // 61: config.ConfigService.Sync:input_type -> config.SyncRequest
+	29, // 62: config.ConfigService.Export:input_type -> config.ExportRequest
+	31, // 63: config.ConfigService.Import:input_type -> config.ImportRequest
+	34, // 64: config.ConfigService.ListBackups:input_type -> config.ListBackupsRequest
+	36, // 65: config.ConfigService.CreateBackup:input_type -> config.CreateBackupRequest
+	38, // 66: config.ConfigService.RestoreBackup:input_type -> config.RestoreBackupRequest
+	39, // 67: config.ConfigService.Diff:input_type -> config.DiffRequest
+	43, // 68: config.ConfigService.CreateDraft:input_type -> config.CreateDraftRequest
+	47, // 69: config.ConfigService.GetDraft:input_type -> config.GetDraftRequest
+	49, // 70: config.ConfigService.ListDrafts:input_type -> config.ListDraftsRequest
+	44, // 71: config.ConfigService.UpdateDraft:input_type -> config.UpdateDraftRequest
+	45, // 72: config.ConfigService.ApproveDraft:input_type -> config.DraftActionRequest
+	45, // 73: config.ConfigService.PublishDraft:input_type -> config.DraftActionRequest
+	45, // 74: config.ConfigService.AbandonDraft:input_type -> config.DraftActionRequest
+	1,  // 75: config.ConfigService.SetConfig:output_type -> config.SetConfigResponse
+	3,  // 76: config.ConfigService.GetConfig:output_type -> config.GetConfigResponse
+	5,  // 77: config.ConfigService.GetServiceConfigs:output_type -> config.GetServiceConfigsResponse
+	7,  // 78: config.ConfigService.DeleteConfig:output_type -> config.DeleteConfigResponse
+	9,  // 79: config.ConfigService.DeleteServiceConfigs:output_type -> config.DeleteServiceConfigsResponse
+	11, // 80: config.ConfigService.ListServices:output_type -> config.ListServicesResponse
+	54, // 81: config.ConfigService.WatchConfig:output_type -> config.WatchConfigResponse
+	62, // 82: config.ConfigService.Session:output_type -> config.SessionResponse
+	70, // 83: config.ConfigService.ListClients:output_type -> config.ListClientsResponse
+	16, // 84: config.ConfigService.SetServiceMeta:output_type -> config.SetServiceMetaResponse
+	18, // 85: config.ConfigService.GetServiceMeta:output_type -> config.GetServiceMetaResponse
+	20, // 86: config.ConfigService.DeleteServiceMeta:output_type -> config.DeleteServiceMetaResponse
+	23, // 87: config.ConfigService.Search:output_type -> config.SearchResponse
+	28, // 88: config.ConfigService.Sync:output_type -> config.SyncResponse
+	30, // 89: config.ConfigService.Export:output_type -> config.ExportResponse
+	32, // 90: config.ConfigService.Import:output_type -> config.ImportResponse
+	35, // 91: config.ConfigService.ListBackups:output_type -> config.ListBackupsResponse
+	37, // 92: config.ConfigService.CreateBackup:output_type -> config.CreateBackupResponse
+	32, // 93: config.ConfigService.RestoreBackup:output_type -> config.ImportResponse
+	52, // 94: config.ConfigService.Diff:output_type -> config.DiffResponse
+	46, // 95: config.ConfigService.CreateDraft:output_type -> config.DraftResponse
+	48, // 96: config.ConfigService.GetDraft:output_type -> config.GetDraftResponse
+	50, // 97: config.ConfigService.ListDrafts:output_type -> config.ListDraftsResponse
+	46, // 98: config.ConfigService.UpdateDraft:output_type -> config.DraftResponse
+	46, // 99: config.ConfigService.ApproveDraft:output_type -> config.DraftResponse
+	46, // 100: config.ConfigService.PublishDraft:output_type -> config.DraftResponse
+	46, // 101: config.ConfigService.AbandonDraft:output_type -> config.DraftResponse
+	75, // [75:102] is the sub-list for method output_type
+	48, // [48:75] is the sub-list for method input_type
+	48, // [48:48] is the sub-list for extension type_name
+	48, // [48:48] is the sub-list for extension extendee
+	0,  // [0:48] is the sub-list for field type_name
 }
 
 func init() { file_api_proto_config_proto_init() }
@@ -962,13 +5303,29 @@ func file_api_proto_config_proto_init() {
 	if File_api_proto_config_proto != nil {
 		return
 	}
+	file_api_proto_config_proto_msgTypes[12].OneofWrappers = []any{}
+	file_api_proto_config_proto_msgTypes[56].OneofWrappers = []any{
+		(*SessionRequest_Hello)(nil),
+		(*SessionRequest_Subscribe)(nil),
+		(*SessionRequest_Unsubscribe)(nil),
+		(*SessionRequest_Ack)(nil),
+		(*SessionRequest_Heartbeat)(nil),
+	}
+	file_api_proto_config_proto_msgTypes[62].OneofWrappers = []any{
+		(*SessionResponse_Welcome)(nil),
+		(*SessionResponse_Event)(nil),
+		(*SessionResponse_Subscribed)(nil),
+		(*SessionResponse_Unsubscribed)(nil),
+		(*SessionResponse_Heartbeat)(nil),
+		(*SessionResponse_Error)(nil),
+	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_proto_config_proto_rawDesc), len(file_api_proto_config_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   16,
+			NumMessages:   76,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -26,6 +26,26 @@ const (
 	ConfigService_DeleteServiceConfigs_FullMethodName = "/config.ConfigService/DeleteServiceConfigs"
 	ConfigService_ListServices_FullMethodName         = "/config.ConfigService/ListServices"
 	ConfigService_WatchConfig_FullMethodName          = "/config.ConfigService/WatchConfig"
+	ConfigService_Session_FullMethodName              = "/config.ConfigService/Session"
+	ConfigService_ListClients_FullMethodName          = "/config.ConfigService/ListClients"
+	ConfigService_SetServiceMeta_FullMethodName       = "/config.ConfigService/SetServiceMeta"
+	ConfigService_GetServiceMeta_FullMethodName       = "/config.ConfigService/GetServiceMeta"
+	ConfigService_DeleteServiceMeta_FullMethodName    = "/config.ConfigService/DeleteServiceMeta"
+	ConfigService_Search_FullMethodName               = "/config.ConfigService/Search"
+	ConfigService_Sync_FullMethodName                 = "/config.ConfigService/Sync"
+	ConfigService_Export_FullMethodName               = "/config.ConfigService/Export"
+	ConfigService_Import_FullMethodName               = "/config.ConfigService/Import"
+	ConfigService_ListBackups_FullMethodName          = "/config.ConfigService/ListBackups"
+	ConfigService_CreateBackup_FullMethodName         = "/config.ConfigService/CreateBackup"
+	ConfigService_RestoreBackup_FullMethodName        = "/config.ConfigService/RestoreBackup"
+	ConfigService_Diff_FullMethodName                 = "/config.ConfigService/Diff"
+	ConfigService_CreateDraft_FullMethodName          = "/config.ConfigService/CreateDraft"
+	ConfigService_GetDraft_FullMethodName             = "/config.ConfigService/GetDraft"
+	ConfigService_ListDrafts_FullMethodName           = "/config.ConfigService/ListDrafts"
+	ConfigService_UpdateDraft_FullMethodName          = "/config.ConfigService/UpdateDraft"
+	ConfigService_ApproveDraft_FullMethodName         = "/config.ConfigService/ApproveDraft"
+	ConfigService_PublishDraft_FullMethodName         = "/config.ConfigService/PublishDraft"
+	ConfigService_AbandonDraft_FullMethodName         = "/config.ConfigService/AbandonDraft"
 )
 
 // ConfigServiceClient is the client API for ConfigService service.
@@ -47,7 +67,55 @@ type ConfigServiceClient interface {
 	// ListServices 列出所有服务
 	ListServices(ctx context.Context, in *ListServicesRequest, opts ...grpc.CallOption) (*ListServicesResponse, error)
 	// WatchConfig 监听配置变化
+	// start_revision 已被压缩时返回 OUT_OF_RANGE, 客户端需要全量同步后重新监听
 	WatchConfig(ctx context.Context, in *WatchConfigRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WatchConfigResponse], error)
+	// Session 长连接会话, 供Twig等本地客户端使用
+	// 客户端首条消息必须为 hello, 之后可随时订阅、取消订阅、确认已应用的版本并发送心跳
+	// 超过 3 个心跳间隔未收到客户端消息时, 服务端以 DEADLINE_EXCEEDED 关闭会话
+	Session(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[SessionRequest, SessionResponse], error)
+	// ListClients 列出已连接的客户端（监听流、SSE 与会话）及其版本漂移情况
+	ListClients(ctx context.Context, in *ListClientsRequest, opts ...grpc.CallOption) (*ListClientsResponse, error)
+	// SetServiceMeta 创建或更新服务元数据，期间元数据被并发修改时返回 ABORTED
+	SetServiceMeta(ctx context.Context, in *SetServiceMetaRequest, opts ...grpc.CallOption) (*SetServiceMetaResponse, error)
+	// GetServiceMeta 获取服务元数据与统计信息
+	GetServiceMeta(ctx context.Context, in *GetServiceMetaRequest, opts ...grpc.CallOption) (*GetServiceMetaResponse, error)
+	// DeleteServiceMeta 删除服务元数据，服务的配置不受影响，期间元数据被并发修改时返回 ABORTED
+	DeleteServiceMeta(ctx context.Context, in *DeleteServiceMetaRequest, opts ...grpc.CallOption) (*DeleteServiceMetaResponse, error)
+	// Search 按子串或正则表达式搜索配置的键、值与描述，敏感配置的值仅对授权的调用方参与匹配
+	Search(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (*SearchResponse, error)
+	// Sync 将声明的服务配置同步到存储，返回新建、更新与删除的计划，dry_run 时不写入
+	// 写入分批在事务中执行，期间配置被并发修改时返回 ABORTED
+	Sync(ctx context.Context, in *SyncRequest, opts ...grpc.CallOption) (*SyncResponse, error)
+	// Export 在同一版本下导出所有服务的配置与元数据，敏感配置的值以归档密钥加密，未授权或未配置归档密钥时不导出
+	Export(ctx context.Context, in *ExportRequest, opts ...grpc.CallOption) (*ExportResponse, error)
+	// Import 导入 Export 生成的归档，merge 模式只新建与更新，replace 模式同时删除归档中没有的配置
+	// 需要审批人的令牌，写入与 Sync 相同，期间配置被并发修改时返回 ABORTED
+	Import(ctx context.Context, in *ImportRequest, opts ...grpc.CallOption) (*ImportResponse, error)
+	// ListBackups 列出本地备份，最新的在前，未配置备份目录时返回 NOT_FOUND，备份相关的接口都需要审批人的令牌
+	ListBackups(ctx context.Context, in *ListBackupsRequest, opts ...grpc.CallOption) (*ListBackupsResponse, error)
+	// CreateBackup 立即创建备份
+	CreateBackup(ctx context.Context, in *CreateBackupRequest, opts ...grpc.CallOption) (*CreateBackupResponse, error)
+	// RestoreBackup 从备份恢复配置，默认 replace 模式恢复到备份时的状态，confirm 为 true 时才写入，否则只返回计划
+	// 写入前先创建当前状态的备份
+	RestoreBackup(ctx context.Context, in *RestoreBackupRequest, opts ...grpc.CallOption) (*ImportResponse, error)
+	// Diff 比较两侧服务的配置，每一侧可以是当前配置、历史版本、其他环境、备份或上传的归档
+	// 未授权时敏感配置的值为 ******
+	Diff(ctx context.Context, in *DiffRequest, opts ...grpc.CallOption) (*DiffResponse, error)
+	// CreateDraft 创建草稿，需要作者或审批人的令牌，草稿中的操作在发布前不影响配置
+	CreateDraft(ctx context.Context, in *CreateDraftRequest, opts ...grpc.CallOption) (*DraftResponse, error)
+	// GetDraft 获取草稿，可同时返回每个服务当前配置与发布后配置的差异
+	GetDraft(ctx context.Context, in *GetDraftRequest, opts ...grpc.CallOption) (*GetDraftResponse, error)
+	// ListDrafts 列出草稿，最新的在前
+	ListDrafts(ctx context.Context, in *ListDraftsRequest, opts ...grpc.CallOption) (*ListDraftsResponse, error)
+	// UpdateDraft 修改草稿并以当前配置为基准，需要作者或审批人的令牌，已有的审批作废
+	UpdateDraft(ctx context.Context, in *UpdateDraftRequest, opts ...grpc.CallOption) (*DraftResponse, error)
+	// ApproveDraft 审批草稿，需要审批人的令牌，草稿的作者和修改过草稿的人不能审批
+	ApproveDraft(ctx context.Context, in *DraftActionRequest, opts ...grpc.CallOption) (*DraftResponse, error)
+	// PublishDraft 发布草稿，需要审批人的令牌，所有操作在一个事务中写入
+	// 审批数不足时返回 FAILED_PRECONDITION，涉及的配置在草稿更新后被修改时返回 ABORTED
+	PublishDraft(ctx context.Context, in *DraftActionRequest, opts ...grpc.CallOption) (*DraftResponse, error)
+	// AbandonDraft 放弃草稿，需要作者或审批人的令牌
+	AbandonDraft(ctx context.Context, in *DraftActionRequest, opts ...grpc.CallOption) (*DraftResponse, error)
 }
 
 type configServiceClient struct {
@@ -137,6 +205,209 @@ func (c *configServiceClient) WatchConfig(ctx context.Context, in *WatchConfigRe
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type ConfigService_WatchConfigClient = grpc.ServerStreamingClient[WatchConfigResponse]
 
+func (c *configServiceClient) Session(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[SessionRequest, SessionResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &ConfigService_ServiceDesc.Streams[1], ConfigService_Session_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[SessionRequest, SessionResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ConfigService_SessionClient = grpc.BidiStreamingClient[SessionRequest, SessionResponse]
+
+func (c *configServiceClient) ListClients(ctx context.Context, in *ListClientsRequest, opts ...grpc.CallOption) (*ListClientsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListClientsResponse)
+	err := c.cc.Invoke(ctx, ConfigService_ListClients_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *configServiceClient) SetServiceMeta(ctx context.Context, in *SetServiceMetaRequest, opts ...grpc.CallOption) (*SetServiceMetaResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetServiceMetaResponse)
+	err := c.cc.Invoke(ctx, ConfigService_SetServiceMeta_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *configServiceClient) GetServiceMeta(ctx context.Context, in *GetServiceMetaRequest, opts ...grpc.CallOption) (*GetServiceMetaResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetServiceMetaResponse)
+	err := c.cc.Invoke(ctx, ConfigService_GetServiceMeta_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *configServiceClient) DeleteServiceMeta(ctx context.Context, in *DeleteServiceMetaRequest, opts ...grpc.CallOption) (*DeleteServiceMetaResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteServiceMetaResponse)
+	err := c.cc.Invoke(ctx, ConfigService_DeleteServiceMeta_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *configServiceClient) Search(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (*SearchResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SearchResponse)
+	err := c.cc.Invoke(ctx, ConfigService_Search_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *configServiceClient) Sync(ctx context.Context, in *SyncRequest, opts ...grpc.CallOption) (*SyncResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SyncResponse)
+	err := c.cc.Invoke(ctx, ConfigService_Sync_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *configServiceClient) Export(ctx context.Context, in *ExportRequest, opts ...grpc.CallOption) (*ExportResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ExportResponse)
+	err := c.cc.Invoke(ctx, ConfigService_Export_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *configServiceClient) Import(ctx context.Context, in *ImportRequest, opts ...grpc.CallOption) (*ImportResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ImportResponse)
+	err := c.cc.Invoke(ctx, ConfigService_Import_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *configServiceClient) ListBackups(ctx context.Context, in *ListBackupsRequest, opts ...grpc.CallOption) (*ListBackupsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListBackupsResponse)
+	err := c.cc.Invoke(ctx, ConfigService_ListBackups_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *configServiceClient) CreateBackup(ctx context.Context, in *CreateBackupRequest, opts ...grpc.CallOption) (*CreateBackupResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateBackupResponse)
+	err := c.cc.Invoke(ctx, ConfigService_CreateBackup_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *configServiceClient) RestoreBackup(ctx context.Context, in *RestoreBackupRequest, opts ...grpc.CallOption) (*ImportResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ImportResponse)
+	err := c.cc.Invoke(ctx, ConfigService_RestoreBackup_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *configServiceClient) Diff(ctx context.Context, in *DiffRequest, opts ...grpc.CallOption) (*DiffResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DiffResponse)
+	err := c.cc.Invoke(ctx, ConfigService_Diff_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *configServiceClient) CreateDraft(ctx context.Context, in *CreateDraftRequest, opts ...grpc.CallOption) (*DraftResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DraftResponse)
+	err := c.cc.Invoke(ctx, ConfigService_CreateDraft_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *configServiceClient) GetDraft(ctx context.Context, in *GetDraftRequest, opts ...grpc.CallOption) (*GetDraftResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetDraftResponse)
+	err := c.cc.Invoke(ctx, ConfigService_GetDraft_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *configServiceClient) ListDrafts(ctx context.Context, in *ListDraftsRequest, opts ...grpc.CallOption) (*ListDraftsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListDraftsResponse)
+	err := c.cc.Invoke(ctx, ConfigService_ListDrafts_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *configServiceClient) UpdateDraft(ctx context.Context, in *UpdateDraftRequest, opts ...grpc.CallOption) (*DraftResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DraftResponse)
+	err := c.cc.Invoke(ctx, ConfigService_UpdateDraft_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *configServiceClient) ApproveDraft(ctx context.Context, in *DraftActionRequest, opts ...grpc.CallOption) (*DraftResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DraftResponse)
+	err := c.cc.Invoke(ctx, ConfigService_ApproveDraft_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *configServiceClient) PublishDraft(ctx context.Context, in *DraftActionRequest, opts ...grpc.CallOption) (*DraftResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DraftResponse)
+	err := c.cc.Invoke(ctx, ConfigService_PublishDraft_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *configServiceClient) AbandonDraft(ctx context.Context, in *DraftActionRequest, opts ...grpc.CallOption) (*DraftResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DraftResponse)
+	err := c.cc.Invoke(ctx, ConfigService_AbandonDraft_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ConfigServiceServer is the server API for ConfigService service.
 // All implementations must embed UnimplementedConfigServiceServer
 // for forward compatibility.
@@ -156,7 +427,55 @@ type ConfigServiceServer interface {
 	// ListServices 列出所有服务
 	ListServices(context.Context, *ListServicesRequest) (*ListServicesResponse, error)
 	// WatchConfig 监听配置变化
+	// start_revision 已被压缩时返回 OUT_OF_RANGE, 客户端需要全量同步后重新监听
 	WatchConfig(*WatchConfigRequest, grpc.ServerStreamingServer[WatchConfigResponse]) error
+	// Session 长连接会话, 供Twig等本地客户端使用
+	// 客户端首条消息必须为 hello, 之后可随时订阅、取消订阅、确认已应用的版本并发送心跳
+	// 超过 3 个心跳间隔未收到客户端消息时, 服务端以 DEADLINE_EXCEEDED 关闭会话
+	Session(grpc.BidiStreamingServer[SessionRequest, SessionResponse]) error
+	// ListClients 列出已连接的客户端（监听流、SSE 与会话）及其版本漂移情况
+	ListClients(context.Context, *ListClientsRequest) (*ListClientsResponse, error)
+	// SetServiceMeta 创建或更新服务元数据，期间元数据被并发修改时返回 ABORTED
+	SetServiceMeta(context.Context, *SetServiceMetaRequest) (*SetServiceMetaResponse, error)
+	// GetServiceMeta 获取服务元数据与统计信息
+	GetServiceMeta(context.Context, *GetServiceMetaRequest) (*GetServiceMetaResponse, error)
+	// DeleteServiceMeta 删除服务元数据，服务的配置不受影响，期间元数据被并发修改时返回 ABORTED
+	DeleteServiceMeta(context.Context, *DeleteServiceMetaRequest) (*DeleteServiceMetaResponse, error)
+	// Search 按子串或正则表达式搜索配置的键、值与描述，敏感配置的值仅对授权的调用方参与匹配
+	Search(context.Context, *SearchRequest) (*SearchResponse, error)
+	// Sync 将声明的服务配置同步到存储，返回新建、更新与删除的计划，dry_run 时不写入
+	// 写入分批在事务中执行，期间配置被并发修改时返回 ABORTED
+	Sync(context.Context, *SyncRequest) (*SyncResponse, error)
+	// Export 在同一版本下导出所有服务的配置与元数据，敏感配置的值以归档密钥加密，未授权或未配置归档密钥时不导出
+	Export(context.Context, *ExportRequest) (*ExportResponse, error)
+	// Import 导入 Export 生成的归档，merge 模式只新建与更新，replace 模式同时删除归档中没有的配置
+	// 需要审批人的令牌，写入与 Sync 相同，期间配置被并发修改时返回 ABORTED
+	Import(context.Context, *ImportRequest) (*ImportResponse, error)
+	// ListBackups 列出本地备份，最新的在前，未配置备份目录时返回 NOT_FOUND，备份相关的接口都需要审批人的令牌
+	ListBackups(context.Context, *ListBackupsRequest) (*ListBackupsResponse, error)
+	// CreateBackup 立即创建备份
+	CreateBackup(context.Context, *CreateBackupRequest) (*CreateBackupResponse, error)
+	// RestoreBackup 从备份恢复配置，默认 replace 模式恢复到备份时的状态，confirm 为 true 时才写入，否则只返回计划
+	// 写入前先创建当前状态的备份
+	RestoreBackup(context.Context, *RestoreBackupRequest) (*ImportResponse, error)
+	// Diff 比较两侧服务的配置，每一侧可以是当前配置、历史版本、其他环境、备份或上传的归档
+	// 未授权时敏感配置的值为 ******
+	Diff(context.Context, *DiffRequest) (*DiffResponse, error)
+	// CreateDraft 创建草稿，需要作者或审批人的令牌，草稿中的操作在发布前不影响配置
+	CreateDraft(context.Context, *CreateDraftRequest) (*DraftResponse, error)
+	// GetDraft 获取草稿，可同时返回每个服务当前配置与发布后配置的差异
+	GetDraft(context.Context, *GetDraftRequest) (*GetDraftResponse, error)
+	// ListDrafts 列出草稿，最新的在前
+	ListDrafts(context.Context, *ListDraftsRequest) (*ListDraftsResponse, error)
+	// UpdateDraft 修改草稿并以当前配置为基准，需要作者或审批人的令牌，已有的审批作废
+	UpdateDraft(context.Context, *UpdateDraftRequest) (*DraftResponse, error)
+	// ApproveDraft 审批草稿，需要审批人的令牌，草稿的作者和修改过草稿的人不能审批
+	ApproveDraft(context.Context, *DraftActionRequest) (*DraftResponse, error)
+	// PublishDraft 发布草稿，需要审批人的令牌，所有操作在一个事务中写入
+	// 审批数不足时返回 FAILED_PRECONDITION，涉及的配置在草稿更新后被修改时返回 ABORTED
+	PublishDraft(context.Context, *DraftActionRequest) (*DraftResponse, error)
+	// AbandonDraft 放弃草稿，需要作者或审批人的令牌
+	AbandonDraft(context.Context, *DraftActionRequest) (*DraftResponse, error)
 	mustEmbedUnimplementedConfigServiceServer()
 }
 
@@ -188,6 +507,66 @@ func (UnimplementedConfigServiceServer) ListServices(context.Context, *ListServi
 func (UnimplementedConfigServiceServer) WatchConfig(*WatchConfigRequest, grpc.ServerStreamingServer[WatchConfigResponse]) error {
 	return status.Errorf(codes.Unimplemented, "method WatchConfig not implemented")
 }
+func (UnimplementedConfigServiceServer) Session(grpc.BidiStreamingServer[SessionRequest, SessionResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method Session not implemented")
+}
+func (UnimplementedConfigServiceServer) ListClients(context.Context, *ListClientsRequest) (*ListClientsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListClients not implemented")
+}
+func (UnimplementedConfigServiceServer) SetServiceMeta(context.Context, *SetServiceMetaRequest) (*SetServiceMetaResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetServiceMeta not implemented")
+}
+func (UnimplementedConfigServiceServer) GetServiceMeta(context.Context, *GetServiceMetaRequest) (*GetServiceMetaResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetServiceMeta not implemented")
+}
+func (UnimplementedConfigServiceServer) DeleteServiceMeta(context.Context, *DeleteServiceMetaRequest) (*DeleteServiceMetaResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteServiceMeta not implemented")
+}
+func (UnimplementedConfigServiceServer) Search(context.Context, *SearchRequest) (*SearchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Search not implemented")
+}
+func (UnimplementedConfigServiceServer) Sync(context.Context, *SyncRequest) (*SyncResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Sync not implemented")
+}
+func (UnimplementedConfigServiceServer) Export(context.Context, *ExportRequest) (*ExportResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Export not implemented")
+}
+func (UnimplementedConfigServiceServer) Import(context.Context, *ImportRequest) (*ImportResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Import not implemented")
+}
+func (UnimplementedConfigServiceServer) ListBackups(context.Context, *ListBackupsRequest) (*ListBackupsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListBackups not implemented")
+}
+func (UnimplementedConfigServiceServer) CreateBackup(context.Context, *CreateBackupRequest) (*CreateBackupResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateBackup not implemented")
+}
+func (UnimplementedConfigServiceServer) RestoreBackup(context.Context, *RestoreBackupRequest) (*ImportResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreBackup not implemented")
+}
+func (UnimplementedConfigServiceServer) Diff(context.Context, *DiffRequest) (*DiffResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Diff not implemented")
+}
+func (UnimplementedConfigServiceServer) CreateDraft(context.Context, *CreateDraftRequest) (*DraftResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateDraft not implemented")
+}
+func (UnimplementedConfigServiceServer) GetDraft(context.Context, *GetDraftRequest) (*GetDraftResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDraft not implemented")
+}
+func (UnimplementedConfigServiceServer) ListDrafts(context.Context, *ListDraftsRequest) (*ListDraftsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListDrafts not implemented")
+}
+func (UnimplementedConfigServiceServer) UpdateDraft(context.Context, *UpdateDraftRequest) (*DraftResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateDraft not implemented")
+}
+func (UnimplementedConfigServiceServer) ApproveDraft(context.Context, *DraftActionRequest) (*DraftResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ApproveDraft not implemented")
+}
+func (UnimplementedConfigServiceServer) PublishDraft(context.Context, *DraftActionRequest) (*DraftResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PublishDraft not implemented")
+}
+func (UnimplementedConfigServiceServer) AbandonDraft(context.Context, *DraftActionRequest) (*DraftResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AbandonDraft not implemented")
+}
 func (UnimplementedConfigServiceServer) mustEmbedUnimplementedConfigServiceServer() {}
 func (UnimplementedConfigServiceServer) testEmbeddedByValue()                       {}
 
@@ -328,6 +707,355 @@ func _ConfigService_WatchConfig_Handler(srv interface{}, stream grpc.ServerStrea
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type ConfigService_WatchConfigServer = grpc.ServerStreamingServer[WatchConfigResponse]
 
+func _ConfigService_Session_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(ConfigServiceServer).Session(&grpc.GenericServerStream[SessionRequest, SessionResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ConfigService_SessionServer = grpc.BidiStreamingServer[SessionRequest, SessionResponse]
+
+func _ConfigService_ListClients_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListClientsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConfigServiceServer).ListClients(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ConfigService_ListClients_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConfigServiceServer).ListClients(ctx, req.(*ListClientsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ConfigService_SetServiceMeta_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetServiceMetaRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConfigServiceServer).SetServiceMeta(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ConfigService_SetServiceMeta_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConfigServiceServer).SetServiceMeta(ctx, req.(*SetServiceMetaRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ConfigService_GetServiceMeta_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetServiceMetaRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConfigServiceServer).GetServiceMeta(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ConfigService_GetServiceMeta_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConfigServiceServer).GetServiceMeta(ctx, req.(*GetServiceMetaRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ConfigService_DeleteServiceMeta_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteServiceMetaRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConfigServiceServer).DeleteServiceMeta(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ConfigService_DeleteServiceMeta_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConfigServiceServer).DeleteServiceMeta(ctx, req.(*DeleteServiceMetaRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ConfigService_Search_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConfigServiceServer).Search(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ConfigService_Search_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConfigServiceServer).Search(ctx, req.(*SearchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ConfigService_Sync_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SyncRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConfigServiceServer).Sync(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ConfigService_Sync_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConfigServiceServer).Sync(ctx, req.(*SyncRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ConfigService_Export_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConfigServiceServer).Export(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ConfigService_Export_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConfigServiceServer).Export(ctx, req.(*ExportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ConfigService_Import_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ImportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConfigServiceServer).Import(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ConfigService_Import_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConfigServiceServer).Import(ctx, req.(*ImportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ConfigService_ListBackups_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListBackupsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConfigServiceServer).ListBackups(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ConfigService_ListBackups_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConfigServiceServer).ListBackups(ctx, req.(*ListBackupsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ConfigService_CreateBackup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateBackupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConfigServiceServer).CreateBackup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ConfigService_CreateBackup_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConfigServiceServer).CreateBackup(ctx, req.(*CreateBackupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ConfigService_RestoreBackup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreBackupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConfigServiceServer).RestoreBackup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ConfigService_RestoreBackup_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConfigServiceServer).RestoreBackup(ctx, req.(*RestoreBackupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ConfigService_Diff_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DiffRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConfigServiceServer).Diff(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ConfigService_Diff_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConfigServiceServer).Diff(ctx, req.(*DiffRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ConfigService_CreateDraft_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateDraftRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConfigServiceServer).CreateDraft(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ConfigService_CreateDraft_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConfigServiceServer).CreateDraft(ctx, req.(*CreateDraftRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ConfigService_GetDraft_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDraftRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConfigServiceServer).GetDraft(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ConfigService_GetDraft_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConfigServiceServer).GetDraft(ctx, req.(*GetDraftRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ConfigService_ListDrafts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListDraftsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConfigServiceServer).ListDrafts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ConfigService_ListDrafts_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConfigServiceServer).ListDrafts(ctx, req.(*ListDraftsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ConfigService_UpdateDraft_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateDraftRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConfigServiceServer).UpdateDraft(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ConfigService_UpdateDraft_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConfigServiceServer).UpdateDraft(ctx, req.(*UpdateDraftRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ConfigService_ApproveDraft_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DraftActionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConfigServiceServer).ApproveDraft(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ConfigService_ApproveDraft_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConfigServiceServer).ApproveDraft(ctx, req.(*DraftActionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ConfigService_PublishDraft_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DraftActionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConfigServiceServer).PublishDraft(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ConfigService_PublishDraft_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConfigServiceServer).PublishDraft(ctx, req.(*DraftActionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ConfigService_AbandonDraft_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DraftActionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConfigServiceServer).AbandonDraft(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ConfigService_AbandonDraft_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConfigServiceServer).AbandonDraft(ctx, req.(*DraftActionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // ConfigService_ServiceDesc is the grpc.ServiceDesc for ConfigService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -359,6 +1087,82 @@ var ConfigService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ListServices",
 			Handler:    _ConfigService_ListServices_Handler,
 		},
+		{
+			MethodName: "ListClients",
+			Handler:    _ConfigService_ListClients_Handler,
+		},
+		{
+			MethodName: "SetServiceMeta",
+			Handler:    _ConfigService_SetServiceMeta_Handler,
+		},
+		{
+			MethodName: "GetServiceMeta",
+			Handler:    _ConfigService_GetServiceMeta_Handler,
+		},
+		{
+			MethodName: "DeleteServiceMeta",
+			Handler:    _ConfigService_DeleteServiceMeta_Handler,
+		},
+		{
+			MethodName: "Search",
+			Handler:    _ConfigService_Search_Handler,
+		},
+		{
+			MethodName: "Sync",
+			Handler:    _ConfigService_Sync_Handler,
+		},
+		{
+			MethodName: "Export",
+			Handler:    _ConfigService_Export_Handler,
+		},
+		{
+			MethodName: "Import",
+			Handler:    _ConfigService_Import_Handler,
+		},
+		{
+			MethodName: "ListBackups",
+			Handler:    _ConfigService_ListBackups_Handler,
+		},
+		{
+			MethodName: "CreateBackup",
+			Handler:    _ConfigService_CreateBackup_Handler,
+		},
+		{
+			MethodName: "RestoreBackup",
+			Handler:    _ConfigService_RestoreBackup_Handler,
+		},
+		{
+			MethodName: "Diff",
+			Handler:    _ConfigService_Diff_Handler,
+		},
+		{
+			MethodName: "CreateDraft",
+			Handler:    _ConfigService_CreateDraft_Handler,
+		},
+		{
+			MethodName: "GetDraft",
+			Handler:    _ConfigService_GetDraft_Handler,
+		},
+		{
+			MethodName: "ListDrafts",
+			Handler:    _ConfigService_ListDrafts_Handler,
+		},
+		{
+			MethodName: "UpdateDraft",
+			Handler:    _ConfigService_UpdateDraft_Handler,
+		},
+		{
+			MethodName: "ApproveDraft",
+			Handler:    _ConfigService_ApproveDraft_Handler,
+		},
+		{
+			MethodName: "PublishDraft",
+			Handler:    _ConfigService_PublishDraft_Handler,
+		},
+		{
+			MethodName: "AbandonDraft",
+			Handler:    _ConfigService_AbandonDraft_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -366,6 +1170,12 @@ var ConfigService_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _ConfigService_WatchConfig_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "Session",
+			Handler:       _ConfigService_Session_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
 	},
 	Metadata: "api/proto/config.proto",
 }
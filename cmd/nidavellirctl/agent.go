@@ -0,0 +1,285 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"syscall"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// agentConfig agent 配置文件
+type agentConfig struct {
+	// Wait 配置变化后等待稳定的时间, 期间的多次变化只渲染一次
+	Wait      time.Duration     `mapstructure:"wait"`
+	Templates []*templateConfig `mapstructure:"template"`
+	Files     []*filesConfig    `mapstructure:"files"`
+}
+
+// loadAgentConfig 读取 agent 配置文件并解析模板, 模板的相对路径相对于配置文件所在目录
+func loadAgentConfig(path string) (*agentConfig, error) {
+	v := viper.New()
+	v.SetConfigFile(path)
+	v.SetConfigType("toml")
+	v.SetDefault("wait", "1s")
+	if err := v.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("failed to read agent config %s: %w", path, err)
+	}
+
+	cfg := &agentConfig{}
+	if err := v.Unmarshal(cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse agent config %s: %w", path, err)
+	}
+	if len(cfg.Templates) == 0 && len(cfg.Files) == 0 {
+		return nil, fmt.Errorf("agent config %s has no [[template]] or [[files]] entries", path)
+	}
+
+	dir := filepath.Dir(path)
+	for _, t := range cfg.Templates {
+		if t.Source != "" && !filepath.IsAbs(t.Source) {
+			t.Source = filepath.Join(dir, t.Source)
+		}
+		if err := t.load(); err != nil {
+			return nil, err
+		}
+	}
+	for _, f := range cfg.Files {
+		if err := f.load(); err != nil {
+			return nil, err
+		}
+	}
+	return cfg, nil
+}
+
+// runAgent 按模板渲染配置文件, 配置变化后重新渲染并执行命令, -once 时渲染一次后退出
+func runAgent(a *app, args []string) error {
+	fs := flag.NewFlagSet("agent", flag.ContinueOnError)
+	configPath := fs.String("config", "", "agent config file")
+	once := fs.Bool("once", false, "render once and exit, for container entrypoints")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 0 || *configPath == "" {
+		return errors.New("usage: agent -config <file> [-once]")
+	}
+
+	cfg, err := loadAgentConfig(*configPath)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	ag := &agent{cfg: cfg}
+	if *once {
+		return ag.runOnce(ctx, a)
+	}
+	return ag.run(ctx, a)
+}
+
+// agent 渲染模板与配置文件目录
+type agent struct {
+	cfg *agentConfig
+}
+
+// agentCommand 文件变化后待执行的命令
+type agentCommand struct {
+	command string
+	timeout time.Duration
+}
+
+// fileServices [[files]] 使用的服务
+func (ag *agent) fileServices() []string {
+	services := make([]string, 0, len(ag.cfg.Files))
+	for _, f := range ag.cfg.Files {
+		services = append(services, f.Service)
+	}
+	return services
+}
+
+// runOnce 读取模板引用的服务, 全部渲染完成并执行命令后返回, 任何错误都会返回
+func (ag *agent) runOnce(ctx context.Context, a *app) error {
+	configs := make(configSet)
+	loaded := make(map[string]bool)
+	services := ag.fileServices()
+	for {
+		if len(services) > 0 {
+			fetched, err := a.fetchConfigs(services)
+			if err != nil {
+				return err
+			}
+			for _, service := range services {
+				configs[service] = fetched[service]
+				loaded[service] = true
+			}
+		}
+
+		missing, err := ag.renderAll(ctx, configs, loaded)
+		if err != nil {
+			return err
+		}
+		if len(missing) == 0 {
+			return nil
+		}
+		// 模板中的条件分支可能在加载服务后引用新的服务, 继续加载直到没有遗漏
+		services = missing
+	}
+}
+
+// run 监听模板引用的服务, 首次快照后立即渲染, 之后的变化稳定 Wait 后重新渲染。
+// 模板引用了新的服务时扩大监听范围并重新获取快照, 渲染错误只记录日志并保留原文件
+func (ag *agent) run(ctx context.Context, a *app) error {
+	var (
+		watched     = make(map[string]bool)
+		cancelWatch = func() {}
+		updates     chan configSet
+		configs     configSet
+		ready       bool
+		debounce    *time.Timer
+		fire        <-chan time.Time
+	)
+	defer func() { cancelWatch() }()
+
+	startWatch := func() {
+		cancelWatch()
+		services := make([]string, 0, len(watched))
+		for service := range watched {
+			services = append(services, service)
+		}
+		sort.Strings(services)
+
+		var watchCtx context.Context
+		watchCtx, cancelWatch = context.WithCancel(ctx)
+		updates = make(chan configSet)
+		ready = false
+		go a.watchConfigs(watchCtx, services, updates)
+	}
+
+	pass := func() {
+		loaded := make(map[string]bool, len(watched))
+		if ready {
+			for service := range watched {
+				loaded[service] = true
+			}
+		}
+		missing, err := ag.renderAll(ctx, configs, loaded)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "nidavellirctl:", err)
+		}
+
+		added := false
+		for _, service := range missing {
+			if !watched[service] {
+				watched[service] = true
+				added = true
+			}
+		}
+		if added {
+			startWatch()
+		}
+	}
+
+	for _, service := range ag.fileServices() {
+		watched[service] = true
+	}
+	// 不加载任何服务渲染一次, 找出模板引用的服务
+	pass()
+	if len(watched) == 0 {
+		fmt.Fprintln(os.Stderr, "nidavellirctl: templates reference no services, nothing to watch")
+		return nil
+	}
+	if updates == nil {
+		startWatch()
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+
+		case c := <-updates:
+			configs = c
+			if !ready {
+				ready = true
+				pass()
+				continue
+			}
+			if debounce == nil {
+				debounce = time.NewTimer(ag.cfg.Wait)
+			} else {
+				debounce.Reset(ag.cfg.Wait)
+			}
+			fire = debounce.C
+
+		case <-fire:
+			fire = nil
+			pass()
+		}
+	}
+}
+
+// renderAll 渲染所有模板并同步文件目录, 执行内容变化对应的命令(相同的命令只执行一次),
+// 返回模板引用了但尚未加载的服务
+func (ag *agent) renderAll(ctx context.Context, configs configSet, loaded map[string]bool) ([]string, error) {
+	var (
+		errs     []error
+		commands []agentCommand
+		missing  = make(map[string]bool)
+		seen     = make(map[string]bool)
+	)
+	addCommand := func(command string, timeout time.Duration) {
+		if command == "" || seen[command] {
+			return
+		}
+		seen[command] = true
+		commands = append(commands, agentCommand{command: command, timeout: timeout})
+	}
+
+	for _, t := range ag.cfg.Templates {
+		changed, services, err := t.render(configs, loaded)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		for _, service := range services {
+			missing[service] = true
+		}
+		if changed {
+			fmt.Fprintf(os.Stderr, "nidavellirctl: rendered %s\n", t.Destination)
+			addCommand(t.Command, t.CommandTimeout)
+		}
+	}
+
+	for _, f := range ag.cfg.Files {
+		changed, err := f.sync(configs, loaded)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		if changed {
+			fmt.Fprintf(os.Stderr, "nidavellirctl: synced %s to %s\n", f.Service, f.Directory)
+			addCommand(f.Command, f.CommandTimeout)
+		}
+	}
+
+	for _, c := range commands {
+		fmt.Fprintf(os.Stderr, "nidavellirctl: running %q\n", c.command)
+		if err := runCommand(ctx, c.command, c.timeout); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	services := make([]string, 0, len(missing))
+	for service := range missing {
+		services = append(services, service)
+	}
+	sort.Strings(services)
+	return services, errors.Join(errs...)
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	grpcConfig "nidavellir/api/proto"
+)
+
+// runExport 导出所有服务的配置与元数据, 敏感配置的值需要有权限的访问令牌, 由服务端以归档密钥加密后导出
+func runExport(a *app, args []string) error {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	format := fs.String("format", "", "archive format: json or toml (default from -f extension, otherwise json)")
+	file := fs.String("f", "", "write the archive to file instead of stdout")
+	var services stringList
+	fs.Var(&services, "service", "service name or pattern such as Heimdallr* (repeatable)")
+	args, err := parseInterspersed(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(args) != 0 {
+		return errors.New("usage: export [-format json|toml] [-service s]... [-f file]")
+	}
+	if *format == "" {
+		*format = formatFromExt(*file)
+	}
+
+	ctx, cancel := a.requestContext()
+	defer cancel()
+
+	resp, err := a.client.Export(ctx, &grpcConfig.ExportRequest{Format: *format, Services: services})
+	if err != nil {
+		return err
+	}
+	if *file == "" {
+		_, err := os.Stdout.Write(resp.Archive)
+		return err
+	}
+	if err := os.WriteFile(*file, resp.Archive, 0600); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "exported revision %d to %s\n", resp.Revision, *file)
+	return nil
+}
+
+// runImport 导入 export 生成的归档, -dry-run 时只输出计划
+func runImport(a *app, args []string) error {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	mode := fs.String("mode", "merge", "merge writes archived configs, replace also deletes configs not in the archive")
+	format := fs.String("format", "", "archive format: json or toml (default from file extension or content)")
+	dryRun := fs.Bool("dry-run", false, "print the plan without applying it")
+	var services stringList
+	fs.Var(&services, "service", "only import services matching name or pattern (repeatable)")
+	args, err := parseInterspersed(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(args) != 1 {
+		return errors.New("usage: import <file|-> [-mode merge|replace] [-service s]... [-dry-run]")
+	}
+
+	var data []byte
+	if args[0] == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(args[0])
+		if *format == "" {
+			*format = formatFromExt(args[0])
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := a.requestContext()
+	defer cancel()
+
+	resp, err := a.client.Import(ctx, &grpcConfig.ImportRequest{
+		Archive:  data,
+		Format:   *format,
+		Mode:     *mode,
+		Services: services,
+		DryRun:   *dryRun,
+	})
+	if err != nil {
+		return err
+	}
+	if err := a.printer.printImport(resp); err != nil {
+		return err
+	}
+
+	if len(resp.Skipped) > 0 {
+		fmt.Fprintf(os.Stderr, "%d secret configs were exported without values and skipped\n", len(resp.Skipped))
+	}
+	return nil
+}
+
+// formatFromExt 根据文件扩展名判断归档格式, 无法判断时为空
+func formatFromExt(name string) string {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".json":
+		return "json"
+	case ".toml":
+		return "toml"
+	}
+	return ""
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	grpcConfig "nidavellir/api/proto"
+)
+
+const backupUsage = "usage: backup list | backup create | backup restore <name> [-service s]... [-mode replace|merge] [-dry-run] [-yes]"
+
+// runBackup 管理服务端的本地备份
+func runBackup(a *app, args []string) error {
+	if len(args) == 0 {
+		return errors.New(backupUsage)
+	}
+
+	switch args[0] {
+	case "list":
+		ctx, cancel := a.requestContext()
+		defer cancel()
+		resp, err := a.client.ListBackups(ctx, &grpcConfig.ListBackupsRequest{})
+		if err != nil {
+			return err
+		}
+		return a.printer.printBackups(resp.Backups)
+	case "create":
+		ctx, cancel := a.requestContext()
+		defer cancel()
+		resp, err := a.client.CreateBackup(ctx, &grpcConfig.CreateBackupRequest{})
+		if err != nil {
+			return err
+		}
+		return a.printer.printBackups([]*grpcConfig.BackupInfo{resp.Backup})
+	case "restore":
+		return runRestore(a, args[1:])
+	}
+	return errors.New(backupUsage)
+}
+
+// runRestore 先输出恢复计划, 确认后再写入, -dry-run 时只输出计划
+func runRestore(a *app, args []string) error {
+	fs := flag.NewFlagSet("backup restore", flag.ContinueOnError)
+	mode := fs.String("mode", "replace", "replace restores services to the backup state, merge only creates and updates")
+	dryRun := fs.Bool("dry-run", false, "print the plan without applying it")
+	yes := fs.Bool("yes", false, "apply without asking for confirmation")
+	var services stringList
+	fs.Var(&services, "service", "only restore services matching name or pattern (repeatable)")
+	args, err := parseInterspersed(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(args) != 1 {
+		return errors.New(backupUsage)
+	}
+
+	req := &grpcConfig.RestoreBackupRequest{Name: args[0], Services: services, Mode: *mode, DryRun: true}
+	ctx, cancel := a.requestContext()
+	plan, err := a.client.RestoreBackup(ctx, req)
+	cancel()
+	if err != nil {
+		return err
+	}
+	if err := a.printer.printImport(plan); err != nil {
+		return err
+	}
+	if *dryRun {
+		return nil
+	}
+	if len(plan.Changes) == 0 && len(plan.Metadata) == 0 {
+		fmt.Fprintln(os.Stderr, "nothing to restore")
+		return nil
+	}
+	if !*yes && !confirm(fmt.Sprintf("restore %d changes from %s?", len(plan.Changes), args[0])) {
+		return errors.New("restore cancelled")
+	}
+
+	req.DryRun, req.Confirm = false, true
+	ctx, cancel = a.requestContext()
+	defer cancel()
+	resp, err := a.client.RestoreBackup(ctx, req)
+	if err != nil {
+		return err
+	}
+	if len(resp.Changes) != len(plan.Changes) {
+		fmt.Fprintln(os.Stderr, "configs changed after the preview, applied changes:")
+		if err := a.printer.printImport(resp); err != nil {
+			return err
+		}
+	}
+	fmt.Fprintf(os.Stderr, "restored %d changes at revision %d\n", len(resp.Changes), resp.Revision)
+	return nil
+}
+
+// confirm 在标准错误输出提示并从标准输入读取 y/N
+func confirm(prompt string) bool {
+	fmt.Fprintf(os.Stderr, "%s [y/N] ", prompt)
+	line, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	answer := strings.ToLower(strings.TrimSpace(line))
+	return answer == "y" || answer == "yes"
+}
@@ -0,0 +1,453 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	grpcConfig "nidavellir/api/proto"
+
+	"google.golang.org/grpc"
+)
+
+// app 命令执行环境
+type app struct {
+	conn    *grpc.ClientConn
+	client  grpcConfig.ConfigServiceClient
+	printer *printer
+	timeout time.Duration
+}
+
+// newApp 根据全局参数创建执行环境
+func newApp(opts *globalOptions) (*app, error) {
+	c, err := loadContext(contextFilePath(), opts.contextName)
+	if err != nil {
+		return nil, err
+	}
+	if opts.server != "" {
+		c.Server = opts.server
+	}
+	if opts.token != "" {
+		c.Token = opts.token
+	}
+
+	p, err := newPrinter(os.Stdout, opts.output)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, client, err := dial(c)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect %s: %w", c.Server, err)
+	}
+
+	return &app{
+		conn:    conn,
+		client:  client,
+		printer: p,
+		timeout: time.Duration(opts.timeout) * time.Second,
+	}, nil
+}
+
+// close 关闭连接
+func (a *app) close() {
+	_ = a.conn.Close()
+}
+
+// requestContext 创建带超时的请求上下文
+func (a *app) requestContext() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), a.timeout)
+}
+
+// serviceConfigs 分页获取服务的所有配置
+func (a *app) serviceConfigs(service string) (map[string]*grpcConfig.ConfigItem, error) {
+	ctx, cancel := a.requestContext()
+	defer cancel()
+
+	configs := make(map[string]*grpcConfig.ConfigItem)
+	req := &grpcConfig.GetServiceConfigsRequest{ServiceName: service, Limit: pageSize}
+	for {
+		resp, err := a.client.GetServiceConfigs(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		for _, config := range resp.Items {
+			configs[config.Key] = config
+		}
+		// 旧版本的服务端只返回 configs
+		if len(resp.Items) == 0 {
+			for key, config := range resp.Configs {
+				configs[key] = config
+			}
+		}
+		if resp.NextPageToken == "" {
+			return configs, nil
+		}
+		req.PageToken = resp.NextPageToken
+	}
+}
+
+// runGet 获取配置
+func runGet(a *app, args []string) error {
+	if len(args) != 2 {
+		return errors.New("usage: get <service> <key>")
+	}
+
+	ctx, cancel := a.requestContext()
+	defer cancel()
+
+	resp, err := a.client.GetConfig(ctx, &grpcConfig.GetConfigRequest{ServiceName: args[0], Key: args[1]})
+	if err != nil {
+		return err
+	}
+	if !resp.Found {
+		return fmt.Errorf("config %s/%s not found", args[0], args[1])
+	}
+
+	return a.printer.printItem(resp.Config)
+}
+
+// runSet 设置配置
+func runSet(a *app, args []string) error {
+	fs := flag.NewFlagSet("set", flag.ContinueOnError)
+	file := fs.String("f", "", "read value from file")
+	description := fs.String("d", "", "config description")
+	encrypt := fs.Bool("encrypt", false, "mark the config as secret")
+	args, err := parseInterspersed(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(args) < 2 || len(args) > 3 {
+		return errors.New("usage: set <service> <key> [value|-] [-f file] [-d description] [-encrypt]")
+	}
+
+	var value string
+	switch {
+	case *file != "":
+		if len(args) == 3 {
+			return errors.New("value argument and -f are mutually exclusive")
+		}
+		data, err := os.ReadFile(*file)
+		if err != nil {
+			return err
+		}
+		value = strings.TrimSuffix(string(data), "\n")
+	case len(args) == 3 && args[2] != "-":
+		value = args[2]
+	default:
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return err
+		}
+		value = strings.TrimSuffix(string(data), "\n")
+	}
+
+	ctx, cancel := a.requestContext()
+	defer cancel()
+
+	resp, err := a.client.SetConfig(ctx, &grpcConfig.SetConfigRequest{
+		ServiceName: args[0],
+		Key:         args[1],
+		Value:       value,
+		Description: *description,
+		Encrypt:     *encrypt,
+	})
+	if err != nil {
+		return err
+	}
+	if !resp.Success {
+		return errors.New(resp.Message)
+	}
+
+	fmt.Fprintln(os.Stderr, resp.Message)
+	return nil
+}
+
+// runDelete 删除配置
+func runDelete(a *app, args []string) error {
+	ctx, cancel := a.requestContext()
+	defer cancel()
+
+	switch len(args) {
+	case 1:
+		resp, err := a.client.DeleteServiceConfigs(ctx, &grpcConfig.DeleteServiceConfigsRequest{ServiceName: args[0]})
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(os.Stderr, resp.Message)
+	case 2:
+		resp, err := a.client.DeleteConfig(ctx, &grpcConfig.DeleteConfigRequest{ServiceName: args[0], Key: args[1]})
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(os.Stderr, resp.Message)
+	default:
+		return errors.New("usage: delete <service> [key]")
+	}
+
+	return nil
+}
+
+// runServices 列出所有服务
+func runServices(a *app, args []string) error {
+	if len(args) != 0 {
+		return errors.New("usage: services")
+	}
+
+	ctx, cancel := a.requestContext()
+	defer cancel()
+
+	var services []string
+	req := &grpcConfig.ListServicesRequest{Limit: pageSize}
+	for {
+		resp, err := a.client.ListServices(ctx, req)
+		if err != nil {
+			return err
+		}
+		services = append(services, resp.Services...)
+		if resp.NextPageToken == "" {
+			break
+		}
+		req.PageToken = resp.NextPageToken
+	}
+
+	return a.printer.printServices(services)
+}
+
+// runClients 列出已连接的客户端
+func runClients(a *app, args []string) error {
+	fs := flag.NewFlagSet("clients", flag.ContinueOnError)
+	drift := fs.Bool("drift", false, "only show clients behind the latest revision")
+	args, err := parseInterspersed(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(args) != 0 {
+		return errors.New("usage: clients [-drift]")
+	}
+
+	ctx, cancel := a.requestContext()
+	defer cancel()
+
+	resp, err := a.client.ListClients(ctx, &grpcConfig.ListClientsRequest{DriftOnly: *drift})
+	if err != nil {
+		return err
+	}
+
+	return a.printer.printClients(resp.Clients)
+}
+
+// runDump 导出服务的所有配置
+func runDump(a *app, args []string) error {
+	if len(args) != 1 {
+		return errors.New("usage: dump <service>")
+	}
+
+	configs, err := a.serviceConfigs(args[0])
+	if err != nil {
+		return err
+	}
+
+	items := make([]*grpcConfig.ConfigItem, 0, len(configs))
+	for _, item := range configs {
+		items = append(items, item)
+	}
+
+	return a.printer.printItems(items)
+}
+
+// stringList 可重复指定的字符串参数
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+// runSearch 搜索配置, 敏感配置的值需要有权限的访问令牌才能匹配和查看
+func runSearch(a *app, args []string) error {
+	fs := flag.NewFlagSet("search", flag.ContinueOnError)
+	regex := fs.Bool("regex", false, "treat query as a regular expression")
+	ignoreCase := fs.Bool("i", false, "case insensitive match")
+	limit := fs.Int("limit", 0, "maximum number of results (server default 100)")
+	var fields, services, labels stringList
+	fs.Var(&fields, "field", "field to match: key, value or description (repeatable)")
+	fs.Var(&services, "service", "service name or pattern such as Heimdallr* (repeatable)")
+	fs.Var(&labels, "label", "service label name=value (repeatable)")
+	args, err := parseInterspersed(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(args) != 1 {
+		return errors.New("usage: search <query> [-regex] [-i] [-field f]... [-service s]... [-label k=v]... [-limit n]")
+	}
+
+	req := &grpcConfig.SearchRequest{
+		Query:      args[0],
+		Regex:      *regex,
+		IgnoreCase: *ignoreCase,
+		Fields:     fields,
+		Services:   services,
+		Limit:      int32(*limit),
+	}
+	for _, label := range labels {
+		name, value, ok := strings.Cut(label, "=")
+		if !ok || name == "" {
+			return fmt.Errorf("invalid label %q, expected name=value", label)
+		}
+		if req.Labels == nil {
+			req.Labels = make(map[string]string)
+		}
+		req.Labels[name] = value
+	}
+
+	ctx, cancel := a.requestContext()
+	defer cancel()
+
+	resp, err := a.client.Search(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	items := make([]*grpcConfig.ConfigItem, 0, len(resp.Matches))
+	for _, match := range resp.Matches {
+		items = append(items, match.Config)
+	}
+	if resp.Truncated {
+		fmt.Fprintf(os.Stderr, "showing first %d matches, use -limit to see more\n", len(items))
+	}
+	return a.printer.printItems(items)
+}
+
+// runWatch 监听配置变化, 未指定服务与条件时监听所有服务, 直到收到中断信号
+func runWatch(a *app, args []string) error {
+	fs := flag.NewFlagSet("watch", flag.ContinueOnError)
+	revision := fs.Int64("rev", 0, "replay changes starting from this revision")
+	snapshot := fs.Bool("snapshot", false, "print current configs before streaming changes")
+	var selectors stringList
+	fs.Var(&selectors, "match", "additional selector such as Heimdallr/Job* (repeatable)")
+	args, err := parseInterspersed(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(args) > 2 {
+		return errors.New("usage: watch [service [key]] [-match selector]... [-rev revision | -snapshot]")
+	}
+
+	req := &grpcConfig.WatchConfigRequest{StartRevision: *revision, Snapshot: *snapshot, Selectors: selectors}
+	if len(args) >= 1 {
+		req.ServiceName = args[0]
+	}
+	if len(args) == 2 {
+		req.Key = args[1]
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	stream, err := a.client.WatchConfig(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	for {
+		resp, err := stream.Recv()
+		if err != nil {
+			if ctx.Err() != nil || errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+		if err := a.printer.printEvent(resp); err != nil {
+			return err
+		}
+	}
+}
+
+// diffExitCode -exit-code 时两侧存在差异的退出码, 与 diff 命令相同
+const diffExitCode = 1
+
+// runDiff 比较两侧服务的配置, 每一侧可以是服务、其他环境中的服务、备份或归档文件中的服务、
+// 以及服务的历史版本, 格式为 [<namespace>/]<service>[@<revision>]
+func runDiff(a *app, args []string) error {
+	fs := flag.NewFlagSet("diff", flag.ContinueOnError)
+	unified := fs.Bool("u", false, "print a unified diff")
+	context := fs.Int("context", 3, "lines of context in the unified diff")
+	exit := fs.Bool("exit-code", false, "exit with status 1 when the sides differ")
+	args, err := parseInterspersed(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(args) != 2 {
+		return errors.New("usage: diff <side> <side> [-u] [-context n] [-exit-code], side: [<env>|backup:<name>|file:<path>/]<service>[@<revision>]")
+	}
+
+	req := &grpcConfig.DiffRequest{Left: args[0], Right: args[1], Unified: *unified, Context: int32(*context)}
+	if *context == 0 {
+		// 请求中的0表示默认值, 不需要上下文时传-1
+		req.Context = -1
+	}
+	if req.LeftArchive, err = readDiffArchive(args[0]); err != nil {
+		return err
+	}
+	if req.RightArchive, err = readDiffArchive(args[1]); err != nil {
+		return err
+	}
+
+	ctx, cancel := a.requestContext()
+	defer cancel()
+
+	resp, err := a.client.Diff(ctx, req)
+	if err != nil {
+		return err
+	}
+	if *unified {
+		fmt.Print(resp.Unified)
+	} else {
+		if err := a.printer.printDiff(resp.Left, resp.Right, diffEntries(resp.Changes)); err != nil {
+			return err
+		}
+	}
+	if *exit && len(resp.Changes) > 0 {
+		return exitCode(diffExitCode)
+	}
+	return nil
+}
+
+// diffEntries 转换服务端返回的差异, 值以纯文本显示
+func diffEntries(changes []*grpcConfig.DiffChange) []diffEntry {
+	entries := make([]diffEntry, 0, len(changes))
+	for _, change := range changes {
+		entries = append(entries, diffEntry{
+			Key:   change.Key,
+			Op:    change.Op,
+			Left:  plainValue(change.Old),
+			Right: plainValue(change.New),
+		})
+	}
+	return entries
+}
+
+// readDiffArchive 读取 file:<path>/<service> 一侧的归档文件, 其他形式返回空
+func readDiffArchive(spec string) ([]byte, error) {
+	rest, ok := strings.CutPrefix(spec, "file:")
+	if !ok {
+		return nil, nil
+	}
+	i := strings.LastIndex(rest, "/")
+	if i <= 0 {
+		return nil, fmt.Errorf("invalid side %q: expected file:<path>/<service>", spec)
+	}
+	return os.ReadFile(rest[:i])
+}
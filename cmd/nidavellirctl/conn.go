@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+
+	grpcConfig "nidavellir/api/proto"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+)
+
+// dial 连接服务端, server支持 host:port 与 unix:///path 两种形式
+func dial(c *ctlContext) (*grpc.ClientConn, grpcConfig.ConfigServiceClient, error) {
+	opts := []grpc.DialOption{
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUserAgent("nidavellirctl"),
+	}
+	if c.Token != "" {
+		opts = append(opts,
+			grpc.WithUnaryInterceptor(tokenUnaryInterceptor(c.Token)),
+			grpc.WithStreamInterceptor(tokenStreamInterceptor(c.Token)),
+		)
+	}
+
+	conn, err := grpc.NewClient(c.Server, opts...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return conn, grpcConfig.NewConfigServiceClient(conn), nil
+}
+
+// withToken 在请求元数据中附加令牌
+func withToken(ctx context.Context, token string) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+token)
+}
+
+// tokenUnaryInterceptor 一元调用令牌拦截器
+func tokenUnaryInterceptor(token string) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return invoker(withToken(ctx, token), method, req, reply, cc, opts...)
+	}
+}
+
+// tokenStreamInterceptor 流调用令牌拦截器
+func tokenStreamInterceptor(token string) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return streamer(withToken(ctx, token), desc, cc, method, opts...)
+	}
+}
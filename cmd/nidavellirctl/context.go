@@ -0,0 +1,94 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	// defaultContextFile 默认上下文文件路径（相对于用户主目录）
+	defaultContextFile = ".nidavellir/context.toml"
+	// defaultServer 默认服务地址
+	defaultServer = "unix:///var/run/Nidavellir.sock"
+	// pageSize 分页获取服务与配置时每页的数量
+	pageSize = 500
+)
+
+// ctlContext 客户端上下文，描述连接哪个服务端以及使用的令牌
+type ctlContext struct {
+	Server string `mapstructure:"server"`
+	Token  string `mapstructure:"token"`
+}
+
+// contextFile 上下文文件结构
+//
+//	current = "local"
+//	[contexts.local]
+//	server = "unix:///var/run/Nidavellir.sock"
+//	token = ""
+type contextFile struct {
+	Current  string                 `mapstructure:"current"`
+	Contexts map[string]*ctlContext `mapstructure:"contexts"`
+}
+
+// contextFilePath 获取上下文文件路径, 优先使用环境变量 NIDAVELLIR_CONTEXT_FILE
+func contextFilePath() string {
+	if path := os.Getenv("NIDAVELLIR_CONTEXT_FILE"); path != "" {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, defaultContextFile)
+}
+
+// loadContext 加载上下文, name为空时使用文件中的current
+func loadContext(path, name string) (*ctlContext, error) {
+	ctx := &ctlContext{Server: defaultServer}
+	if path == "" {
+		return ctx, nil
+	}
+
+	v := viper.New()
+	v.SetConfigFile(path)
+	v.SetConfigType("toml")
+	if err := v.ReadInConfig(); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			if name != "" {
+				return nil, fmt.Errorf("context %q not found: %s does not exist", name, path)
+			}
+			return ctx, nil
+		}
+		return nil, fmt.Errorf("failed to read context file: %w", err)
+	}
+
+	var file contextFile
+	if err := v.Unmarshal(&file); err != nil {
+		return nil, fmt.Errorf("failed to parse context file: %w", err)
+	}
+
+	if name == "" {
+		name = file.Current
+	}
+	if name == "" {
+		return ctx, nil
+	}
+
+	// viper会将键名转为小写
+	found, ok := file.Contexts[strings.ToLower(name)]
+	if !ok || found == nil {
+		return nil, fmt.Errorf("context %q not found in %s", name, path)
+	}
+	if found.Server != "" {
+		ctx.Server = found.Server
+	}
+	ctx.Token = found.Token
+
+	return ctx, nil
+}
@@ -0,0 +1,237 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	grpcConfig "nidavellir/api/proto"
+)
+
+const draftUsage = "usage: draft list [-status s] | draft show <id> [-u] | " +
+	"draft create -m desc [-set s/k=v]... [-secret s/k=v]... [-delete s/k]... | " +
+	"draft update <id> [-m desc] [ops]... | draft approve <id> | draft publish <id> [-yes] | draft abandon <id>"
+
+// runDraft 管理草稿: 一组审批后原子发布的配置修改
+func runDraft(a *app, args []string) error {
+	if len(args) == 0 {
+		return errors.New(draftUsage)
+	}
+
+	switch args[0] {
+	case "list":
+		return runDraftList(a, args[1:])
+	case "show":
+		return runDraftShow(a, args[1:])
+	case "create":
+		return runDraftEdit(a, "", args[1:])
+	case "update":
+		if len(args) < 2 {
+			return errors.New(draftUsage)
+		}
+		return runDraftEdit(a, args[1], args[2:])
+	case "approve", "abandon":
+		if len(args) != 2 {
+			return errors.New(draftUsage)
+		}
+		return runDraftAction(a, args[0], args[1])
+	case "publish":
+		return runDraftPublish(a, args[1:])
+	}
+	return errors.New(draftUsage)
+}
+
+// runDraftList 列出草稿
+func runDraftList(a *app, args []string) error {
+	fs := flag.NewFlagSet("draft list", flag.ContinueOnError)
+	status := fs.String("status", "open", "draft status: open, published, abandoned or all")
+	args, err := parseInterspersed(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(args) != 0 {
+		return errors.New(draftUsage)
+	}
+	if *status == "all" {
+		*status = ""
+	}
+
+	ctx, cancel := a.requestContext()
+	defer cancel()
+	resp, err := a.client.ListDrafts(ctx, &grpcConfig.ListDraftsRequest{Status: *status})
+	if err != nil {
+		return err
+	}
+	return a.printer.printDrafts(resp.Drafts)
+}
+
+// runDraftShow 输出草稿与每个服务当前配置和发布后配置的差异
+func runDraftShow(a *app, args []string) error {
+	fs := flag.NewFlagSet("draft show", flag.ContinueOnError)
+	unified := fs.Bool("u", false, "print a unified diff")
+	context := fs.Int("context", 3, "lines of context in the unified diff")
+	args, err := parseInterspersed(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(args) != 1 {
+		return errors.New(draftUsage)
+	}
+
+	resp, err := a.getDraft(args[0], *unified, *context)
+	if err != nil {
+		return err
+	}
+	return a.printer.printDraftDiff(resp, *unified)
+}
+
+// runDraftEdit 创建草稿, id 不为空时修改草稿, 没有指定操作时保留原有的操作并以当前配置为基准
+func runDraftEdit(a *app, id string, args []string) error {
+	name := "draft create"
+	if id != "" {
+		name = "draft update"
+	}
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	description := fs.String("m", "", "draft description")
+	var ops []*grpcConfig.DraftOperation
+	fs.Var(&draftOpFlag{ops: &ops, op: "set"}, "set", "set service/key=value (repeatable)")
+	fs.Var(&draftOpFlag{ops: &ops, op: "set", encrypt: true}, "secret", "set service/key=value as a secret config (repeatable)")
+	fs.Var(&draftOpFlag{ops: &ops, op: "delete"}, "delete", "delete service/key (repeatable)")
+	args, err := parseInterspersed(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(args) != 0 {
+		return errors.New(draftUsage)
+	}
+
+	ctx, cancel := a.requestContext()
+	defer cancel()
+
+	var resp *grpcConfig.DraftResponse
+	if id == "" {
+		if len(ops) == 0 {
+			return errors.New("at least one of -set, -secret or -delete is required")
+		}
+		resp, err = a.client.CreateDraft(ctx, &grpcConfig.CreateDraftRequest{
+			Description: *description,
+			Operations:  ops,
+		})
+	} else {
+		resp, err = a.client.UpdateDraft(ctx, &grpcConfig.UpdateDraftRequest{
+			Id:          id,
+			Description: *description,
+			Operations:  ops,
+		})
+	}
+	if err != nil {
+		return err
+	}
+	return a.printer.printDrafts([]*grpcConfig.Draft{resp.Draft})
+}
+
+// runDraftAction 审批或放弃草稿
+func runDraftAction(a *app, action, id string) error {
+	ctx, cancel := a.requestContext()
+	defer cancel()
+
+	req := &grpcConfig.DraftActionRequest{Id: id}
+	var (
+		resp *grpcConfig.DraftResponse
+		err  error
+	)
+	if action == "approve" {
+		resp, err = a.client.ApproveDraft(ctx, req)
+	} else {
+		resp, err = a.client.AbandonDraft(ctx, req)
+	}
+	if err != nil {
+		return err
+	}
+	return a.printer.printDrafts([]*grpcConfig.Draft{resp.Draft})
+}
+
+// runDraftPublish 先输出草稿的差异, 确认后发布
+func runDraftPublish(a *app, args []string) error {
+	fs := flag.NewFlagSet("draft publish", flag.ContinueOnError)
+	yes := fs.Bool("yes", false, "publish without asking for confirmation")
+	args, err := parseInterspersed(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(args) != 1 {
+		return errors.New(draftUsage)
+	}
+
+	if !*yes {
+		preview, err := a.getDraft(args[0], false, 0)
+		if err != nil {
+			return err
+		}
+		if err := a.printer.printDraftDiff(preview, false); err != nil {
+			return err
+		}
+		if !confirm(fmt.Sprintf("publish draft %s?", args[0])) {
+			return errors.New("publish cancelled")
+		}
+	}
+
+	ctx, cancel := a.requestContext()
+	defer cancel()
+	resp, err := a.client.PublishDraft(ctx, &grpcConfig.DraftActionRequest{Id: args[0]})
+	if err != nil {
+		return err
+	}
+	if err := a.printer.printDrafts([]*grpcConfig.Draft{resp.Draft}); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "published at revision %d\n", resp.Draft.Revision)
+	return nil
+}
+
+// getDraft 获取草稿及其差异
+func (a *app) getDraft(id string, unified bool, context int) (*grpcConfig.GetDraftResponse, error) {
+	req := &grpcConfig.GetDraftRequest{Id: id, Diff: true, Unified: unified, Context: int32(context)}
+	if context == 0 {
+		// 请求中的0表示默认值, 不需要上下文时传-1
+		req.Context = -1
+	}
+	ctx, cancel := a.requestContext()
+	defer cancel()
+	return a.client.GetDraft(ctx, req)
+}
+
+// draftOpFlag 可重复的草稿操作参数, 格式为 service/key=value, 删除时为 service/key
+type draftOpFlag struct {
+	ops     *[]*grpcConfig.DraftOperation
+	op      string
+	encrypt bool
+}
+
+func (f *draftOpFlag) String() string {
+	return ""
+}
+
+func (f *draftOpFlag) Set(v string) error {
+	target, value, hasValue := strings.Cut(v, "=")
+	if f.op == "delete" {
+		target, hasValue = v, true
+	}
+	service, key, ok := strings.Cut(target, "/")
+	if !ok || service == "" || key == "" || !hasValue {
+		if f.op == "delete" {
+			return fmt.Errorf("invalid operation %q, expected service/key", v)
+		}
+		return fmt.Errorf("invalid operation %q, expected service/key=value", v)
+	}
+	*f.ops = append(*f.ops, &grpcConfig.DraftOperation{
+		Op:          f.op,
+		ServiceName: service,
+		Key:         key,
+		Value:       value,
+		Encrypt:     f.encrypt,
+	})
+	return nil
+}
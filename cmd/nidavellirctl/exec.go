@@ -0,0 +1,275 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"slices"
+	"strings"
+	"time"
+
+	"nidavellir/internal/render"
+)
+
+// exitCode 以指定退出码退出, 用于传递子进程的退出码
+type exitCode int
+
+func (c exitCode) Error() string {
+	return fmt.Sprintf("exit status %d", int(c))
+}
+
+// execOptions exec 子命令参数
+type execOptions struct {
+	services    []string
+	keys        render.KeyOptions
+	signal      os.Signal
+	restart     bool
+	debounce    time.Duration
+	stopTimeout time.Duration
+}
+
+// runExec 将服务配置注入环境变量后运行命令, -watch 时配置变化后向进程发送信号或重启进程
+func runExec(a *app, args []string) error {
+	const usageText = "usage: exec -service <service>... [-key-case c] [-prefix p] [-watch [-signal sig | -restart] [-debounce d]] -- <command> [args]"
+
+	flagArgs, command := splitCommand(args)
+	fs := flag.NewFlagSet("exec", flag.ContinueOnError)
+	var services stringList
+	fs.Var(&services, "service", "service whose configs are injected, later services override earlier ones (repeatable)")
+	keyCase := fs.String("key-case", render.CaseUpperSnake, "key transform: upper_snake, lower_snake or none")
+	prefix := fs.String("prefix", "", "prefix added to every variable name")
+	watch := fs.Bool("watch", false, "keep running and react to config changes")
+	signalName := fs.String("signal", "HUP", "signal sent to the process when configs change")
+	restart := fs.Bool("restart", false, "restart the process with the new environment instead of sending a signal")
+	debounce := fs.Duration("debounce", time.Second, "wait for changes to settle before signaling or restarting")
+	stopTimeout := fs.Duration("stop-timeout", 10*time.Second, "time to wait for the process to exit on restart before killing it")
+	if err := fs.Parse(flagArgs); err != nil {
+		return err
+	}
+	if fs.NArg() != 0 || len(services) == 0 || len(command) == 0 {
+		return errors.New(usageText)
+	}
+
+	opts := execOptions{
+		services:    services,
+		restart:     *restart,
+		debounce:    *debounce,
+		stopTimeout: *stopTimeout,
+	}
+	var err error
+	if opts.keys.Case, err = render.ParseCase(*keyCase); err != nil {
+		return err
+	}
+	opts.keys.Prefix = *prefix
+	if opts.signal, err = parseSignal(*signalName); err != nil {
+		return err
+	}
+
+	path, err := exec.LookPath(command[0])
+	if err != nil {
+		return err
+	}
+
+	if !*watch {
+		configs, err := a.fetchConfigs(services)
+		if err != nil {
+			return err
+		}
+		env, err := configEnv(opts, configs)
+		if err != nil {
+			return err
+		}
+		return replaceProcess(path, command, mergeEnv(os.Environ(), env))
+	}
+
+	return a.superviseExec(opts, path, command)
+}
+
+// splitCommand 以第一个 "--" 分隔子命令参数与要运行的命令
+func splitCommand(args []string) ([]string, []string) {
+	for i, arg := range args {
+		if arg == "--" {
+			return args[:i], args[i+1:]
+		}
+	}
+	return args, nil
+}
+
+// execChild 运行中的子进程
+type execChild struct {
+	cmd  *exec.Cmd
+	done chan error
+}
+
+// superviseExec 以子进程方式运行命令, 转发收到的信号, 配置变化稳定 debounce 后发送信号或重启,
+// 子进程自行退出时以其退出码退出
+func (a *app) superviseExec(opts execOptions, path string, command []string) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	signals := make(chan os.Signal, 4)
+	signal.Notify(signals, forwardedSignals...)
+	defer signal.Stop(signals)
+
+	updates := make(chan configSet)
+	go a.watchConfigs(ctx, opts.services, updates)
+
+	var (
+		child    *execChild
+		done     <-chan error
+		current  []string
+		pending  []string
+		debounce *time.Timer
+		fire     <-chan time.Time
+	)
+	for {
+		select {
+		case configs := <-updates:
+			env, err := configEnv(opts, configs)
+			if err != nil {
+				if child == nil {
+					return err
+				}
+				fmt.Fprintln(os.Stderr, "nidavellirctl: ignoring config change:", err)
+				continue
+			}
+			if child == nil {
+				current = env
+				var err error
+				if child, err = startChild(path, command, current); err != nil {
+					return err
+				}
+				done = child.done
+				continue
+			}
+			pending = env
+			if debounce == nil {
+				debounce = time.NewTimer(opts.debounce)
+			} else {
+				debounce.Reset(opts.debounce)
+			}
+			fire = debounce.C
+
+		case <-fire:
+			fire = nil
+			if slices.Equal(pending, current) {
+				continue
+			}
+			current = pending
+			if !opts.restart {
+				fmt.Fprintf(os.Stderr, "nidavellirctl: configs changed, sending %v to pid %d\n", opts.signal, child.cmd.Process.Pid)
+				_ = child.cmd.Process.Signal(opts.signal)
+				continue
+			}
+
+			fmt.Fprintf(os.Stderr, "nidavellirctl: configs changed, restarting pid %d\n", child.cmd.Process.Pid)
+			child.stop(opts.stopTimeout)
+			var err error
+			if child, err = startChild(path, command, current); err != nil {
+				return err
+			}
+			done = child.done
+
+		case sig := <-signals:
+			if child == nil {
+				return fmt.Errorf("interrupted by %v", sig)
+			}
+			_ = child.cmd.Process.Signal(sig)
+
+		case err := <-done:
+			return childExitError(err)
+		}
+	}
+}
+
+// startChild 使用给定的配置环境变量启动子进程
+func startChild(path string, command, env []string) (*execChild, error) {
+	cmd := exec.Command(path, command[1:]...)
+	cmd.Args = command
+	cmd.Env = mergeEnv(os.Environ(), env)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	child := &execChild{cmd: cmd, done: make(chan error, 1)}
+	go func() {
+		child.done <- cmd.Wait()
+	}()
+	return child, nil
+}
+
+// stop 请求子进程退出, 超时后强制结束
+func (c *execChild) stop(timeout time.Duration) {
+	_ = terminate(c.cmd.Process)
+	select {
+	case <-c.done:
+	case <-time.After(timeout):
+		_ = c.cmd.Process.Kill()
+		<-c.done
+	}
+}
+
+// childExitError 将子进程的退出结果转换为退出码
+func childExitError(err error) error {
+	if err == nil {
+		return nil
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		if code := exitErr.ExitCode(); code > 0 {
+			return exitCode(code)
+		}
+		return exitCode(1)
+	}
+	return err
+}
+
+// configEnv 按服务顺序合并配置并转换为环境变量, 后面服务的同名配置覆盖前面的
+func configEnv(opts execOptions, configs configSet) ([]string, error) {
+	merged := make(map[string]interface{})
+	for _, service := range opts.services {
+		for key, value := range configs[service] {
+			merged[key] = value
+		}
+	}
+
+	entries := make([]render.Entry, 0, len(merged))
+	for key, value := range merged {
+		entries = append(entries, render.Entry{Key: key, Value: value})
+	}
+	return render.Env(entries, opts.keys)
+}
+
+// mergeEnv 合并环境变量, override中的变量覆盖base中的同名变量
+func mergeEnv(base, override []string) []string {
+	names := make(map[string]bool, len(override))
+	for _, kv := range override {
+		name, _, _ := strings.Cut(kv, "=")
+		names[name] = true
+	}
+
+	result := make([]string, 0, len(base)+len(override))
+	for _, kv := range base {
+		name, _, _ := strings.Cut(kv, "=")
+		if !names[name] {
+			result = append(result, kv)
+		}
+	}
+	return append(result, override...)
+}
+
+// parseSignal 解析信号名, 支持 HUP 与 SIGHUP 两种形式
+func parseSignal(name string) (os.Signal, error) {
+	sig, ok := execSignals[strings.TrimPrefix(strings.ToUpper(name), "SIG")]
+	if !ok {
+		return nil, fmt.Errorf("unsupported signal %q", name)
+	}
+	return sig, nil
+}
@@ -0,0 +1,38 @@
+//go:build windows
+
+package main
+
+import (
+	"context"
+	"os"
+	"os/exec"
+)
+
+// execSignals -signal 支持的信号, Windows 只能结束进程
+var execSignals = map[string]os.Signal{
+	"KILL": os.Kill,
+}
+
+// forwardedSignals 转发给子进程的信号
+var forwardedSignals = []os.Signal{os.Interrupt}
+
+// replaceProcess Windows 不支持替换当前进程, 以子进程方式运行并等待退出
+func replaceProcess(path string, args, env []string) error {
+	cmd := exec.Command(path, args[1:]...)
+	cmd.Args = args
+	cmd.Env = env
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return childExitError(cmd.Run())
+}
+
+// terminate 请求进程退出
+func terminate(p *os.Process) error {
+	return p.Kill()
+}
+
+// shellCommand 通过 cmd.exe 执行命令
+func shellCommand(ctx context.Context, command string) *exec.Cmd {
+	return exec.CommandContext(ctx, "cmd", "/C", command)
+}
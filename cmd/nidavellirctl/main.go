@@ -0,0 +1,127 @@
+// nidavellirctl Nidavellir 配置中心命令行客户端
+//
+// 基于gRPC接口, 支持通过TCP地址或Twig Unix Socket连接服务端。
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+)
+
+// globalOptions 全局参数
+type globalOptions struct {
+	contextName string
+	server      string
+	token       string
+	output      string
+	timeout     int
+}
+
+// command 子命令
+type command struct {
+	name  string
+	args  string
+	usage string
+	run   func(app *app, args []string) error
+}
+
+var commands = []command{
+	{"get", "<service> <key>", "get a config item", runGet},
+	{"set", "<service> <key> [value|-] [-f file] [-d desc] [-encrypt]", "set a config item, value from argument, file or stdin", runSet},
+	{"delete", "<service> [key]", "delete a config item or all configs of a service", runDelete},
+	{"services", "", "list all services", runServices},
+	{"dump", "<service>", "dump all configs of a service", runDump},
+	{"watch", "[service [key]] [-match selector]... [-rev revision | -snapshot]", "watch config changes", runWatch},
+	{"search", "<query> [-regex] [-i] [-field f]... [-service s]... [-label k=v]... [-limit n]", "search keys, values and descriptions", runSearch},
+	{"diff", "<side> <side> [-u] [-exit-code]", "compare configs of services, environments, backups, files or revisions", runDiff},
+	{"exec", "-service <service>... [-key-case c] [-prefix p] [-watch [-signal sig | -restart]] -- <command> [args]", "run a command with service configs as environment variables", runExec},
+	{"agent", "-config <file> [-once]", "render templates and per-key files from configs, re-render on changes", runAgent},
+	{"sync", "<dir> [-prune] [-dry-run] [-exit-code]", "sync configs from a directory of per-service TOML/YAML files", runSync},
+	{"export", "[-format json|toml] [-service s]... [-f file]", "export configs and metadata of all services as an archive", runExport},
+	{"import", "<file|-> [-mode merge|replace] [-service s]... [-dry-run]", "import an archive produced by export", runImport},
+	{"backup", "list | create | restore <name> [-service s]... [-mode replace|merge] [-dry-run] [-yes]", "list, create or restore server-side backups, restore previews changes first", runBackup},
+	{"draft", "list | show <id> [-u] | create -m desc [-set s/k=v]... [-secret s/k=v]... [-delete s/k]... | update <id> | approve <id> | publish <id> [-yes] | abandon <id>", "review and publish change sets, required for protected services", runDraft},
+	{"clients", "[-drift]", "list connected clients, -drift shows only clients behind", runClients},
+}
+
+func main() {
+	opts := &globalOptions{}
+	fs := flag.NewFlagSet("nidavellirctl", flag.ExitOnError)
+	fs.StringVar(&opts.contextName, "context", "", "context name in the context file")
+	fs.StringVar(&opts.server, "server", "", "server address, host:port or unix:///path (overrides context)")
+	fs.StringVar(&opts.token, "token", "", "access token (overrides context)")
+	fs.StringVar(&opts.output, "o", outputTable, "output format: table, json, env")
+	fs.IntVar(&opts.timeout, "timeout", 5, "request timeout in seconds")
+	fs.Usage = func() { usage(fs) }
+	_ = fs.Parse(os.Args[1:])
+
+	if fs.NArg() == 0 {
+		usage(fs)
+		os.Exit(2)
+	}
+
+	name, args := fs.Arg(0), fs.Args()[1:]
+	for _, cmd := range commands {
+		if cmd.name != name {
+			continue
+		}
+		a, err := newApp(opts)
+		if err != nil {
+			fatal(err)
+		}
+		err = cmd.run(a, args)
+		a.close()
+		var code exitCode
+		if errors.As(err, &code) {
+			os.Exit(int(code))
+		}
+		if err != nil {
+			fatal(err)
+		}
+		return
+	}
+
+	fmt.Fprintf(os.Stderr, "unknown command %q\n\n", name)
+	usage(fs)
+	os.Exit(2)
+}
+
+// usage 打印帮助信息
+func usage(fs *flag.FlagSet) {
+	fmt.Fprintln(os.Stderr, "Usage: nidavellirctl [options] <command> [args]")
+	fmt.Fprintln(os.Stderr, "\nCommands:")
+	tw := tabwriter.NewWriter(os.Stderr, 0, 4, 2, ' ', 0)
+	for _, cmd := range commands {
+		fmt.Fprintf(tw, "  %s %s\t%s\n", cmd.name, cmd.args, cmd.usage)
+	}
+	_ = tw.Flush()
+	fmt.Fprintln(os.Stderr, "\nOptions:")
+	fs.PrintDefaults()
+	fmt.Fprintf(os.Stderr, "\nContext file: %s (override with NIDAVELLIR_CONTEXT_FILE)\n", contextFilePath())
+}
+
+// fatal 打印错误并退出
+func fatal(err error) {
+	fmt.Fprintln(os.Stderr, "Error:", err)
+	os.Exit(1)
+}
+
+// parseInterspersed 解析允许参数与选项交错出现的子命令参数
+func parseInterspersed(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		// 单独的 "-" 表示标准输入, 作为普通参数处理
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
@@ -0,0 +1,447 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	grpcConfig "nidavellir/api/proto"
+)
+
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+	outputEnv   = "env"
+)
+
+// printer 按指定格式输出结果
+type printer struct {
+	w      io.Writer
+	format string
+}
+
+// newPrinter 创建输出器
+func newPrinter(w io.Writer, format string) (*printer, error) {
+	switch format {
+	case outputTable, outputJSON, outputEnv:
+		return &printer{w: w, format: format}, nil
+	default:
+		return nil, fmt.Errorf("unsupported output format %q (table, json, env)", format)
+	}
+}
+
+// jsonItem json输出的配置项, value保持原始JSON
+type jsonItem struct {
+	Key         string          `json:"key"`
+	Value       json.RawMessage `json:"value"`
+	ServiceName string          `json:"service_name"`
+	Description string          `json:"description,omitempty"`
+	Encrypt     bool            `json:"encrypt,omitempty"`
+	CreatedAt   int64           `json:"created_at"`
+	UpdatedAt   int64           `json:"updated_at"`
+}
+
+// printItems 输出配置项列表
+func (p *printer) printItems(items []*grpcConfig.ConfigItem) error {
+	sortItems(items)
+
+	switch p.format {
+	case outputJSON:
+		out := make([]jsonItem, 0, len(items))
+		for _, item := range items {
+			out = append(out, toJSONItem(item))
+		}
+		return p.writeJSON(out)
+	case outputEnv:
+		for _, item := range items {
+			fmt.Fprintf(p.w, "%s=%s\n", item.Key, envQuote(plainValue(item.Value)))
+		}
+		return nil
+	default:
+		tw := tabwriter.NewWriter(p.w, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "SERVICE\tKEY\tVALUE\tDESCRIPTION\tUPDATED")
+		for _, item := range items {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n",
+				item.ServiceName, item.Key, plainValue(item.Value), item.Description, formatTime(item.UpdatedAt))
+		}
+		return tw.Flush()
+	}
+}
+
+// printItem 输出单个配置项
+func (p *printer) printItem(item *grpcConfig.ConfigItem) error {
+	if p.format == outputJSON {
+		return p.writeJSON(toJSONItem(item))
+	}
+	return p.printItems([]*grpcConfig.ConfigItem{item})
+}
+
+// printServices 输出服务列表
+func (p *printer) printServices(services []string) error {
+	sort.Strings(services)
+
+	switch p.format {
+	case outputJSON:
+		return p.writeJSON(services)
+	default:
+		if p.format == outputTable {
+			fmt.Fprintln(p.w, "SERVICE")
+		}
+		for _, service := range services {
+			fmt.Fprintln(p.w, service)
+		}
+		return nil
+	}
+}
+
+// printClients 输出客户端列表
+func (p *printer) printClients(clients []*grpcConfig.ClientInfo) error {
+	if p.format == outputJSON {
+		if clients == nil {
+			clients = []*grpcConfig.ClientInfo{}
+		}
+		return p.writeJSON(clients)
+	}
+
+	tw := tabwriter.NewWriter(p.w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tNAME\tTRANSPORT\tPEER\tSELECTORS\tCONNECTED\tAPPLIED\tREQUIRED\tBEHIND")
+	for _, c := range clients {
+		peer := c.Peer
+		if c.Pid > 0 {
+			peer = fmt.Sprintf("pid:%d", c.Pid)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%d\t%d\t%s\n",
+			c.Id, c.Name, c.Transport, peer, strings.Join(c.Selectors, ","), formatTime(c.ConnectedAt),
+			c.AppliedRevision, c.RequiredRevision, strings.Join(c.BehindServices, ","))
+	}
+	return tw.Flush()
+}
+
+// printBackups 输出备份列表
+func (p *printer) printBackups(backups []*grpcConfig.BackupInfo) error {
+	if p.format == outputJSON {
+		if backups == nil {
+			backups = []*grpcConfig.BackupInfo{}
+		}
+		return p.writeJSON(backups)
+	}
+
+	tw := tabwriter.NewWriter(p.w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tREVISION\tCREATED\tSIZE\tENCRYPTED")
+	for _, b := range backups {
+		fmt.Fprintf(tw, "%s\t%d\t%s\t%d\t%t\n", b.Name, b.Revision, formatTime(b.CreatedAt), b.Size, b.Encrypted)
+	}
+	return tw.Flush()
+}
+
+// printDrafts 输出草稿列表
+func (p *printer) printDrafts(drafts []*grpcConfig.Draft) error {
+	if p.format == outputJSON {
+		if drafts == nil {
+			drafts = []*grpcConfig.Draft{}
+		}
+		return p.writeJSON(drafts)
+	}
+
+	tw := tabwriter.NewWriter(p.w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tSTATUS\tAUTHOR\tAPPROVALS\tOPERATIONS\tUPDATED\tDESCRIPTION")
+	for _, d := range drafts {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%d/%d\t%d\t%s\t%s\n", d.Id, d.Status, d.Author,
+			len(d.Approvals), d.RequiredApprovals, len(d.Operations), formatTime(d.UpdatedAt), d.Description)
+	}
+	return tw.Flush()
+}
+
+// printDraftDiff 输出草稿的信息与每个服务的差异
+func (p *printer) printDraftDiff(resp *grpcConfig.GetDraftResponse, unified bool) error {
+	if p.format == outputJSON {
+		return p.writeJSON(resp)
+	}
+
+	d := resp.Draft
+	fmt.Fprintf(p.w, "draft %s (%s) by %s\n", d.Id, d.Status, d.Author)
+	if d.Description != "" {
+		fmt.Fprintf(p.w, "    %s\n", d.Description)
+	}
+	if len(d.Editors) > 0 {
+		fmt.Fprintf(p.w, "edited by %s\n", strings.Join(d.Editors, ", "))
+	}
+	approvers := make([]string, 0, len(d.Approvals))
+	for _, approval := range d.Approvals {
+		approvers = append(approvers, approval.Approver)
+	}
+	fmt.Fprintf(p.w, "approvals: %d/%d", len(d.Approvals), d.RequiredApprovals)
+	if len(approvers) > 0 {
+		fmt.Fprintf(p.w, " (%s)", strings.Join(approvers, ", "))
+	}
+	fmt.Fprintln(p.w)
+	if d.Revision > 0 {
+		fmt.Fprintf(p.w, "published by %s at revision %d\n", d.ClosedBy, d.Revision)
+	}
+
+	for _, diff := range resp.Diffs {
+		fmt.Fprintln(p.w)
+		if unified {
+			fmt.Fprint(p.w, diff.Unified)
+			continue
+		}
+		if err := p.printDiff(diff.Left, diff.Right, diffEntries(diff.Changes)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// printEvent 输出一条监听事件
+func (p *printer) printEvent(resp *grpcConfig.WatchConfigResponse) error {
+	item := resp.Config
+	if item == nil {
+		item = &grpcConfig.ConfigItem{}
+	}
+
+	switch p.format {
+	case outputJSON:
+		event := struct {
+			EventType  string    `json:"event_type"`
+			Revision   int64     `json:"revision"`
+			Config     *jsonItem `json:"config,omitempty"`
+			PrevConfig *jsonItem `json:"prev_config,omitempty"`
+		}{EventType: resp.EventType, Revision: resp.Revision}
+		if resp.Config != nil {
+			config := toJSONItem(item)
+			event.Config = &config
+		}
+		if resp.PrevConfig != nil {
+			prev := toJSONItem(resp.PrevConfig)
+			event.PrevConfig = &prev
+		}
+		data, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(p.w, string(data))
+	case outputEnv:
+		if resp.Config == nil {
+			return nil
+		}
+		if resp.EventType == "DELETE" {
+			fmt.Fprintf(p.w, "# deleted %s\n", item.Key)
+			return nil
+		}
+		fmt.Fprintf(p.w, "%s=%s\n", item.Key, envQuote(plainValue(item.Value)))
+	default:
+		if resp.EventType == "SNAPSHOT_END" {
+			fmt.Fprintf(p.w, "--- snapshot at revision %d ---\n", resp.Revision)
+			return nil
+		}
+		if resp.Config == nil {
+			// 进度通知不输出
+			return nil
+		}
+		fmt.Fprintf(p.w, "%s\t%d\t%-6s\t%s/%s\t%s\n",
+			time.Now().Format(time.TimeOnly), resp.Revision, resp.EventType, item.ServiceName, item.Key, plainValue(item.Value))
+	}
+
+	return nil
+}
+
+// diffEntry 两个服务之间的差异项
+type diffEntry struct {
+	Key   string `json:"key"`
+	Op    string `json:"op"` // added, removed, changed
+	Left  string `json:"left,omitempty"`
+	Right string `json:"right,omitempty"`
+}
+
+// printDiff 输出差异
+func (p *printer) printDiff(left, right string, entries []diffEntry) error {
+	switch p.format {
+	case outputJSON:
+		return p.writeJSON(struct {
+			Left    string      `json:"left"`
+			Right   string      `json:"right"`
+			Changes []diffEntry `json:"changes"`
+		}{left, right, entries})
+	case outputEnv:
+		for _, e := range entries {
+			switch e.Op {
+			case "removed":
+				fmt.Fprintf(p.w, "-%s=%s\n", e.Key, envQuote(e.Left))
+			case "added":
+				fmt.Fprintf(p.w, "+%s=%s\n", e.Key, envQuote(e.Right))
+			default:
+				fmt.Fprintf(p.w, "-%s=%s\n", e.Key, envQuote(e.Left))
+				fmt.Fprintf(p.w, "+%s=%s\n", e.Key, envQuote(e.Right))
+			}
+		}
+		return nil
+	default:
+		tw := tabwriter.NewWriter(p.w, 0, 4, 2, ' ', 0)
+		fmt.Fprintf(tw, "KEY\tOP\t%s\t%s\n", left, right)
+		for _, e := range entries {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", e.Key, e.Op, e.Left, e.Right)
+		}
+		return tw.Flush()
+	}
+}
+
+// printSync 输出同步计划, table 格式每个变更一行, env 格式为 +、~、- 开头的差异
+func (p *printer) printSync(resp *grpcConfig.SyncResponse) error {
+	if p.format == outputJSON {
+		return p.writeJSON(map[string]interface{}{
+			"changes":  syncChangesJSON(resp.Changes),
+			"extra":    resp.Extra,
+			"revision": resp.Revision,
+			"applied":  resp.Applied,
+		})
+	}
+	return p.printChanges(resp.Changes, resp.Applied, resp.Revision)
+}
+
+// printImport 输出导入计划或结果, 表格格式下列出元数据被覆盖的服务
+func (p *printer) printImport(resp *grpcConfig.ImportResponse) error {
+	if p.format == outputJSON {
+		return p.writeJSON(map[string]interface{}{
+			"mode":     resp.Mode,
+			"changes":  syncChangesJSON(resp.Changes),
+			"extra":    resp.Extra,
+			"metadata": resp.Metadata,
+			"skipped":  resp.Skipped,
+			"revision": resp.Revision,
+			"applied":  resp.Applied,
+		})
+	}
+	if err := p.printChanges(resp.Changes, resp.Applied, resp.Revision); err != nil {
+		return err
+	}
+	if p.format != outputEnv && len(resp.Metadata) > 0 {
+		fmt.Fprintf(p.w, "metadata: %s\n", strings.Join(resp.Metadata, ", "))
+	}
+	return nil
+}
+
+// syncChangesJSON 转换同步计划中的变更为json输出结构
+func syncChangesJSON(changes []*grpcConfig.SyncChange) []interface{} {
+	result := make([]interface{}, 0, len(changes))
+	for _, change := range changes {
+		entry := map[string]interface{}{
+			"action":       change.Action,
+			"service_name": change.ServiceName,
+			"key":          change.Key,
+		}
+		if len(change.Fields) > 0 {
+			entry["fields"] = change.Fields
+		}
+		if change.Config != nil {
+			entry["config"] = toJSONItem(change.Config)
+		}
+		if change.Current != nil {
+			entry["current"] = toJSONItem(change.Current)
+		}
+		result = append(result, entry)
+	}
+	return result
+}
+
+// printChanges 以 env 或表格格式输出同步计划中的变更
+func (p *printer) printChanges(changes []*grpcConfig.SyncChange, applied bool, revision int64) error {
+	if p.format == outputEnv {
+		for _, change := range changes {
+			name := change.ServiceName + "/" + change.Key
+			switch change.Action {
+			case "create":
+				fmt.Fprintf(p.w, "+%s=%s\n", name, envQuote(plainValue(change.Config.Value)))
+			case "delete":
+				fmt.Fprintf(p.w, "-%s=%s\n", name, envQuote(plainValue(change.Current.Value)))
+			default:
+				fmt.Fprintf(p.w, "~%s=%s\n", name, envQuote(plainValue(change.Config.Value)))
+			}
+		}
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(p.w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "ACTION\tSERVICE\tKEY\tFIELDS\tCURRENT\tDESIRED")
+	for _, change := range changes {
+		var current, desired string
+		if change.Current != nil {
+			current = plainValue(change.Current.Value)
+		}
+		if change.Config != nil {
+			desired = plainValue(change.Config.Value)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", change.Action, change.ServiceName, change.Key,
+			strings.Join(change.Fields, ","), current, desired)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	if applied {
+		fmt.Fprintf(p.w, "applied %d changes at revision %d\n", len(changes), revision)
+	}
+	return nil
+}
+
+// writeJSON 以缩进格式输出JSON
+func (p *printer) writeJSON(v interface{}) error {
+	enc := json.NewEncoder(p.w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// toJSONItem 转换为json输出结构
+func toJSONItem(item *grpcConfig.ConfigItem) jsonItem {
+	value := json.RawMessage(item.Value)
+	if !json.Valid(value) {
+		value, _ = json.Marshal(item.Value)
+	}
+	return jsonItem{
+		Key:         item.Key,
+		Value:       value,
+		ServiceName: item.ServiceName,
+		Description: item.Description,
+		Encrypt:     item.Encrypt,
+		CreatedAt:   item.CreatedAt,
+		UpdatedAt:   item.UpdatedAt,
+	}
+}
+
+// plainValue 将服务端返回的JSON值转换为便于阅读的文本, 字符串去掉引号
+func plainValue(raw string) string {
+	var s string
+	if err := json.Unmarshal([]byte(raw), &s); err == nil {
+		return s
+	}
+	return raw
+}
+
+// envQuote 在需要时为env格式的值加引号
+func envQuote(value string) string {
+	if value == "" || strings.ContainsAny(value, " \t\n\"'#$\\`") {
+		return strconv.Quote(value)
+	}
+	return value
+}
+
+// formatTime 格式化时间戳
+func formatTime(ts int64) string {
+	if ts <= 0 {
+		return "-"
+	}
+	return time.Unix(ts, 0).Format(time.DateTime)
+}
+
+// sortItems 按服务和键排序
+func sortItems(items []*grpcConfig.ConfigItem) {
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].ServiceName != items[j].ServiceName {
+			return items[i].ServiceName < items[j].ServiceName
+		}
+		return items[i].Key < items[j].Key
+	})
+}
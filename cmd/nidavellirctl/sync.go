@@ -0,0 +1,73 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	grpcConfig "nidavellir/api/proto"
+	"nidavellir/internal/gitops"
+)
+
+// driftExitCode -exit-code 时存储与文件不一致的退出码
+const driftExitCode = 2
+
+// runSync 将目录中的服务配置文件同步到配置中心, -dry-run 时只输出计划
+func runSync(a *app, args []string) error {
+	fs := flag.NewFlagSet("sync", flag.ContinueOnError)
+	prune := fs.Bool("prune", false, "delete keys that are not declared in files")
+	dryRun := fs.Bool("dry-run", false, "print the plan without applying it")
+	driftExit := fs.Bool("exit-code", false, "exit with status 2 when live configs differ from files")
+	args, err := parseInterspersed(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(args) != 1 {
+		return errors.New("usage: sync <dir> [-prune] [-dry-run] [-exit-code]")
+	}
+
+	services, err := gitops.Load(args[0])
+	if err != nil {
+		return err
+	}
+
+	req := &grpcConfig.SyncRequest{Prune: *prune, DryRun: *dryRun}
+	for _, service := range services {
+		svc := &grpcConfig.SyncService{Name: service.Name, Unmanaged: service.Unmanaged}
+		for key, cfg := range service.Configs {
+			value, err := json.Marshal(cfg.Value)
+			if err != nil {
+				return fmt.Errorf("%s/%s: %w", service.Name, key, err)
+			}
+			svc.Configs = append(svc.Configs, &grpcConfig.SyncConfig{
+				Key:         key,
+				Value:       string(value),
+				Description: cfg.Description,
+				Encrypt:     cfg.Encrypt,
+			})
+		}
+		req.Services = append(req.Services, svc)
+	}
+
+	ctx, cancel := a.requestContext()
+	defer cancel()
+
+	resp, err := a.client.Sync(ctx, req)
+	if err != nil {
+		return err
+	}
+	if err := a.printer.printSync(resp); err != nil {
+		return err
+	}
+
+	if len(resp.Extra) > 0 && !*prune {
+		fmt.Fprintf(os.Stderr, "%d keys are not declared in files, use -prune to delete them\n", len(resp.Extra))
+	}
+	drift := len(resp.Changes) > 0 || (*prune && len(resp.Extra) > 0)
+	if *driftExit && drift {
+		return exitCode(driftExitCode)
+	}
+	return nil
+}
@@ -0,0 +1,310 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"nidavellir/internal/render"
+)
+
+// defaultCommandTimeout 变更后执行命令的默认超时时间
+const defaultCommandTimeout = 30 * time.Second
+
+// renderContext 一次渲染使用的配置, 记录模板引用了哪些尚未加载的服务
+type renderContext struct {
+	configs configSet
+	// loaded 已加载的服务, 未加载服务的配置一律视为未知
+	loaded  map[string]bool
+	missing map[string]bool
+}
+
+// newRenderContext 创建渲染上下文
+func newRenderContext(configs configSet, loaded map[string]bool) *renderContext {
+	return &renderContext{configs: configs, loaded: loaded, missing: make(map[string]bool)}
+}
+
+// funcs 模板函数
+func (rc *renderContext) funcs() template.FuncMap {
+	return template.FuncMap{
+		"key":          rc.key,
+		"keyOrDefault": rc.keyOrDefault,
+		"service":      rc.service,
+		"toJSON":       toJSON,
+		"env":          os.Getenv,
+	}
+}
+
+// use 判断服务是否已加载, 未加载时记录下来
+func (rc *renderContext) use(service string) bool {
+	if rc.loaded[service] {
+		return true
+	}
+	rc.missing[service] = true
+	return false
+}
+
+// key 配置值的文本形式, 配置不存在时报错
+func (rc *renderContext) key(service, key string) (string, error) {
+	if !rc.use(service) {
+		return "", nil
+	}
+	value, ok := rc.configs[service][key]
+	if !ok {
+		return "", fmt.Errorf("config %s/%s not found", service, key)
+	}
+	return render.Text(value), nil
+}
+
+// keyOrDefault 配置值的文本形式, 配置不存在时返回默认值
+func (rc *renderContext) keyOrDefault(service, key, def string) string {
+	if !rc.use(service) {
+		return def
+	}
+	value, ok := rc.configs[service][key]
+	if !ok {
+		return def
+	}
+	return render.Text(value)
+}
+
+// service 服务的全部配置, 值保持JSON解码后的类型, range 时按键排序
+func (rc *renderContext) service(service string) map[string]interface{} {
+	if !rc.use(service) {
+		return map[string]interface{}{}
+	}
+	configs := rc.configs[service]
+	if configs == nil {
+		return map[string]interface{}{}
+	}
+	return configs
+}
+
+// missingServices 按名称排序的未加载服务
+func (rc *renderContext) missingServices() []string {
+	services := make([]string, 0, len(rc.missing))
+	for service := range rc.missing {
+		services = append(services, service)
+	}
+	sort.Strings(services)
+	return services
+}
+
+// toJSON 将值输出为紧凑的JSON
+func toJSON(value interface{}) (string, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// templateConfig 模板渲染配置
+type templateConfig struct {
+	Source      string `mapstructure:"source"`
+	Destination string `mapstructure:"destination"`
+	// Perms 目标文件的权限, 八进制, 默认 0644
+	Perms string `mapstructure:"perms"`
+	// Command 目标文件内容变化后执行的命令
+	Command        string        `mapstructure:"command"`
+	CommandTimeout time.Duration `mapstructure:"command_timeout"`
+
+	tmpl *template.Template
+	mode os.FileMode
+}
+
+// load 校验配置并解析模板
+func (t *templateConfig) load() error {
+	if t.Source == "" || t.Destination == "" {
+		return errors.New("template source and destination are required")
+	}
+	mode, err := parsePerms(t.Perms, 0o644)
+	if err != nil {
+		return fmt.Errorf("template %s: %w", t.Source, err)
+	}
+	t.mode = mode
+
+	data, err := os.ReadFile(t.Source)
+	if err != nil {
+		return err
+	}
+	t.tmpl, err = template.New(filepath.Base(t.Source)).
+		Option("missingkey=error").
+		Funcs(newRenderContext(nil, nil).funcs()).
+		Parse(string(data))
+	if err != nil {
+		return fmt.Errorf("failed to parse template %s: %w", t.Source, err)
+	}
+	return nil
+}
+
+// render 渲染模板并写入目标文件, 返回文件内容是否变化。
+// 模板引用了未加载的服务时不写入, 返回这些服务, 加载后需重新渲染
+func (t *templateConfig) render(configs configSet, loaded map[string]bool) (bool, []string, error) {
+	rc := newRenderContext(configs, loaded)
+	var buf bytes.Buffer
+	err := t.tmpl.Funcs(rc.funcs()).Execute(&buf, nil)
+	if missing := rc.missingServices(); len(missing) > 0 {
+		// 未加载的服务可能导致渲染失败, 加载后再判断
+		return false, missing, nil
+	}
+	if err != nil {
+		return false, nil, fmt.Errorf("failed to render %s: %w", t.Source, err)
+	}
+
+	changed, err := writeFileAtomic(t.Destination, buf.Bytes(), t.mode)
+	if err != nil {
+		return false, nil, fmt.Errorf("failed to write %s: %w", t.Destination, err)
+	}
+	return changed, nil, nil
+}
+
+// filesConfig 每个配置键写入一个文件, 适用于 tmpfs 目录
+type filesConfig struct {
+	Service   string `mapstructure:"service"`
+	Directory string `mapstructure:"directory"`
+	// Perms 文件的权限, 八进制, 默认 0600
+	Perms string `mapstructure:"perms"`
+	// Command 文件变化后执行的命令
+	Command        string        `mapstructure:"command"`
+	CommandTimeout time.Duration `mapstructure:"command_timeout"`
+
+	mode os.FileMode
+	// written 本进程写入的文件, 配置删除时删除对应的文件
+	written map[string]bool
+}
+
+// load 校验配置
+func (f *filesConfig) load() error {
+	if f.Service == "" || f.Directory == "" {
+		return errors.New("files service and directory are required")
+	}
+	mode, err := parsePerms(f.Perms, 0o600)
+	if err != nil {
+		return fmt.Errorf("files %s: %w", f.Service, err)
+	}
+	f.mode = mode
+	f.written = make(map[string]bool)
+	return nil
+}
+
+// sync 将服务的配置同步到目录, 返回是否有文件变化, 服务未加载时不做处理
+func (f *filesConfig) sync(configs configSet, loaded map[string]bool) (bool, error) {
+	if !loaded[f.Service] {
+		return false, nil
+	}
+	if err := os.MkdirAll(f.Directory, 0o700); err != nil {
+		return false, err
+	}
+
+	var (
+		changed bool
+		errs    []error
+	)
+	names := make(map[string]bool, len(configs[f.Service]))
+	for key, value := range configs[f.Service] {
+		if key == "." || key == ".." || strings.ContainsAny(key, `/\`) {
+			errs = append(errs, fmt.Errorf("config %s/%s cannot be used as a file name", f.Service, key))
+			continue
+		}
+		names[key] = true
+		c, err := writeFileAtomic(filepath.Join(f.Directory, key), []byte(render.Text(value)), f.mode)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		changed = changed || c
+	}
+
+	for name := range f.written {
+		if names[name] {
+			continue
+		}
+		if err := os.Remove(filepath.Join(f.Directory, name)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			errs = append(errs, err)
+			continue
+		}
+		changed = true
+	}
+	f.written = names
+	return changed, errors.Join(errs...)
+}
+
+// writeFileAtomic 内容变化时先写入同目录的临时文件再重命名, 读取方不会看到写了一半的文件。
+// 内容相同只是权限不同时只修改权限, 返回内容是否变化
+func writeFileAtomic(path string, data []byte, mode os.FileMode) (bool, error) {
+	if current, err := os.ReadFile(path); err == nil && bytes.Equal(current, data) {
+		info, err := os.Stat(path)
+		if err == nil && info.Mode().Perm() != mode {
+			return false, os.Chmod(path, mode)
+		}
+		return false, err
+	}
+
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return false, err
+	}
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".tmp*")
+	if err != nil {
+		return false, err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return false, err
+	}
+	if err := tmp.Chmod(mode); err != nil {
+		tmp.Close()
+		return false, err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return false, err
+	}
+	if err := tmp.Close(); err != nil {
+		return false, err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// runCommand 通过shell执行命令, 超时后结束
+func runCommand(ctx context.Context, command string, timeout time.Duration) error {
+	if timeout <= 0 {
+		timeout = defaultCommandTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	cmd := shellCommand(ctx, command)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("command %q failed: %w", command, err)
+	}
+	return nil
+}
+
+// parsePerms 解析八进制的文件权限, 为空时使用默认值
+func parsePerms(perms string, def os.FileMode) (os.FileMode, error) {
+	if perms == "" {
+		return def, nil
+	}
+	var mode uint32
+	if _, err := fmt.Sscanf(perms, "%o", &mode); err != nil || mode > 0o777 {
+		return 0, fmt.Errorf("invalid perms %q", perms)
+	}
+	return os.FileMode(mode), nil
+}
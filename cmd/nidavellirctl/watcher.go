@@ -0,0 +1,160 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	grpcConfig "nidavellir/api/proto"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// watchReconnectInterval 监听中断后重新连接的间隔
+const watchReconnectInterval = time.Second
+
+// configSet 按服务与配置键组织的配置值, 值为解码后的JSON
+type configSet map[string]map[string]interface{}
+
+// set 记录配置项的值
+func (s configSet) set(item *grpcConfig.ConfigItem) {
+	if item == nil {
+		return
+	}
+	if s[item.ServiceName] == nil {
+		s[item.ServiceName] = make(map[string]interface{})
+	}
+	s[item.ServiceName][item.Key] = decodeValue(item.Value)
+}
+
+// clone 复制配置集合, 配置值不会被修改因此不做深拷贝
+func (s configSet) clone() configSet {
+	result := make(configSet, len(s))
+	for service, configs := range s {
+		result[service] = make(map[string]interface{}, len(configs))
+		for key, value := range configs {
+			result[service][key] = value
+		}
+	}
+	return result
+}
+
+// fetchConfigs 读取服务的当前配置
+func (a *app) fetchConfigs(services []string) (configSet, error) {
+	result := make(configSet, len(services))
+	for _, service := range services {
+		items, err := a.serviceConfigs(service)
+		if err != nil {
+			return nil, err
+		}
+		result[service] = make(map[string]interface{}, len(items))
+		for _, item := range items {
+			result.set(item)
+		}
+	}
+	return result, nil
+}
+
+// watchConfigs 监听服务配置, 快照完成及每次变更后发送全部配置的副本, 断开后按已处理的版本重连, 直到ctx结束
+func (a *app) watchConfigs(ctx context.Context, services []string, updates chan<- configSet) {
+	w := &configWatcher{services: services, updates: updates}
+	for {
+		err := w.watch(ctx, a.client)
+		if ctx.Err() != nil {
+			return
+		}
+		if status.Code(err) == codes.OutOfRange {
+			// 版本已被压缩, 重新获取快照
+			w.revision = 0
+		}
+		fmt.Fprintln(os.Stderr, "nidavellirctl: watch interrupted, reconnecting:", err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(watchReconnectInterval):
+		}
+	}
+}
+
+// configWatcher 维护监听到的服务配置
+type configWatcher struct {
+	services []string
+	updates  chan<- configSet
+	configs  configSet
+	revision int64
+}
+
+// watch 建立一次监听, 首次连接或需要重新同步时先读取快照, 之后从已处理的版本继续
+func (w *configWatcher) watch(ctx context.Context, client grpcConfig.ConfigServiceClient) error {
+	req := &grpcConfig.WatchConfigRequest{Selectors: w.services}
+	if w.revision == 0 {
+		req.Snapshot = true
+	} else {
+		req.StartRevision = w.revision + 1
+	}
+
+	// 等待连接就绪, 服务端重启期间不会立即失败
+	stream, err := client.WatchConfig(ctx, req, grpc.WaitForReady(true))
+	if err != nil {
+		return err
+	}
+
+	var snapshot configSet
+	for {
+		resp, err := stream.Recv()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return errors.New("stream closed by server")
+			}
+			return err
+		}
+
+		switch resp.EventType {
+		case "SNAPSHOT":
+			if snapshot == nil {
+				snapshot = make(configSet)
+			}
+			snapshot.set(resp.Config)
+			continue
+		case "SNAPSHOT_END":
+			if snapshot == nil {
+				snapshot = make(configSet)
+			}
+			w.configs, snapshot = snapshot, nil
+		case "PUT":
+			w.configs.set(resp.Config)
+		case "DELETE":
+			if resp.Config != nil {
+				delete(w.configs[resp.Config.ServiceName], resp.Config.Key)
+			}
+		case "PROGRESS":
+			w.revision = resp.Revision
+			continue
+		default:
+			continue
+		}
+		w.revision = resp.Revision
+
+		select {
+		case w.updates <- w.configs.clone():
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// decodeValue 将服务端返回的JSON值解码, 无法解码时按字符串处理
+func decodeValue(raw string) interface{} {
+	var value interface{}
+	if err := json.Unmarshal([]byte(raw), &value); err != nil {
+		return raw
+	}
+	return value
+}
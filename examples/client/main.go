@@ -10,9 +10,7 @@ import (
 	"net/http"
 	"time"
 
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
-	"nidavellir/api/proto/config"
+	"nidavellir/pkg/client"
 )
 
 // HTTPClient HTTP客户端示例
@@ -94,98 +92,6 @@ func (c *HTTPClient) GetConfig(service, key string) (map[string]interface{}, err
 	return result, nil
 }
 
-// GRPCClient gRPC客户端示例
-type GRPCClient struct {
-	client config.ConfigServiceClient
-	conn   *grpc.ClientConn
-}
-
-// NewGRPCClient 创建gRPC客户端
-func NewGRPCClient(address string) (*GRPCClient, error) {
-	conn, err := grpc.Dial(address, grpc.WithTransportCredentials(insecure.NewCredentials()))
-	if err != nil {
-		return nil, err
-	}
-	
-	client := config.NewConfigServiceClient(conn)
-	
-	return &GRPCClient{
-		client: client,
-		conn:   conn,
-	}, nil
-}
-
-// Close 关闭连接
-func (c *GRPCClient) Close() error {
-	return c.conn.Close()
-}
-
-// SetConfig 设置配置
-func (c *GRPCClient) SetConfig(ctx context.Context, service, key, value, description string) error {
-	req := &config.SetConfigRequest{
-		ServiceName: service,
-		Key:         key,
-		Value:       value,
-		Description: description,
-	}
-	
-	resp, err := c.client.SetConfig(ctx, req)
-	if err != nil {
-		return err
-	}
-	
-	if !resp.Success {
-		return fmt.Errorf("failed to set config: %s", resp.Message)
-	}
-	
-	return nil
-}
-
-// GetConfig 获取配置
-func (c *GRPCClient) GetConfig(ctx context.Context, service, key string) (*config.ConfigItem, error) {
-	req := &config.GetConfigRequest{
-		ServiceName: service,
-		Key:         key,
-	}
-	
-	resp, err := c.client.GetConfig(ctx, req)
-	if err != nil {
-		return nil, err
-	}
-	
-	if !resp.Found {
-		return nil, fmt.Errorf("config not found")
-	}
-	
-	return resp.Config, nil
-}
-
-// WatchConfig 监听配置变化
-func (c *GRPCClient) WatchConfig(ctx context.Context, service, key string) error {
-	req := &config.WatchConfigRequest{
-		ServiceName: service,
-		Key:         key,
-	}
-	
-	stream, err := c.client.WatchConfig(ctx, req)
-	if err != nil {
-		return err
-	}
-	
-	for {
-		resp, err := stream.Recv()
-		if err != nil {
-			return err
-		}
-		
-		fmt.Printf("配置变化: %s - %s/%s = %s\n", 
-			resp.EventType, 
-			resp.Config.ServiceName, 
-			resp.Config.Key, 
-			resp.Config.Value)
-	}
-}
-
 func main() {
 	fmt.Println("Nidavellir 配置中心客户端示例")
 	fmt.Println("================================")
@@ -210,41 +116,43 @@ func main() {
 	
 	// gRPC客户端示例
 	fmt.Println("\n2. gRPC客户端示例")
-	grpcClient, err := NewGRPCClient("localhost:9090")
+	grpcClient, err := client.New("localhost:9090")
 	if err != nil {
 		log.Printf("创建gRPC客户端失败: %v", err)
 		return
 	}
 	defer grpcClient.Close()
-	
+
 	ctx := context.Background()
-	
+
 	// 设置配置
-	if err := grpcClient.SetConfig(ctx, "order-service", "redis_url", `"redis://localhost:6379"`, "订单服务Redis连接"); err != nil {
+	if err := grpcClient.Set(ctx, "order-service", "redis_url", `"redis://localhost:6379"`, "订单服务Redis连接"); err != nil {
 		log.Printf("gRPC设置配置失败: %v", err)
 	} else {
 		fmt.Println("✓ gRPC设置配置成功")
 	}
-	
+
 	// 获取配置
-	if configItem, err := grpcClient.GetConfig(ctx, "order-service", "redis_url"); err != nil {
+	if configItem, err := grpcClient.Get(ctx, "order-service", "redis_url"); err != nil {
 		log.Printf("gRPC获取配置失败: %v", err)
 	} else {
-		fmt.Printf("✓ gRPC获取配置成功: %+v\n", configItem)
+		fmt.Printf("✓ gRPC获取配置成功: %s\n", configItem.String())
 	}
-	
-	// 监听配置变化示例（注释掉，因为会阻塞）
-	/*
-	fmt.Println("\n3. 监听配置变化示例")
-	go func() {
-		if err := grpcClient.WatchConfig(ctx, "order-service", "redis_url"); err != nil {
-			log.Printf("监听配置变化失败: %v", err)
-		}
-	}()
-	
-	// 等待一段时间以观察变化
-	time.Sleep(30 * time.Second)
-	*/
-	
+
+	// 本地缓存与自动监听示例
+	fmt.Println("\n3. 本地缓存与自动监听示例")
+	svc, err := grpcClient.Service(ctx, "order-service")
+	if err != nil {
+		log.Printf("加载服务配置失败: %v", err)
+		return
+	}
+	defer svc.Close()
+
+	svc.OnChange(func(e client.Event) {
+		fmt.Printf("配置变化: %s - %s/%s\n", e.Type, e.Service, e.Key)
+	})
+	fmt.Printf("✓ redis_url = %s\n", svc.String("redis_url", ""))
+	fmt.Printf("✓ timeout = %s\n", svc.Duration("timeout", 3*time.Second))
+
 	fmt.Println("\n示例完成！")
 }
\ No newline at end of file
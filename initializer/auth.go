@@ -0,0 +1,10 @@
+package initializer
+
+import (
+	"nidavellir/internal/auth"
+)
+
+func InitializeAuth(glb *Global) {
+	// 访问令牌校验, 供gRPC与HTTP共用
+	glb.Auth = auth.NewAuthorizer(glb.Cfg.Auth)
+}
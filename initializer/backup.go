@@ -0,0 +1,15 @@
+package initializer
+
+import (
+	"go.uber.org/zap"
+	"nidavellir/internal/backup"
+)
+
+func InitializeBackup(glb *Global) {
+	// 本地备份, 未配置目录时只提供 Enabled 判断
+	manager, err := backup.NewManager(glb.ConfigService, glb.Cfg.Backup, glb.Logger)
+	if err != nil {
+		glb.Logger.Fatal("Invalid backup config", zap.Error(err))
+	}
+	glb.Backups = manager
+}
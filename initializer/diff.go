@@ -0,0 +1,10 @@
+package initializer
+
+import (
+	"nidavellir/internal/diff"
+)
+
+func InitializeDiff(glb *Global) {
+	// 配置比较, 读取本地存储、备份与其他环境
+	glb.Diff = diff.NewResolver(glb.ConfigService, glb.Backups, glb.Cfg.Environments)
+}
@@ -1,6 +1,9 @@
 package initializer
 
 import (
+	"context"
+	"time"
+
 	"go.uber.org/zap"
 	"nidavellir/internal/config"
 	"nidavellir/internal/etcd"
@@ -13,15 +16,44 @@ func InitializeEtcd(glb *Global) {
 		glb.Logger.Fatal("Failed to create etcd client", zap.Error(err))
 	}
 
-	InitializeEnvs(glb.EnvCfg, etcdClient, glb.Logger)
 	service := InitializeService(etcdClient, glb.Logger)
+	InitializeArchiveKey(service, glb.Cfg.Archive, glb.Logger)
+	seeder := InitializeSeeder(glb.Cfg.Seed, service, glb.Logger)
+	InitializeEnvs(seeder, glb.Cfg.Seed, glb.Logger)
 
 	glb.Client = etcdClient
 	glb.ConfigService = service
+	glb.Seeder = seeder
+}
+
+func InitializeSeeder(cfg config.SeedConfig, service *etcd.ConfigService, logger *zap.Logger) *etcd.Seeder {
+	if _, err := etcd.ParseSeedMode(cfg.Mode); err != nil {
+		logger.Fatal("Invalid seed config", zap.Error(err))
+	}
+	return etcd.NewSeeder(service, config.LoadEnvs, cfg, logger)
 }
 
-func InitializeEnvs(envCfg *config.EnvConfig, client *etcd.Client, logger *zap.Logger) {
-	etcd.InitServiceEnvs(envCfg, client, logger)
+// InitializeEnvs 按配置的写入模式写入 envs.toml 中的默认配置
+func InitializeEnvs(seeder *etcd.Seeder, cfg config.SeedConfig, logger *zap.Logger) {
+	// etcd不可用时不阻塞启动, 由降级模式提供读取
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if _, err := seeder.Reload(ctx, "", cfg.DryRun); err != nil {
+		logger.Error("Failed to seed service configs", zap.Error(err))
+	}
+}
+
+// InitializeArchiveKey 加载归档中敏感配置的加密密钥
+func InitializeArchiveKey(service *etcd.ConfigService, cfg config.ArchiveConfig, logger *zap.Logger) {
+	if cfg.KeyFile == "" {
+		return
+	}
+	key, err := etcd.LoadKey(cfg.KeyFile)
+	if err != nil {
+		logger.Fatal("Invalid archive config", zap.Error(err))
+	}
+	service.SetArchiveKey(key)
 }
 
 func InitializeService(client *etcd.Client, logger *zap.Logger) *etcd.ConfigService {
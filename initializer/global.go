@@ -2,8 +2,13 @@ package initializer
 
 import (
 	"go.uber.org/zap"
+	"nidavellir/internal/auth"
+	"nidavellir/internal/backup"
+	"nidavellir/internal/clients"
 	"nidavellir/internal/config"
+	"nidavellir/internal/diff"
 	"nidavellir/internal/etcd"
+	"nidavellir/internal/watch"
 )
 
 type Global struct {
@@ -12,4 +17,10 @@ type Global struct {
 	Cfg           *config.Config
 	EnvCfg        *config.EnvConfig
 	ConfigService *etcd.ConfigService
+	Seeder        *etcd.Seeder
+	Backups       *backup.Manager
+	Diff          *diff.Resolver
+	Hub           *watch.Hub
+	Clients       *clients.Registry
+	Auth          *auth.Authorizer
 }
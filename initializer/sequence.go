@@ -4,16 +4,24 @@ const (
 	Logger = iota
 	Conf
 	Etcd
+	Watch
+	Auth
+	Backup
+	Diff
 	Grpc
 	Http
 )
 
 var (
-	Sequence = 5
+	Sequence = 9
 	initMap  = map[int]func(*Global){
 		Logger: InitializeLogger,
 		Conf:   InitializeConfig,
 		Etcd:   InitializeEtcd,
+		Watch:  InitializeWatch,
+		Auth:   InitializeAuth,
+		Backup: InitializeBackup,
+		Diff:   InitializeDiff,
 	}
 )
 
@@ -0,0 +1,17 @@
+package initializer
+
+import (
+	"time"
+
+	"nidavellir/internal/clients"
+	"nidavellir/internal/watch"
+)
+
+func InitializeWatch(glb *Global) {
+	// 创建配置变更分发中心, 由main负责启动
+	glb.Hub = watch.NewHub(glb.Cfg.Watch,
+		time.Duration(glb.Cfg.GRPC.WatchProgressInterval)*time.Second,
+		glb.ConfigService, glb.Logger)
+	// 已连接客户端的登记表, 供gRPC与HTTP共用
+	glb.Clients = clients.NewRegistry()
+}
@@ -0,0 +1,109 @@
+// Package archive 将配置归档编码为 JSON 或 TOML 文件, 以及从文件解码
+package archive
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"nidavellir/internal/etcd"
+
+	"github.com/pelletier/go-toml/v2"
+)
+
+// 归档文件格式
+const (
+	FormatJSON = "json"
+	FormatTOML = "toml"
+)
+
+// ErrUnknownFormat 不支持的归档文件格式
+var ErrUnknownFormat = errors.New("unknown archive format")
+
+// ParseFormat 解析归档文件格式, 为空时为 JSON
+func ParseFormat(format string) (string, error) {
+	switch strings.ToLower(format) {
+	case "", FormatJSON:
+		return FormatJSON, nil
+	case FormatTOML:
+		return FormatTOML, nil
+	}
+	return "", fmt.Errorf("%w: %q", ErrUnknownFormat, format)
+}
+
+// Encode 按格式写入归档。TOML 无法表示 null 与空表, 值中包含它们时返回错误, 此时应使用 JSON
+func Encode(w io.Writer, archive *etcd.Archive, format string) error {
+	switch format {
+	case FormatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(archive)
+	case FormatTOML:
+		for _, service := range archive.Services {
+			for _, cfg := range service.Configs {
+				if cfg.Value == nil {
+					continue
+				}
+				if err := checkTOML(cfg.Value); err != nil {
+					return fmt.Errorf("config %s/%s cannot be encoded as toml (%v), use json", service.Name, cfg.Key, err)
+				}
+			}
+		}
+		return toml.NewEncoder(w).Encode(archive)
+	}
+	return fmt.Errorf("%w: %q", ErrUnknownFormat, format)
+}
+
+// Decode 解析归档, format 为空时按内容判断: 以 { 开头为 JSON, 否则为 TOML
+func Decode(data []byte, format string) (*etcd.Archive, error) {
+	if format == "" {
+		format = FormatTOML
+		if bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+			format = FormatJSON
+		}
+	}
+
+	var (
+		archive etcd.Archive
+		err     error
+	)
+	switch format {
+	case FormatJSON:
+		err = json.Unmarshal(data, &archive)
+	case FormatTOML:
+		err = toml.Unmarshal(data, &archive)
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownFormat, format)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", etcd.ErrInvalidArchive, err)
+	}
+	return &archive, nil
+}
+
+// checkTOML 检查值能否无损地编码为 TOML
+func checkTOML(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		return errors.New("null value")
+	case map[string]interface{}:
+		if len(v) == 0 {
+			return errors.New("empty object")
+		}
+		for _, item := range v {
+			if err := checkTOML(item); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for _, item := range v {
+			if err := checkTOML(item); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+// Package auth 根据请求携带的访问令牌判断调用方的权限
+package auth
+
+import (
+	"crypto/subtle"
+	"strings"
+
+	"nidavellir/internal/config"
+)
+
+// Authorizer 访问令牌校验
+type Authorizer struct {
+	secretTokens [][]byte
+	members      []member
+	// requiredApprovals 发布草稿前需要的审批数
+	requiredApprovals int
+}
+
+// member 可以提交草稿的成员, approver 为true时同时可以审批
+type member struct {
+	name     string
+	token    []byte
+	approver bool
+}
+
+// NewAuthorizer 创建访问令牌校验, 忽略空令牌
+func NewAuthorizer(cfg config.AuthConfig) *Authorizer {
+	a := &Authorizer{requiredApprovals: max(cfg.RequiredApprovals, 0)}
+	for _, token := range cfg.SecretTokens {
+		if token != "" {
+			a.secretTokens = append(a.secretTokens, []byte(token))
+		}
+	}
+	for name, token := range cfg.Approvers {
+		if token != "" {
+			a.members = append(a.members, member{name: name, token: []byte(token), approver: true})
+		}
+	}
+	for name, token := range cfg.Authors {
+		if token != "" {
+			a.members = append(a.members, member{name: name, token: []byte(token)})
+		}
+	}
+	return a
+}
+
+// CanReadSecrets 判断令牌是否允许读取敏感配置
+func (a *Authorizer) CanReadSecrets(token string) bool {
+	if a == nil || token == "" {
+		return false
+	}
+	allowed := false
+	for _, secret := range a.secretTokens {
+		if subtle.ConstantTimeCompare([]byte(token), secret) == 1 {
+			allowed = true
+		}
+	}
+	return allowed
+}
+
+// Approver 返回令牌对应的审批人, 令牌不属于任何审批人时返回false
+func (a *Authorizer) Approver(token string) (string, bool) {
+	m, ok := a.member(token)
+	if !ok || !m.approver {
+		return "", false
+	}
+	return m.name, true
+}
+
+// Member 返回令牌对应的作者或审批人的名称, 令牌不属于任何成员时返回false
+func (a *Authorizer) Member(token string) (string, bool) {
+	m, ok := a.member(token)
+	return m.name, ok
+}
+
+// member 查找令牌对应的成员, 同一令牌同时配置为审批人与作者时视为审批人
+func (a *Authorizer) member(token string) (member, bool) {
+	if a == nil || token == "" {
+		return member{}, false
+	}
+	var (
+		result member
+		found  bool
+	)
+	for _, m := range a.members {
+		if subtle.ConstantTimeCompare([]byte(token), m.token) == 1 && (!found || m.approver) {
+			result, found = m, true
+		}
+	}
+	return result, found
+}
+
+// RequiredApprovals 发布草稿前需要的审批数
+func (a *Authorizer) RequiredApprovals() int {
+	if a == nil {
+		return 0
+	}
+	return a.requiredApprovals
+}
+
+// BearerToken 从 Authorization 头中解析令牌, 格式不符时返回空串
+func BearerToken(header string) string {
+	scheme, token, ok := strings.Cut(strings.TrimSpace(header), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return ""
+	}
+	return strings.TrimSpace(token)
+}
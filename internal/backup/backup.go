@@ -0,0 +1,387 @@
+// Package backup 按计划将所有服务的配置归档写入本地目录, 并按数量与时间清理, 支持从备份恢复
+package backup
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"nidavellir/internal/archive"
+	"nidavellir/internal/config"
+	"nidavellir/internal/etcd"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// encryptedMagic 加密备份的文件头, 其后为GCM的nonce与密文
+	encryptedMagic = "NIDBAK1\n"
+	// timeLayout 备份文件名中的时间格式(UTC)
+	timeLayout = "20060102T150405Z"
+)
+
+var (
+	// ErrDisabled 未配置备份目录
+	ErrDisabled = errors.New("backups are not configured")
+	// ErrNotFound 备份不存在
+	ErrNotFound = errors.New("backup not found")
+	// ErrNoKey 备份已加密但未配置密钥
+	ErrNoKey = errors.New("backup is encrypted but no key is configured")
+)
+
+// backupName 备份文件名: nidavellir-<UTC时间>-r<版本号>.json.gz, 加密时再加 .enc
+var backupName = regexp.MustCompile(`^nidavellir-(\d{8}T\d{6}Z)-r(\d+)\.json\.gz(\.enc)?$`)
+
+// Info 备份文件信息
+type Info struct {
+	Name string `json:"name"`
+	// Revision 备份所基于的版本号
+	Revision  int64 `json:"revision"`
+	CreatedAt int64 `json:"created_at"`
+	Size      int64 `json:"size"`
+	Encrypted bool  `json:"encrypted"`
+}
+
+// Manager 管理本地备份
+type Manager struct {
+	service  *etcd.ConfigService
+	cfg      config.BackupConfig
+	schedule *Schedule
+	// key AES-256密钥, 为空时不加密
+	key    []byte
+	logger *zap.Logger
+	// mu 串行化备份的写入与清理
+	mu sync.Mutex
+}
+
+// NewManager 创建备份管理器, 未配置目录时除 Enabled 外的方法都返回 ErrDisabled
+func NewManager(service *etcd.ConfigService, cfg config.BackupConfig, logger *zap.Logger) (*Manager, error) {
+	m := &Manager{service: service, cfg: cfg, logger: logger}
+	if cfg.Dir == "" {
+		return m, nil
+	}
+
+	schedule, err := ParseSchedule(cfg.Schedule)
+	if err != nil {
+		return nil, err
+	}
+	m.schedule = schedule
+	if cfg.KeyFile != "" {
+		if m.key, err = etcd.LoadKey(cfg.KeyFile); err != nil {
+			return nil, err
+		}
+	}
+	if err := os.MkdirAll(cfg.Dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create backup dir: %w", err)
+	}
+	return m, nil
+}
+
+// Enabled 是否配置了备份目录
+func (m *Manager) Enabled() bool {
+	return m.cfg.Dir != ""
+}
+
+// Run 按计划创建备份并清理过期的备份, 直到ctx结束
+func (m *Manager) Run(ctx context.Context) {
+	if !m.Enabled() {
+		return
+	}
+	m.prune()
+
+	for {
+		next := m.schedule.Next(time.Now())
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		info, err := m.create(ctx, true)
+		if err != nil {
+			m.logger.Error("Failed to create backup", zap.Error(err))
+			continue
+		}
+		if info != nil {
+			m.logger.Info("Backup created", zap.String("name", info.Name), zap.Int64("revision", info.Revision))
+		}
+		m.prune()
+	}
+}
+
+// Create 立即创建备份
+func (m *Manager) Create(ctx context.Context) (*Info, error) {
+	if !m.Enabled() {
+		return nil, ErrDisabled
+	}
+	info, err := m.create(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+	m.prune()
+	return info, nil
+}
+
+// List 列出所有备份, 最新的在前
+func (m *Manager) List() ([]Info, error) {
+	if !m.Enabled() {
+		return nil, ErrDisabled
+	}
+	entries, err := os.ReadDir(m.cfg.Dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read backup dir: %w", err)
+	}
+
+	backups := []Info{}
+	for _, entry := range entries {
+		info, ok := parseName(entry.Name())
+		if !ok || entry.IsDir() {
+			continue
+		}
+		if fi, err := entry.Info(); err == nil {
+			info.Size = fi.Size()
+		}
+		backups = append(backups, info)
+	}
+	sort.Slice(backups, func(i, j int) bool {
+		if backups[i].CreatedAt != backups[j].CreatedAt {
+			return backups[i].CreatedAt > backups[j].CreatedAt
+		}
+		return backups[i].Revision > backups[j].Revision
+	})
+	return backups, nil
+}
+
+// Load 读取备份中的归档
+func (m *Manager) Load(name string) (*etcd.Archive, error) {
+	if !m.Enabled() {
+		return nil, ErrDisabled
+	}
+	info, ok := parseName(name)
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrNotFound, name)
+	}
+	data, err := os.ReadFile(filepath.Join(m.cfg.Dir, name))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %q", ErrNotFound, name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read backup: %w", err)
+	}
+
+	if info.Encrypted {
+		if m.key == nil {
+			return nil, ErrNoKey
+		}
+		if data, err = decrypt(m.key, data); err != nil {
+			return nil, fmt.Errorf("failed to decrypt backup %s: %w", name, err)
+		}
+	}
+	reader, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress backup %s: %w", name, err)
+	}
+	defer reader.Close()
+	data, err = io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress backup %s: %w", name, err)
+	}
+	return archive.Decode(data, archive.FormatJSON)
+}
+
+// Restore 从备份恢复配置, 模式为空时为 replace, 即匹配的服务恢复到备份时的状态。
+// 写入前先创建一个当前状态的备份, 恢复有误时可以再恢复回来
+func (m *Manager) Restore(ctx context.Context, name string, opts etcd.ImportOptions) (*etcd.ImportResult, error) {
+	arc, err := m.Load(name)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Mode == "" {
+		opts.Mode = etcd.ImportModeReplace
+	}
+
+	if !opts.DryRun {
+		before, err := m.create(ctx, false)
+		if err != nil {
+			return nil, fmt.Errorf("failed to back up current configs before restore: %w", err)
+		}
+		m.logger.Info("Backup created before restore", zap.String("name", before.Name), zap.String("restore", name))
+	}
+	result, err := m.service.Import(ctx, arc, opts)
+	if err != nil {
+		return nil, err
+	}
+	if result.Applied {
+		m.logger.Info("Backup restored",
+			zap.String("name", name),
+			zap.String("mode", result.Mode),
+			zap.Strings("services", opts.Services),
+			zap.Int("changes", len(result.Changes)))
+	}
+	return result, nil
+}
+
+// create 导出所有配置与元数据并写入备份文件, skipUnchanged 为true且版本号与最新的备份相同时不写入
+func (m *Manager) create(ctx context.Context, skipUnchanged bool) (*Info, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// 备份文件整体加密时敏感配置以明文写入, 否则按归档密钥加密, 均未配置时不包含敏感配置的值
+	arc, err := m.service.Export(ctx, etcd.ExportOptions{IncludeSecrets: true, Plaintext: m.key != nil})
+	if err != nil {
+		return nil, err
+	}
+	if skipUnchanged {
+		if backups, err := m.List(); err == nil && len(backups) > 0 && backups[0].Revision == arc.Revision {
+			return nil, nil
+		}
+	}
+
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	if err := archive.Encode(writer, arc, archive.FormatJSON); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	data := buf.Bytes()
+
+	createdAt := time.Unix(arc.CreatedAt, 0).UTC()
+	name := fmt.Sprintf("nidavellir-%s-r%d.json.gz", createdAt.Format(timeLayout), arc.Revision)
+	if m.key != nil {
+		if data, err = encrypt(m.key, data); err != nil {
+			return nil, err
+		}
+		name += ".enc"
+	}
+	if err := writeFile(filepath.Join(m.cfg.Dir, name), data); err != nil {
+		return nil, err
+	}
+	return &Info{
+		Name:      name,
+		Revision:  arc.Revision,
+		CreatedAt: createdAt.Unix(),
+		Size:      int64(len(data)),
+		Encrypted: m.key != nil,
+	}, nil
+}
+
+// prune 删除超出数量与超过保留时间的备份, 最新的备份始终保留
+func (m *Manager) prune() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	backups, err := m.List()
+	if err != nil {
+		m.logger.Error("Failed to list backups", zap.Error(err))
+		return
+	}
+	cutoff := time.Now().Add(-time.Duration(m.cfg.MaxAge) * time.Hour).Unix()
+	for i, info := range backups {
+		if i == 0 {
+			continue
+		}
+		expired := m.cfg.MaxAge > 0 && info.CreatedAt < cutoff
+		if !expired && (m.cfg.Keep <= 0 || i < m.cfg.Keep) {
+			continue
+		}
+		if err := os.Remove(filepath.Join(m.cfg.Dir, info.Name)); err != nil {
+			m.logger.Error("Failed to remove backup", zap.String("name", info.Name), zap.Error(err))
+			continue
+		}
+		m.logger.Info("Backup removed", zap.String("name", info.Name), zap.Bool("expired", expired))
+	}
+}
+
+// parseName 从文件名解析备份信息
+func parseName(name string) (Info, bool) {
+	match := backupName.FindStringSubmatch(name)
+	if match == nil {
+		return Info{}, false
+	}
+	createdAt, err := time.Parse(timeLayout, match[1])
+	if err != nil {
+		return Info{}, false
+	}
+	revision, err := strconv.ParseInt(match[2], 10, 64)
+	if err != nil {
+		return Info{}, false
+	}
+	return Info{Name: name, Revision: revision, CreatedAt: createdAt.Unix(), Encrypted: match[3] != ""}, true
+}
+
+// writeFile 先写入临时文件再重命名, 避免留下不完整的备份
+func writeFile(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".backup-*")
+	if err != nil {
+		return fmt.Errorf("failed to create backup file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write backup file: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write backup file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write backup file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write backup file: %w", err)
+	}
+	return nil
+}
+
+// encrypt 以AES-256-GCM加密
+func encrypt(key, plaintext []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	out := append([]byte(encryptedMagic), nonce...)
+	return gcm.Seal(out, nonce, plaintext, nil), nil
+}
+
+// decrypt 解密 encrypt 的结果
+func decrypt(key, data []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.HasPrefix(data, []byte(encryptedMagic)) || len(data) < len(encryptedMagic)+gcm.NonceSize() {
+		return nil, errors.New("not an encrypted backup")
+	}
+	data = data[len(encryptedMagic):]
+	return gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], nil)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
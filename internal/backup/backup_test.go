@@ -0,0 +1,101 @@
+package backup
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestParseName(t *testing.T) {
+	createdAt := time.Date(2026, 10, 18, 12, 30, 5, 0, time.UTC).Unix()
+	tests := []struct {
+		name   string
+		want   Info
+		wantOK bool
+	}{
+		{
+			name:   "nidavellir-20261018T123005Z-r42.json.gz",
+			want:   Info{Name: "nidavellir-20261018T123005Z-r42.json.gz", Revision: 42, CreatedAt: createdAt},
+			wantOK: true,
+		},
+		{
+			name:   "nidavellir-20261018T123005Z-r7.json.gz.enc",
+			want:   Info{Name: "nidavellir-20261018T123005Z-r7.json.gz.enc", Revision: 7, CreatedAt: createdAt, Encrypted: true},
+			wantOK: true,
+		},
+		{name: "nidavellir-20261018T123005Z-r42.json"},
+		{name: "nidavellir-20261018T123005Z-r42.json.gz.tmp"},
+		{name: "nidavellir-20261018T123005Z.json.gz"},
+		{name: "nidavellir-20261018-123005-r42.json.gz"},
+		{name: "nidavellir-20261318T123005Z-r42.json.gz"},
+		{name: "nidavellir-20261018T123005Z-r99999999999999999999.json.gz"},
+		{name: "backup-nidavellir-20261018T123005Z-r42.json.gz"},
+		{name: ".backup-123456"},
+		{name: ""},
+	}
+
+	for _, tt := range tests {
+		got, ok := parseName(tt.name)
+		if ok != tt.wantOK || got != tt.want {
+			t.Errorf("parseName(%q) = %+v, %v, want %+v, %v", tt.name, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestEncrypt(t *testing.T) {
+	key := bytes.Repeat([]byte{7}, 32)
+	plaintexts := [][]byte{[]byte(`{"version":2,"services":[]}`), {}, bytes.Repeat([]byte{0}, 4096)}
+
+	for _, plaintext := range plaintexts {
+		data, err := encrypt(key, plaintext)
+		if err != nil {
+			t.Fatalf("encrypt() error = %v", err)
+		}
+		if !bytes.HasPrefix(data, []byte(encryptedMagic)) {
+			t.Errorf("encrypt() = %q..., want %q header", data[:min(len(data), 8)], encryptedMagic)
+		}
+		got, err := decrypt(key, data)
+		if err != nil {
+			t.Fatalf("decrypt() error = %v", err)
+		}
+		if !bytes.Equal(got, plaintext) {
+			t.Errorf("decrypt(encrypt(%q)) = %q", plaintext, got)
+		}
+	}
+
+	// 每次加密使用不同的nonce
+	first, _ := encrypt(key, []byte("same"))
+	second, _ := encrypt(key, []byte("same"))
+	if bytes.Equal(first, second) {
+		t.Error("encrypt() returned the same ciphertext twice")
+	}
+}
+
+func TestDecryptErrors(t *testing.T) {
+	key := bytes.Repeat([]byte{7}, 32)
+	data, err := encrypt(key, []byte("backup"))
+	if err != nil {
+		t.Fatalf("encrypt() error = %v", err)
+	}
+	tampered := bytes.Clone(data)
+	tampered[len(tampered)-1] ^= 1
+
+	tests := []struct {
+		name string
+		key  []byte
+		data []byte
+	}{
+		{name: "wrong key", key: bytes.Repeat([]byte{8}, 32), data: data},
+		{name: "tampered", key: key, data: tampered},
+		{name: "truncated", key: key, data: data[:len(encryptedMagic)+4]},
+		{name: "no header", key: key, data: data[len(encryptedMagic):]},
+		{name: "plain gzip", key: key, data: []byte{0x1f, 0x8b, 8, 0}},
+		{name: "invalid key size", key: key[:10], data: data},
+	}
+
+	for _, tt := range tests {
+		if got, err := decrypt(tt.key, tt.data); err == nil {
+			t.Errorf("%s: decrypt() = %q, want error", tt.name, got)
+		}
+	}
+}
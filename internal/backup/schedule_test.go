@@ -0,0 +1,70 @@
+package backup
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestScheduleNext(t *testing.T) {
+	// 2026-10-19 为周一
+	from := time.Date(2026, 10, 19, 10, 7, 30, 0, time.UTC)
+	at := func(month time.Month, day, hour, minute int) time.Time {
+		return time.Date(2026, month, day, hour, minute, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		spec string
+		want time.Time
+	}{
+		{spec: "@hourly", want: at(10, 19, 11, 0)},
+		{spec: "@daily", want: at(10, 20, 0, 0)},
+		{spec: "@weekly", want: at(10, 25, 0, 0)},
+		{spec: "@every 90m", want: at(10, 19, 11, 37)},
+		{spec: "* * * * *", want: at(10, 19, 10, 8)},
+		{spec: "*/15 * * * *", want: at(10, 19, 10, 15)},
+		{spec: "5,50 9-11 * * *", want: at(10, 19, 10, 50)},
+		{spec: "0 3 * * 1-5", want: at(10, 20, 3, 0)},
+		{spec: "0 0 * * 7", want: at(10, 25, 0, 0)},
+		{spec: "0 0 1 * *", want: at(11, 1, 0, 0)},
+		// 日与周都被限制时满足其一即可
+		{spec: "0 0 1 * 3", want: at(10, 21, 0, 0)},
+		{spec: "30 4 29 2 *", want: time.Date(2028, 2, 29, 4, 30, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		schedule, err := ParseSchedule(tt.spec)
+		if err != nil {
+			t.Fatalf("ParseSchedule(%q) error = %v", tt.spec, err)
+		}
+		if got := schedule.Next(from); !got.Equal(tt.want) {
+			t.Errorf("ParseSchedule(%q).Next(%s) = %s, want %s", tt.spec, from, got, tt.want)
+		}
+	}
+}
+
+func TestParseScheduleErrors(t *testing.T) {
+	specs := []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"a * * * *",
+		"0 0 30 2 *",
+		"@every 30s",
+		"@every soon",
+		"@yearly",
+	}
+
+	for _, spec := range specs {
+		if _, err := ParseSchedule(spec); !errors.Is(err, ErrInvalidSchedule) {
+			t.Errorf("ParseSchedule(%q) error = %v, want ErrInvalidSchedule", spec, err)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package clients
+
+import (
+	"context"
+	"sort"
+
+	"nidavellir/internal/etcd"
+	"nidavellir/internal/watch"
+)
+
+// Latest 配置的最新版本, 用于计算客户端的漂移
+type Latest struct {
+	// Keys 现存配置的最后修改版本
+	Keys []etcd.KeyRevision
+	// Services 服务最近一次变更(包括删除)的版本
+	Services map[string]int64
+}
+
+// LoadLatest 读取各配置键的最后修改版本, 并合并分发中心记录的服务变更版本, 同时返回当前版本号
+func LoadLatest(ctx context.Context, configService *etcd.ConfigService, hub *watch.Hub) (*Latest, int64, error) {
+	keys, revision, err := configService.KeyRevisions(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	return &Latest{Keys: keys, Services: hub.ServiceRevisions()}, revision, nil
+}
+
+// evaluate 计算客户端订阅范围内的最新版本与落后的服务
+func (l *Latest) evaluate(info *Info) {
+	var filter watch.Filter
+	for _, s := range info.Selectors {
+		selector, err := watch.ParseSelector(s)
+		if err != nil {
+			continue
+		}
+		filter.Selectors = append(filter.Selectors, selector)
+	}
+	// 尚未订阅任何配置的会话
+	if len(filter.Selectors) == 0 {
+		return
+	}
+
+	required := make(map[string]int64)
+	for _, kr := range l.Keys {
+		if filter.MatchKey(kr.ServiceName, kr.Key) && kr.Revision > required[kr.ServiceName] {
+			required[kr.ServiceName] = kr.Revision
+		}
+	}
+	// 删除事件不会留下键, 按服务粒度补充
+	for service, revision := range l.Services {
+		if matchService(filter, service) && revision > required[service] {
+			required[service] = revision
+		}
+	}
+
+	for service, revision := range required {
+		if revision > info.RequiredRevision {
+			info.RequiredRevision = revision
+		}
+		if revision > info.AppliedRevision {
+			info.BehindServices = append(info.BehindServices, service)
+		}
+	}
+	sort.Strings(info.BehindServices)
+}
+
+// matchService 判断服务是否在订阅范围内
+func matchService(filter watch.Filter, service string) bool {
+	for _, selector := range filter.Selectors {
+		if (watch.Selector{Service: selector.Service}).Match(service, "") {
+			return true
+		}
+	}
+	return false
+}
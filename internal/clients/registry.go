@@ -0,0 +1,211 @@
+// Package clients 已连接客户端的登记与配置漂移报告
+package clients
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// KindWatch gRPC WatchConfig 监听流
+	KindWatch = "watch"
+	// KindSSE HTTP SSE 监听流
+	KindSSE = "sse"
+	// KindSession gRPC Session 会话
+	KindSession = "session"
+)
+
+const (
+	// TransportTCP gRPC over TCP
+	TransportTCP = "tcp"
+	// TransportUDS gRPC over Unix Socket (Twig)
+	TransportUDS = "uds"
+	// TransportHTTP HTTP
+	TransportHTTP = "http"
+)
+
+// Identity 客户端身份
+type Identity struct {
+	Kind       string
+	Name       string
+	InstanceID string
+	Labels     map[string]string
+	Transport  string
+	// Peer 对端地址, UDS连接为空
+	Peer string
+	// PID UDS连接的对端进程号, 无法获取时为0
+	PID int32
+}
+
+// Info 客户端信息
+type Info struct {
+	ID          string            `json:"id"`
+	Kind        string            `json:"kind"`
+	Name        string            `json:"name,omitempty"`
+	InstanceID  string            `json:"instance_id,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	Transport   string            `json:"transport"`
+	Peer        string            `json:"peer,omitempty"`
+	PID         int32             `json:"pid,omitempty"`
+	Selectors   []string          `json:"selectors"`
+	ConnectedAt int64             `json:"connected_at"`
+	// DeliveredRevision 已推送给客户端的最新版本号
+	DeliveredRevision int64 `json:"delivered_revision"`
+	// AckedRevision 客户端确认已应用的版本号, 仅Session会话支持
+	AckedRevision int64 `json:"acked_revision,omitempty"`
+	// AppliedRevision 用于判断漂移的版本号, 会话为确认的版本号, 其余为推送的版本号
+	AppliedRevision int64 `json:"applied_revision"`
+	// RequiredRevision 订阅范围内配置的最新版本号
+	RequiredRevision int64 `json:"required_revision"`
+	// BehindServices 版本落后的服务
+	BehindServices []string `json:"behind_services,omitempty"`
+}
+
+// Drifted 判断客户端是否落后于订阅范围内的最新版本
+func (i Info) Drifted() bool {
+	return i.AppliedRevision < i.RequiredRevision
+}
+
+// Client 已登记的客户端
+type Client struct {
+	id          string
+	identity    Identity
+	connectedAt time.Time
+
+	mu        sync.Mutex
+	selectors []string
+	delivered int64
+	acked     int64
+}
+
+// SetSelectors 更新订阅条件
+func (c *Client) SetSelectors(selectors []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.selectors = append([]string(nil), selectors...)
+}
+
+// Delivered 记录已推送的版本号
+func (c *Client) Delivered(revision int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if revision > c.delivered {
+		c.delivered = revision
+	}
+}
+
+// Ack 记录客户端确认已应用的版本号
+func (c *Client) Ack(revision int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if revision > c.acked {
+		c.acked = revision
+	}
+}
+
+// ID 客户端标识
+func (c *Client) ID() string {
+	return c.id
+}
+
+// info 获取客户端信息
+func (c *Client) info() Info {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	info := Info{
+		ID:                c.id,
+		Kind:              c.identity.Kind,
+		Name:              c.identity.Name,
+		InstanceID:        c.identity.InstanceID,
+		Labels:            c.identity.Labels,
+		Transport:         c.identity.Transport,
+		Peer:              c.identity.Peer,
+		PID:               c.identity.PID,
+		Selectors:         append([]string{}, c.selectors...),
+		ConnectedAt:       c.connectedAt.Unix(),
+		DeliveredRevision: c.delivered,
+		AckedRevision:     c.acked,
+		AppliedRevision:   c.delivered,
+	}
+	if c.identity.Kind == KindSession {
+		info.AppliedRevision = c.acked
+	}
+	return info
+}
+
+// Registry 客户端登记表
+type Registry struct {
+	nextID atomic.Uint64
+
+	mu      sync.RWMutex
+	clients map[string]*Client
+}
+
+// NewRegistry 创建客户端登记表
+func NewRegistry() *Registry {
+	return &Registry{clients: make(map[string]*Client)}
+}
+
+// Register 登记客户端, 断开时需调用 Unregister
+func (r *Registry) Register(identity Identity, selectors []string) *Client {
+	c := &Client{
+		id:          fmt.Sprintf("%s-%d", identity.Kind, r.nextID.Add(1)),
+		identity:    identity,
+		connectedAt: time.Now(),
+		selectors:   append([]string(nil), selectors...),
+	}
+
+	r.mu.Lock()
+	r.clients[c.id] = c
+	r.mu.Unlock()
+	return c
+}
+
+// Unregister 注销客户端
+func (r *Registry) Unregister(c *Client) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.clients, c.id)
+}
+
+// List 获取所有客户端信息, 按连接时间排序。latest不为nil时计算每个客户端的漂移情况
+func (r *Registry) List(latest *Latest) []Info {
+	r.mu.RLock()
+	clients := make([]*Client, 0, len(r.clients))
+	for _, c := range r.clients {
+		clients = append(clients, c)
+	}
+	r.mu.RUnlock()
+
+	infos := make([]Info, 0, len(clients))
+	for _, c := range clients {
+		info := c.info()
+		if latest != nil {
+			latest.evaluate(&info)
+		}
+		infos = append(infos, info)
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		if infos[i].ConnectedAt != infos[j].ConnectedAt {
+			return infos[i].ConnectedAt < infos[j].ConnectedAt
+		}
+		return infos[i].ID < infos[j].ID
+	})
+	return infos
+}
+
+// Drift 获取版本落后的客户端
+func (r *Registry) Drift(latest *Latest) []Info {
+	infos := r.List(latest)
+	drifted := infos[:0]
+	for _, info := range infos {
+		if info.Drifted() {
+			drifted = append(drifted, info)
+		}
+	}
+	return drifted
+}
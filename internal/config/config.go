@@ -7,11 +7,21 @@ import (
 
 // Config 应用配置结构
 type Config struct {
-	HTTP HTTPConfig `mapstructure:"http"`
-	GRPC GRPCConfig `mapstructure:"grpc"`
-	Twig TwigConfig `mapstructure:"twig"`
-	Etcd EtcdConfig `mapstructure:"etcd"`
-	Log  LogConfig  `mapstructure:"log"`
+	HTTP   HTTPConfig   `mapstructure:"http"`
+	GRPC   GRPCConfig   `mapstructure:"grpc"`
+	Twig   TwigConfig   `mapstructure:"twig"`
+	Etcd   EtcdConfig   `mapstructure:"etcd"`
+	Log    LogConfig    `mapstructure:"log"`
+	Watch  WatchConfig  `mapstructure:"watch"`
+	Cache  CacheConfig  `mapstructure:"cache"`
+	Auth   AuthConfig   `mapstructure:"auth"`
+	Seed   SeedConfig   `mapstructure:"seed"`
+	Sync   SyncConfig   `mapstructure:"sync"`
+	Backup BackupConfig `mapstructure:"backup"`
+	// Archive 导出与导入的归档
+	Archive ArchiveConfig `mapstructure:"archive"`
+	// Environments 其他环境的配置中心, 按环境名索引, 用于跨环境比较配置
+	Environments map[string]EnvironmentConfig `mapstructure:"environments"`
 }
 
 // HTTPConfig HTTP服务器配置
@@ -26,6 +36,10 @@ type GRPCConfig struct {
 	Port   int    `mapstructure:"port"`
 	Host   string `mapstructure:"host"`
 	Enable bool   `mapstructure:"enable"`
+	// WatchProgressInterval 监听流的进度通知间隔(秒), 0表示关闭
+	WatchProgressInterval int `mapstructure:"watch_progress_interval"`
+	// SessionHeartbeatInterval Session会话的心跳间隔(秒)
+	SessionHeartbeatInterval int `mapstructure:"session_heartbeat_interval"`
 }
 
 type TwigConfig struct {
@@ -39,6 +53,88 @@ type EtcdConfig struct {
 	DialTimeout int      `mapstructure:"dial_timeout"`
 	Username    string   `mapstructure:"username"`
 	Password    string   `mapstructure:"password"`
+	// HealthCheckInterval 检查etcd是否可用的间隔(秒), 不可用时进入只读的降级模式
+	HealthCheckInterval int `mapstructure:"health_check_interval"`
+}
+
+// WatchConfig 监听分发配置
+type WatchConfig struct {
+	// BufferSize 每个订阅者的缓冲事件数
+	BufferSize int `mapstructure:"buffer_size"`
+	// SlowConsumer 缓冲区满时的处理策略: disconnect 断开, coalesce 合并同一键的事件
+	SlowConsumer string `mapstructure:"slow_consumer"`
+	// HistorySize 保留的最近事件数, 用于按版本恢复的订阅
+	HistorySize int `mapstructure:"history_size"`
+}
+
+// CacheConfig 配置缓存
+type CacheConfig struct {
+	// Enable 开启后读取优先使用由监听维护的内存缓存
+	Enable bool `mapstructure:"enable"`
+	// SnapshotPath 缓存的本地快照文件, etcd不可用时由快照提供读取, 为空时不持久化
+	SnapshotPath string `mapstructure:"snapshot_path"`
+	// SnapshotInterval 写入本地快照的间隔(秒)
+	SnapshotInterval int `mapstructure:"snapshot_interval"`
+}
+
+// AuthConfig 访问授权配置
+type AuthConfig struct {
+	// SecretTokens 允许读取敏感配置的访问令牌, 由客户端通过 Authorization: Bearer <token> 携带
+	SecretTokens []string `mapstructure:"secret_tokens"`
+	// Approvers 草稿的审批人, 按名称索引访问令牌, 审批人可以审批与发布草稿、修改服务的 protected 标记
+	Approvers map[string]string `mapstructure:"approvers"`
+	// Authors 可以创建、修改与放弃草稿但不能审批的成员, 按名称索引访问令牌, 审批人同样可以创建草稿
+	Authors map[string]string `mapstructure:"authors"`
+	// RequiredApprovals 发布草稿前需要的审批数, 作者与修改过草稿的人的审批不计入
+	RequiredApprovals int `mapstructure:"required_approvals"`
+}
+
+// SeedConfig 种子配置(envs.toml)的写入方式
+type SeedConfig struct {
+	// Mode 写入模式: skip-if-any 已存在任何配置时不写入, add-missing 只创建不存在的配置, force 覆盖值不同的配置
+	Mode string `mapstructure:"mode"`
+	// DryRun 启动时只输出将要进行的修改, 不写入
+	DryRun bool `mapstructure:"dry_run"`
+}
+
+// SyncConfig 从目录中的服务配置文件定期同步
+type SyncConfig struct {
+	// Dir 服务配置文件所在的目录, 为空时不同步
+	Dir string `mapstructure:"dir"`
+	// Interval 同步间隔(秒)
+	Interval int `mapstructure:"interval"`
+	// Prune 删除文件中没有声明的键
+	Prune bool `mapstructure:"prune"`
+	// DryRun 只记录不一致的配置, 不写入
+	DryRun bool `mapstructure:"dry_run"`
+}
+
+// BackupConfig 定期本地备份
+type BackupConfig struct {
+	// Dir 备份目录, 为空时不备份
+	Dir string `mapstructure:"dir"`
+	// Schedule 备份计划, cron表达式(分 时 日 月 周)或 @hourly、@daily、@weekly、@every 6h
+	Schedule string `mapstructure:"schedule"`
+	// Keep 保留的备份数, 0表示不按数量清理
+	Keep int `mapstructure:"keep"`
+	// MaxAge 备份保留的时间(小时), 0表示不按时间清理
+	MaxAge int `mapstructure:"max_age"`
+	// KeyFile 加密密钥文件, 内容为base64编码的32字节密钥, 为空时不加密
+	KeyFile string `mapstructure:"key_file"`
+}
+
+// ArchiveConfig 导出与导入的归档
+type ArchiveConfig struct {
+	// KeyFile 归档中敏感配置的加密密钥文件, 内容为base64编码的32字节密钥, 为空时导出的归档不包含敏感配置的值
+	KeyFile string `mapstructure:"key_file"`
+}
+
+// EnvironmentConfig 其他环境的配置中心
+type EnvironmentConfig struct {
+	// Server gRPC地址, host:port 或 unix:///path
+	Server string `mapstructure:"server"`
+	// Token 访问令牌, 需要比较敏感配置时应允许读取敏感配置
+	Token string `mapstructure:"token"`
 }
 
 // LogConfig 日志配置
@@ -81,8 +177,23 @@ func setDefaults() {
 	viper.SetDefault("http.host", "0.0.0.0")
 	viper.SetDefault("grpc.port", 9090)
 	viper.SetDefault("grpc.host", "0.0.0.0")
+	viper.SetDefault("grpc.watch_progress_interval", 30)
+	viper.SetDefault("grpc.session_heartbeat_interval", 10)
 	viper.SetDefault("etcd.endpoints", []string{"localhost:2379"})
 	viper.SetDefault("etcd.dial_timeout", 5)
+	viper.SetDefault("etcd.health_check_interval", 5)
+	viper.SetDefault("watch.buffer_size", 256)
+	viper.SetDefault("watch.slow_consumer", "disconnect")
+	viper.SetDefault("watch.history_size", 1024)
+	viper.SetDefault("cache.enable", true)
+	viper.SetDefault("cache.snapshot_path", "data/snapshot.json")
+	viper.SetDefault("cache.snapshot_interval", 5)
+	viper.SetDefault("auth.required_approvals", 1)
+	viper.SetDefault("seed.mode", "skip-if-any")
+	viper.SetDefault("sync.interval", 60)
+	viper.SetDefault("backup.schedule", "@hourly")
+	viper.SetDefault("backup.keep", 48)
+	viper.SetDefault("backup.max_age", 168)
 	viper.SetDefault("log.level", "info")
 	viper.SetDefault("log.format", "json")
 }
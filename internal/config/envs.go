@@ -16,13 +16,16 @@ type EnvConfig struct {
 	} `mapstructure:"service"`
 }
 
+// LoadEnvs 加载微服务默认的环境变量(envs.toml), 文件不存在时返回空配置。
+// 使用独立的viper实例, 重新加载时不影响应用配置
 func LoadEnvs() (*EnvConfig, error) {
-	viper.SetConfigName("envs")
-	viper.SetConfigType("toml")
-	viper.AddConfigPath(".")
-	viper.AddConfigPath("./configs")
+	v := viper.New()
+	v.SetConfigName("envs")
+	v.SetConfigType("toml")
+	v.AddConfigPath(".")
+	v.AddConfigPath("./configs")
 
-	if err := viper.ReadInConfig(); err != nil {
+	if err := v.ReadInConfig(); err != nil {
 		var configFileNotFoundError viper.ConfigFileNotFoundError
 		if !errors.As(err, &configFileNotFoundError) {
 			return &EnvConfig{}, err
@@ -30,7 +33,7 @@ func LoadEnvs() (*EnvConfig, error) {
 	}
 
 	var cfg *EnvConfig
-	if err := viper.Unmarshal(&cfg); err != nil {
+	if err := v.Unmarshal(&cfg); err != nil {
 		return &EnvConfig{}, err
 	}
 
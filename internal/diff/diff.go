@@ -0,0 +1,218 @@
+// Package diff 比较两组服务配置, 每一侧可以是服务的当前配置、历史版本、其他环境或快照中的配置
+package diff
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"nidavellir/internal/etcd"
+	"nidavellir/internal/render"
+)
+
+// 差异类型
+const (
+	OpAdded   = "added"
+	OpRemoved = "removed"
+	OpChanged = "changed"
+)
+
+// DefaultContext 统一格式中每处差异前后的上下文行数
+const DefaultContext = 3
+
+// Change 一个键的差异, Old 为左侧的值, New 为右侧的值
+type Change struct {
+	Key string      `json:"key"`
+	Op  string      `json:"op"`
+	Old interface{} `json:"old,omitempty"`
+	New interface{} `json:"new,omitempty"`
+	// Secret 任一侧为敏感配置
+	Secret bool `json:"secret,omitempty"`
+}
+
+// Result 比较结果
+type Result struct {
+	Left  string `json:"left"`
+	Right string `json:"right"`
+	// Changes 按键排序
+	Changes []Change `json:"changes"`
+	Added   int      `json:"added"`
+	Removed int      `json:"removed"`
+	Changed int      `json:"changed"`
+
+	// unchanged 两侧相同的配置, 作为统一格式中的上下文
+	unchanged []Change
+}
+
+// Compare 比较两组配置的值, 结果按键排序
+func Compare(leftLabel, rightLabel string, left, right map[string]*etcd.ConfigItem) *Result {
+	keys := make([]string, 0, len(left)+len(right))
+	for key := range left {
+		keys = append(keys, key)
+	}
+	for key := range right {
+		if _, ok := left[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	result := &Result{Left: leftLabel, Right: rightLabel, Changes: []Change{}}
+	for _, key := range keys {
+		l, inLeft := left[key]
+		r, inRight := right[key]
+		switch {
+		case !inRight:
+			result.Changes = append(result.Changes, Change{Key: key, Op: OpRemoved, Old: l.Value, Secret: l.Encrypt})
+			result.Removed++
+		case !inLeft:
+			result.Changes = append(result.Changes, Change{Key: key, Op: OpAdded, New: r.Value, Secret: r.Encrypt})
+			result.Added++
+		case !reflect.DeepEqual(l.Value, r.Value):
+			result.Changes = append(result.Changes, Change{Key: key, Op: OpChanged, Old: l.Value, New: r.Value, Secret: l.Encrypt || r.Encrypt})
+			result.Changed++
+		default:
+			result.unchanged = append(result.unchanged, Change{Key: key, Old: l.Value, New: r.Value, Secret: l.Encrypt || r.Encrypt})
+		}
+	}
+	return result
+}
+
+// Masked 返回敏感配置的值被隐藏的副本
+func (r *Result) Masked() *Result {
+	result := *r
+	result.Changes = maskChanges(r.Changes)
+	result.unchanged = maskChanges(r.unchanged)
+	return &result
+}
+
+// maskChanges 敏感配置的值显示为 etcd.MaskedValue
+func maskChanges(changes []Change) []Change {
+	masked := make([]Change, len(changes))
+	for i, change := range changes {
+		if change.Secret {
+			if change.Old != nil {
+				change.Old = etcd.MaskedValue
+			}
+			if change.New != nil {
+				change.New = etcd.MaskedValue
+			}
+		}
+		masked[i] = change
+	}
+	return masked
+}
+
+// line 统一格式中的一行
+type line struct {
+	prefix byte
+	text   string
+}
+
+// Unified 以统一格式(diff -u)输出, 每个配置为一行 key=value, 两侧相同时不输出
+func (r *Result) Unified(w io.Writer, context int) error {
+	if len(r.Changes) == 0 {
+		return nil
+	}
+	if context < 0 {
+		context = DefaultContext
+	}
+
+	all := make([]Change, 0, len(r.Changes)+len(r.unchanged))
+	all = append(all, r.Changes...)
+	all = append(all, r.unchanged...)
+	sort.Slice(all, func(i, j int) bool {
+		return all[i].Key < all[j].Key
+	})
+
+	var (
+		lines   []line
+		changed []int
+	)
+	for _, change := range all {
+		switch change.Op {
+		case OpRemoved:
+			changed = append(changed, len(lines))
+			lines = append(lines, line{'-', entryText(change.Key, change.Old)})
+		case OpAdded:
+			changed = append(changed, len(lines))
+			lines = append(lines, line{'+', entryText(change.Key, change.New)})
+		case OpChanged:
+			changed = append(changed, len(lines), len(lines)+1)
+			lines = append(lines, line{'-', entryText(change.Key, change.Old)}, line{'+', entryText(change.Key, change.New)})
+		default:
+			lines = append(lines, line{' ', entryText(change.Key, change.Old)})
+		}
+	}
+
+	if _, err := fmt.Fprintf(w, "--- %s\n+++ %s\n", r.Left, r.Right); err != nil {
+		return err
+	}
+	for i := 0; i < len(changed); {
+		// 相邻差异之间的相同行不超过两倍上下文时合并为一段
+		j := i
+		for j+1 < len(changed) && changed[j+1]-changed[j] <= 2*context+1 {
+			j++
+		}
+		start := max(changed[i]-context, 0)
+		end := min(changed[j]+context+1, len(lines))
+		if err := writeHunk(w, lines, start, end); err != nil {
+			return err
+		}
+		i = j + 1
+	}
+	return nil
+}
+
+// writeHunk 输出 lines[start:end] 为一段, 段头中的行号从1开始
+func writeHunk(w io.Writer, lines []line, start, end int) error {
+	var leftStart, rightStart, leftCount, rightCount int
+	for i := 0; i < end; i++ {
+		inLeft := lines[i].prefix != '+'
+		inRight := lines[i].prefix != '-'
+		if i < start {
+			if inLeft {
+				leftStart++
+			}
+			if inRight {
+				rightStart++
+			}
+			continue
+		}
+		if inLeft {
+			leftCount++
+		}
+		if inRight {
+			rightCount++
+		}
+	}
+	// 与 diff -u 相同, 某一侧为空时起始行号为其前一行
+	if leftCount > 0 {
+		leftStart++
+	}
+	if rightCount > 0 {
+		rightStart++
+	}
+
+	if _, err := fmt.Fprintf(w, "@@ -%d,%d +%d,%d @@\n", leftStart, leftCount, rightStart, rightCount); err != nil {
+		return err
+	}
+	for _, l := range lines[start:end] {
+		if _, err := fmt.Fprintf(w, "%c%s\n", l.prefix, l.text); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// entryText 配置的文本形式, 包含换行的值加引号以保持一行
+func entryText(key string, value interface{}) string {
+	text := render.Text(value)
+	if strings.ContainsAny(text, "\r\n") {
+		text = strconv.Quote(text)
+	}
+	return key + "=" + text
+}
@@ -0,0 +1,161 @@
+package diff
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"nidavellir/internal/etcd"
+)
+
+// items 以键值对创建配置, 键以 ! 结尾时为敏感配置
+func items(pairs ...interface{}) map[string]*etcd.ConfigItem {
+	result := make(map[string]*etcd.ConfigItem, len(pairs)/2)
+	for i := 0; i+1 < len(pairs); i += 2 {
+		key := pairs[i].(string)
+		secret := strings.HasSuffix(key, "!")
+		key = strings.TrimSuffix(key, "!")
+		result[key] = &etcd.ConfigItem{Key: key, Value: pairs[i+1], ServiceName: "Palace", Encrypt: secret}
+	}
+	return result
+}
+
+func TestCompare(t *testing.T) {
+	left := items("Host", "127.0.0.1", "Port", float64(8080), "Tags", []interface{}{"a"}, "Old", "x", "Password!", "p1")
+	right := items("Host", "127.0.0.1", "Port", float64(9090), "Tags", []interface{}{"a"}, "New", true, "Password!", "p2")
+
+	result := Compare("Palace", "staging/Palace", left, right)
+	want := []Change{
+		{Key: "New", Op: OpAdded, New: true},
+		{Key: "Old", Op: OpRemoved, Old: "x"},
+		{Key: "Password", Op: OpChanged, Old: "p1", New: "p2", Secret: true},
+		{Key: "Port", Op: OpChanged, Old: float64(8080), New: float64(9090)},
+	}
+	if !reflect.DeepEqual(result.Changes, want) {
+		t.Errorf("Changes = %+v, want %+v", result.Changes, want)
+	}
+	if result.Added != 1 || result.Removed != 1 || result.Changed != 2 {
+		t.Errorf("counts = +%d -%d ~%d, want +1 -1 ~2", result.Added, result.Removed, result.Changed)
+	}
+	if result.Left != "Palace" || result.Right != "staging/Palace" {
+		t.Errorf("labels = %q, %q", result.Left, result.Right)
+	}
+	if len(result.unchanged) != 2 {
+		t.Errorf("unchanged = %+v, want Host and Tags", result.unchanged)
+	}
+
+	empty := Compare("a", "b", nil, nil)
+	if empty.Changes == nil || len(empty.Changes) != 0 {
+		t.Errorf("Compare(nil, nil).Changes = %#v, want empty slice", empty.Changes)
+	}
+}
+
+func TestMasked(t *testing.T) {
+	left := items("Password!", "p1", "Token!", "t", "Host", "a")
+	right := items("Password!", "p2", "Host", "b", "Key!", "k")
+	result := Compare("L", "R", left, right)
+	masked := result.Masked()
+
+	want := []Change{
+		{Key: "Host", Op: OpChanged, Old: "a", New: "b"},
+		{Key: "Key", Op: OpAdded, New: etcd.MaskedValue, Secret: true},
+		{Key: "Password", Op: OpChanged, Old: etcd.MaskedValue, New: etcd.MaskedValue, Secret: true},
+		{Key: "Token", Op: OpRemoved, Old: etcd.MaskedValue, Secret: true},
+	}
+	if !reflect.DeepEqual(masked.Changes, want) {
+		t.Errorf("Masked().Changes = %+v, want %+v", masked.Changes, want)
+	}
+	// 原结果不受影响
+	if result.Changes[2].Old != "p1" {
+		t.Errorf("Masked() modified the original result: %+v", result.Changes[2])
+	}
+}
+
+func TestUnified(t *testing.T) {
+	tests := []struct {
+		name    string
+		left    map[string]*etcd.ConfigItem
+		right   map[string]*etcd.ConfigItem
+		context int
+		mask    bool
+		want    string
+	}{
+		{
+			name:  "no changes",
+			left:  items("a", "1"),
+			right: items("a", "1"),
+			want:  "",
+		},
+		{
+			name:    "one hunk",
+			left:    items("a", "1", "b", "2", "c", "3"),
+			right:   items("a", "1", "b", "20", "c", "3", "d", "4"),
+			context: 1,
+			want:    "--- L\n+++ R\n@@ -1,3 +1,4 @@\n a=1\n-b=2\n+b=20\n c=3\n+d=4\n",
+		},
+		{
+			name:    "separate hunks",
+			left:    items("k1", "a", "k2", "x", "k3", "x", "k4", "x", "k5", "x", "k6", "x", "k7", "z"),
+			right:   items("k1", "b", "k2", "x", "k3", "x", "k4", "x", "k5", "x", "k6", "x"),
+			context: 1,
+			want:    "--- L\n+++ R\n@@ -1,2 +1,2 @@\n-k1=a\n+k1=b\n k2=x\n@@ -6,2 +6,1 @@\n k6=x\n-k7=z\n",
+		},
+		{
+			name:    "merged within twice the context",
+			left:    items("k1", "a", "k2", "x", "k3", "x", "k4", "z"),
+			right:   items("k1", "b", "k2", "x", "k3", "x"),
+			context: 1,
+			want:    "--- L\n+++ R\n@@ -1,4 +1,3 @@\n-k1=a\n+k1=b\n k2=x\n k3=x\n-k4=z\n",
+		},
+		{
+			name:  "all removed",
+			left:  items("a", "1"),
+			right: items(),
+			want:  "--- L\n+++ R\n@@ -1,1 +0,0 @@\n-a=1\n",
+		},
+		{
+			name:  "all added",
+			left:  items(),
+			right: items("a", "1", "b", "2"),
+			want:  "--- L\n+++ R\n@@ -0,0 +1,2 @@\n+a=1\n+b=2\n",
+		},
+		{
+			name:    "default context",
+			left:    items("a", "1", "b", "1", "c", "1", "d", "1", "e", "1"),
+			right:   items("a", "1", "b", "1", "c", "1", "d", "1", "e", "2"),
+			context: -1,
+			want:    "--- L\n+++ R\n@@ -2,4 +2,4 @@\n b=1\n c=1\n d=1\n-e=1\n+e=2\n",
+		},
+		{
+			name:    "value types",
+			context: 3,
+			left:    items("n", float64(8080), "m", map[string]interface{}{"a": float64(1)}, "s", "line1\nline2"),
+			right:   items("n", nil, "m", map[string]interface{}{"a": float64(1)}, "s", "line1"),
+			want:    "--- L\n+++ R\n@@ -1,3 +1,3 @@\n m={\"a\":1}\n-n=8080\n+n=\n-s=\"line1\\nline2\"\n+s=line1\n",
+		},
+		{
+			name:    "masked",
+			context: 3,
+			left:    items("Password!", "p1", "Token!", "t"),
+			right:   items("Password!", "p2", "Token!", "t"),
+			mask:    true,
+			want:    "--- L\n+++ R\n@@ -1,2 +1,2 @@\n-Password=******\n+Password=******\n Token=******\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Compare("L", "R", tt.left, tt.right)
+			if tt.mask {
+				result = result.Masked()
+			}
+			var b strings.Builder
+			if err := result.Unified(&b, tt.context); err != nil {
+				t.Fatalf("Unified() error = %v", err)
+			}
+			if got := b.String(); got != tt.want {
+				t.Errorf("Unified() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,241 @@
+package diff
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	grpcConfig "nidavellir/api/proto"
+	"nidavellir/internal/backup"
+	"nidavellir/internal/config"
+	"nidavellir/internal/etcd"
+	"nidavellir/pkg/client"
+)
+
+const (
+	// backupPrefix 备份快照的命名空间前缀
+	backupPrefix = "backup:"
+	// filePrefix 归档文件的命名空间前缀, 文件由调用方读取后随请求上传
+	filePrefix = "file:"
+	// draftPrefix 草稿的命名空间前缀, 为服务当前的配置执行草稿中的操作后的结果
+	draftPrefix = "draft:"
+	// remotePageSize 从其他环境分页读取配置时每页的数量
+	remotePageSize = 500
+)
+
+var (
+	// ErrInvalidSide 比较的一侧格式错误或无法解析
+	ErrInvalidSide = errors.New("invalid diff side")
+	// ErrEnvironment 无法从其他环境读取配置
+	ErrEnvironment = errors.New("failed to read environment")
+)
+
+// Side 比较的一侧, 格式为 [<namespace>/]<service>[@<revision>]。
+// namespace 为空时为本地存储, 为 backup:<name> 时为本地备份, 为 file:<path> 时为导出的归档文件,
+// 为 draft:<id> 时为发布草稿后的配置, 否则为 environments 中配置的环境名。revision 只适用于本地存储
+type Side struct {
+	// Spec 原始描述, 作为结果中的标签
+	Spec        string
+	Service     string
+	Revision    int64
+	Environment string
+	Backup      string
+	File        string
+	Draft       string
+	// Archive 上传的归档, 用于 file: 命名空间
+	Archive *etcd.Archive
+}
+
+// ParseSide 解析比较的一侧, 服务名不能包含 /, 因此以最后一个 / 分隔命名空间与服务名
+func ParseSide(spec string) (Side, error) {
+	side := Side{Spec: spec}
+	rest := spec
+	if i := strings.LastIndex(rest, "@"); i >= 0 && !strings.Contains(rest[i:], "/") {
+		revision, err := strconv.ParseInt(rest[i+1:], 10, 64)
+		if err != nil || revision <= 0 {
+			return Side{}, fmt.Errorf("%w: %q: revision must be a positive integer", ErrInvalidSide, spec)
+		}
+		side.Revision = revision
+		rest = rest[:i]
+	}
+
+	namespace := ""
+	side.Service = rest
+	if i := strings.LastIndex(rest, "/"); i >= 0 {
+		namespace, side.Service = rest[:i], rest[i+1:]
+	}
+	if side.Service == "" {
+		return Side{}, fmt.Errorf("%w: %q: missing service name", ErrInvalidSide, spec)
+	}
+
+	switch {
+	case namespace == "":
+	case strings.HasPrefix(namespace, backupPrefix):
+		side.Backup = strings.TrimPrefix(namespace, backupPrefix)
+	case strings.HasPrefix(namespace, filePrefix):
+		side.File = strings.TrimPrefix(namespace, filePrefix)
+	case strings.HasPrefix(namespace, draftPrefix):
+		side.Draft = strings.TrimPrefix(namespace, draftPrefix)
+	default:
+		side.Environment = namespace
+	}
+	if namespace != "" && side.Backup == "" && side.File == "" && side.Draft == "" && side.Environment == "" {
+		return Side{}, fmt.Errorf("%w: %q: missing name after %q", ErrInvalidSide, spec, namespace)
+	}
+	if namespace != "" && side.Revision > 0 {
+		return Side{}, fmt.Errorf("%w: %q: revisions are only supported for local services", ErrInvalidSide, spec)
+	}
+	return side, nil
+}
+
+// Resolver 读取比较的两侧
+type Resolver struct {
+	service      *etcd.ConfigService
+	backups      *backup.Manager
+	environments map[string]config.EnvironmentConfig
+}
+
+// NewResolver 创建读取器
+func NewResolver(service *etcd.ConfigService, backups *backup.Manager, environments map[string]config.EnvironmentConfig) *Resolver {
+	return &Resolver{service: service, backups: backups, environments: environments}
+}
+
+// Diff 读取两侧的配置并比较, 结果包含敏感配置的值, 由调用方按权限隐藏
+func (r *Resolver) Diff(ctx context.Context, left, right Side) (*Result, error) {
+	leftConfigs, err := r.Load(ctx, left)
+	if err != nil {
+		return nil, err
+	}
+	rightConfigs, err := r.Load(ctx, right)
+	if err != nil {
+		return nil, err
+	}
+	return Compare(left.Spec, right.Spec, leftConfigs, rightConfigs), nil
+}
+
+// DraftDiff 比较草稿涉及的每个服务当前的配置与发布草稿后的配置, 按服务名排序
+func (r *Resolver) DraftDiff(ctx context.Context, draft *etcd.Draft) ([]*Result, error) {
+	services := draft.Services()
+	results := make([]*Result, 0, len(services))
+	for _, service := range services {
+		configs, err := r.service.GetServiceConfigs(ctx, service)
+		if err != nil {
+			return nil, err
+		}
+		right := draftPrefix + draft.ID + "/" + service
+		results = append(results, Compare(service, right, configs, draft.Apply(service, configs)))
+	}
+	return results, nil
+}
+
+// Load 读取一侧的配置, 服务不存在时返回空集合
+func (r *Resolver) Load(ctx context.Context, side Side) (map[string]*etcd.ConfigItem, error) {
+	switch {
+	case side.Archive != nil:
+		return r.archiveConfigs(side, side.Archive)
+	case side.File != "":
+		return nil, fmt.Errorf("%w: %q: archive file was not uploaded", ErrInvalidSide, side.Spec)
+	case side.Backup != "":
+		arc, err := r.backups.Load(side.Backup)
+		if err != nil {
+			return nil, err
+		}
+		return r.archiveConfigs(side, arc)
+	case side.Draft != "":
+		draft, err := r.service.GetDraft(ctx, side.Draft)
+		if err != nil {
+			return nil, err
+		}
+		configs, err := r.service.GetServiceConfigs(ctx, side.Service)
+		if err != nil {
+			return nil, err
+		}
+		return draft.Apply(side.Service, configs), nil
+	case side.Environment != "":
+		return r.loadEnvironment(ctx, side)
+	case side.Revision > 0:
+		return r.service.GetServiceConfigsAt(ctx, side.Service, side.Revision)
+	}
+	return r.service.GetServiceConfigs(ctx, side.Service)
+}
+
+// loadEnvironment 从其他环境的配置中心读取服务配置
+func (r *Resolver) loadEnvironment(ctx context.Context, side Side) (map[string]*etcd.ConfigItem, error) {
+	env, ok := r.environments[side.Environment]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q: unknown environment %q", ErrInvalidSide, side.Spec, side.Environment)
+	}
+	c, err := client.New(env.Server, client.WithToken(env.Token), client.WithClientName("nidavellir-diff"))
+	if err != nil {
+		return nil, fmt.Errorf("%w %s: %v", ErrEnvironment, side.Environment, err)
+	}
+	defer c.Close()
+
+	configs := make(map[string]*etcd.ConfigItem)
+	req := &grpcConfig.GetServiceConfigsRequest{ServiceName: side.Service, Limit: remotePageSize}
+	for {
+		resp, err := c.RPC().GetServiceConfigs(ctx, req)
+		if err != nil {
+			return nil, fmt.Errorf("%w %s: %v", ErrEnvironment, side.Environment, err)
+		}
+		for key, item := range resp.Configs {
+			var value interface{}
+			if err := json.Unmarshal([]byte(item.Value), &value); err != nil {
+				value = item.Value
+			}
+			configs[key] = &etcd.ConfigItem{
+				Key:         key,
+				Value:       value,
+				ServiceName: item.ServiceName,
+				Description: item.Description,
+				Encrypt:     item.Encrypt,
+				CreatedAt:   item.CreatedAt,
+				UpdatedAt:   item.UpdatedAt,
+			}
+		}
+		if resp.NextPageToken == "" {
+			return configs, nil
+		}
+		req.PageToken = resp.NextPageToken
+	}
+}
+
+// archiveConfigs 解密归档中加密的敏感配置, 返回一侧服务的配置
+func (r *Resolver) archiveConfigs(side Side, arc *etcd.Archive) (map[string]*etcd.ConfigItem, error) {
+	if err := r.service.OpenArchive(arc); err != nil {
+		return nil, fmt.Errorf("%w: %q: %v", ErrInvalidSide, side.Spec, err)
+	}
+	return archiveConfigs(arc, side.Service), nil
+}
+
+// archiveConfigs 归档中服务的配置, 导出时未包含值的敏感配置显示为 etcd.MaskedValue
+func archiveConfigs(arc *etcd.Archive, serviceName string) map[string]*etcd.ConfigItem {
+	configs := make(map[string]*etcd.ConfigItem)
+	for _, svc := range arc.Services {
+		if svc.Name != serviceName {
+			continue
+		}
+		for _, cfg := range svc.Configs {
+			item := &etcd.ConfigItem{
+				Key:         cfg.Key,
+				Value:       cfg.Value,
+				ServiceName: svc.Name,
+				Description: cfg.Description,
+				Encrypt:     cfg.Encrypt,
+				CreatedAt:   cfg.CreatedAt,
+				UpdatedAt:   cfg.UpdatedAt,
+			}
+			if cfg.Masked {
+				item.Value = etcd.MaskedValue
+			} else if data, err := json.Marshal(cfg.Value); err == nil {
+				// TOML 归档中的整数解码为 int64, 转换为与存储中相同的JSON解码形式
+				_ = json.Unmarshal(data, &item.Value)
+			}
+			configs[cfg.Key] = item
+		}
+	}
+	return configs
+}
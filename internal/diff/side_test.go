@@ -0,0 +1,48 @@
+package diff
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseSide(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    Side
+		wantErr bool
+	}{
+		{spec: "Palace", want: Side{Service: "Palace"}},
+		{spec: "Palace@12", want: Side{Service: "Palace", Revision: 12}},
+		{spec: "staging/Palace", want: Side{Service: "Palace", Environment: "staging"}},
+		{spec: "backup:nidavellir-20261018-120000.json/Palace", want: Side{Service: "Palace", Backup: "nidavellir-20261018-120000.json"}},
+		{spec: "file:/tmp/export.json/Palace", want: Side{Service: "Palace", File: "/tmp/export.json"}},
+		// @ 之后还有 / 时不是版本号
+		{spec: "file:./a@b/export.toml/Palace", want: Side{Service: "Palace", File: "./a@b/export.toml"}},
+		{spec: "draft:d-1/Palace", want: Side{Service: "Palace", Draft: "d-1"}},
+		{spec: "", wantErr: true},
+		{spec: "staging/", wantErr: true},
+		{spec: "Palace@", wantErr: true},
+		{spec: "Palace@0", wantErr: true},
+		{spec: "Palace@-1", wantErr: true},
+		{spec: "Palace@head", wantErr: true},
+		{spec: "backup:/Palace", wantErr: true},
+		{spec: "file:/Palace", wantErr: true},
+		{spec: "draft:/Palace", wantErr: true},
+		{spec: "staging/Palace@3", wantErr: true},
+		{spec: "backup:b.json/Palace@3", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseSide(tt.spec)
+		if tt.wantErr {
+			if !errors.Is(err, ErrInvalidSide) {
+				t.Errorf("ParseSide(%q) = %+v, %v, want ErrInvalidSide", tt.spec, got, err)
+			}
+			continue
+		}
+		tt.want.Spec = tt.spec
+		if err != nil || got != tt.want {
+			t.Errorf("ParseSide(%q) = %+v, %v, want %+v", tt.spec, got, err, tt.want)
+		}
+	}
+}
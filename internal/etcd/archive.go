@@ -0,0 +1,402 @@
+package etcd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"reflect"
+	"sort"
+	"strings"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+const (
+	// ArchiveFormat 归档文件的格式标识
+	ArchiveFormat = "nidavellir-archive"
+	// ArchiveVersion 当前的归档版本, 导入时只接受不高于此版本的归档。版本2起敏感配置的值加密导出
+	ArchiveVersion = 2
+)
+
+// 导入模式
+const (
+	// ImportModeMerge 写入归档中的配置, 存储中其他的配置保持不变
+	ImportModeMerge = "merge"
+	// ImportModeReplace 匹配的服务与归档完全一致, 删除归档中没有的配置
+	ImportModeReplace = "replace"
+)
+
+var (
+	// ErrInvalidArchive 归档格式或版本无效
+	ErrInvalidArchive = errors.New("invalid archive")
+	// ErrInvalidImport 导入参数无效
+	ErrInvalidImport = errors.New("invalid import request")
+)
+
+// Archive 某一版本下存储中所有服务的配置与元数据
+type Archive struct {
+	Format  string `json:"format" toml:"format"`
+	Version int    `json:"version" toml:"version"`
+	// Revision 导出所基于的版本号
+	Revision  int64 `json:"revision" toml:"revision"`
+	CreatedAt int64 `json:"created_at" toml:"created_at"`
+	// Filter 导出时的服务名通配符, 为空时归档包含所有服务。
+	// replace 模式只删除同时匹配导出与导入条件的服务中的配置
+	Filter []string `json:"filter,omitempty" toml:"filter,omitempty"`
+	// Services 按服务名排序
+	Services []ArchiveService `json:"services" toml:"services"`
+}
+
+// ArchiveService 归档中的一个服务
+type ArchiveService struct {
+	Name string `json:"name" toml:"name"`
+	// Meta 未设置元数据的服务为nil
+	Meta *ArchiveMeta `json:"metadata,omitempty" toml:"metadata,omitempty"`
+	// Configs 按配置键排序
+	Configs []ArchiveConfig `json:"configs" toml:"configs"`
+}
+
+// ArchiveMeta 归档中的服务元数据, Seeded 标记由各自的种子文件维护, Protected 标记只能由审批人修改, 都不导出
+type ArchiveMeta struct {
+	Description string            `json:"description" toml:"description"`
+	Owner       string            `json:"owner" toml:"owner"`
+	Contact     string            `json:"contact" toml:"contact"`
+	Labels      map[string]string `json:"labels,omitempty" toml:"labels,omitempty"`
+	CreatedAt   int64             `json:"created_at" toml:"created_at"`
+	UpdatedAt   int64             `json:"updated_at" toml:"updated_at"`
+}
+
+// ArchiveConfig 归档中的一个配置项
+type ArchiveConfig struct {
+	Key         string      `json:"key" toml:"key"`
+	Value       interface{} `json:"value" toml:"value,omitempty"`
+	Description string      `json:"description,omitempty" toml:"description,omitempty"`
+	Encrypt     bool        `json:"encrypt,omitempty" toml:"encrypt,omitempty"`
+	// Masked 导出方无权读取敏感配置或未配置归档密钥, 值未导出, 导入时跳过
+	Masked bool `json:"masked,omitempty" toml:"masked,omitempty"`
+	// Sealed 值为以归档密钥加密的密文, 导入时需要相同的密钥
+	Sealed    bool  `json:"sealed,omitempty" toml:"sealed,omitempty"`
+	CreatedAt int64 `json:"created_at" toml:"created_at"`
+	UpdatedAt int64 `json:"updated_at" toml:"updated_at"`
+}
+
+// ExportOptions 导出条件
+type ExportOptions struct {
+	// Services 服务名, 支持通配符(语法同 path.Match), 为空时导出所有服务
+	Services []string
+	// IncludeSecrets 调用方有权读取敏感配置, 敏感配置的值以归档密钥加密导出, 未配置归档密钥或无权读取时不导出
+	IncludeSecrets bool
+	// Plaintext 明文导出敏感配置的值, 仅用于整体加密的备份文件
+	Plaintext bool
+}
+
+// ImportOptions 导入条件
+type ImportOptions struct {
+	// Mode 导入模式, 为空时为 ImportModeMerge
+	Mode string
+	// Services 服务名, 支持通配符(语法同 path.Match), 为空时导入归档中的所有服务
+	Services []string
+	// DryRun 只返回将要执行的变更, 不写入
+	DryRun bool
+}
+
+// ImportResult 导入计划, 执行后为实际的导入结果
+type ImportResult struct {
+	SyncPlan
+	Mode string `json:"mode"`
+	// Metadata 元数据将被(已被)写入的服务
+	Metadata []string `json:"metadata,omitempty"`
+	// Skipped 值未导出而跳过的敏感配置(service/key)
+	Skipped []string `json:"skipped,omitempty"`
+}
+
+// Masked 返回敏感配置的值被隐藏的副本
+func (r *ImportResult) Masked() *ImportResult {
+	result := *r
+	result.SyncPlan = *r.SyncPlan.Masked()
+	return &result
+}
+
+// Export 在同一版本下读取所有配置与服务元数据, 生成归档。
+// 敏感配置保留 Encrypt 标记, 值以归档密钥加密并标记为 Sealed, 调用方无权读取或未配置归档密钥时值不导出并标记为 Masked
+func (s *ConfigService) Export(ctx context.Context, opts ExportOptions) (*Archive, error) {
+	if s.Degraded() {
+		return nil, ErrUnavailable
+	}
+	if err := validatePatterns(opts.Services); err != nil {
+		return nil, err
+	}
+
+	resp, err := s.client.GetWithOptions(ctx, ConfigPrefix, clientv3.WithPrefix())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get configs: %w", err)
+	}
+	revision := resp.Header.Revision
+	metaResp, err := s.client.GetWithOptions(ctx, ServiceMetaPrefix, clientv3.WithPrefix(), clientv3.WithRev(revision))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get service metadata: %w", err)
+	}
+
+	services := make(map[string]*ArchiveService)
+	service := func(name string) *ArchiveService {
+		if services[name] == nil {
+			services[name] = &ArchiveService{Name: name, Configs: []ArchiveConfig{}}
+		}
+		return services[name]
+	}
+	for _, kv := range resp.Kvs {
+		serviceName, _, ok := parseConfigKey(string(kv.Key))
+		if !ok || !matchServices(opts.Services, serviceName) {
+			continue
+		}
+		item := s.unmarshalItem(kv.Key, kv.Value)
+		if item == nil {
+			continue
+		}
+		cfg := ArchiveConfig{
+			Key:         item.Key,
+			Value:       item.Value,
+			Description: item.Description,
+			Encrypt:     item.Encrypt,
+			CreatedAt:   item.CreatedAt,
+			UpdatedAt:   item.UpdatedAt,
+		}
+		if item.Encrypt && !opts.Plaintext {
+			if opts.IncludeSecrets && s.archiveKey != nil {
+				if cfg.Value, err = sealValue(s.archiveKey, serviceName+"/"+item.Key, item.Value); err != nil {
+					return nil, err
+				}
+				cfg.Sealed = true
+			} else {
+				cfg.Value = nil
+				cfg.Masked = true
+			}
+		}
+		svc := service(serviceName)
+		svc.Configs = append(svc.Configs, cfg)
+	}
+	for _, kv := range metaResp.Kvs {
+		name := strings.TrimPrefix(string(kv.Key), ServiceMetaPrefix)
+		if !matchServices(opts.Services, name) {
+			continue
+		}
+		var meta ServiceMeta
+		if err := json.Unmarshal(kv.Value, &meta); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal service metadata: %w", err)
+		}
+		service(name).Meta = &ArchiveMeta{
+			Description: meta.Description,
+			Owner:       meta.Owner,
+			Contact:     meta.Contact,
+			Labels:      meta.Labels,
+			CreatedAt:   meta.CreatedAt,
+			UpdatedAt:   meta.UpdatedAt,
+		}
+	}
+
+	archive := &Archive{
+		Format:    ArchiveFormat,
+		Version:   ArchiveVersion,
+		Revision:  revision,
+		CreatedAt: getCurrentTimestamp(),
+		Filter:    opts.Services,
+		Services:  make([]ArchiveService, 0, len(services)),
+	}
+	for _, svc := range services {
+		sort.Slice(svc.Configs, func(i, j int) bool {
+			return svc.Configs[i].Key < svc.Configs[j].Key
+		})
+		archive.Services = append(archive.Services, *svc)
+	}
+	sort.Slice(archive.Services, func(i, j int) bool {
+		return archive.Services[i].Name < archive.Services[j].Name
+	})
+	return archive, nil
+}
+
+// Import 将归档中的服务写入存储, 配置保留归档中的创建与更新时间。
+// merge 模式只新建与更新, replace 模式同时删除匹配的服务中归档没有的配置,
+// 包括存储中有而归档中没有的服务(限于导出时匹配的服务)。配置的写入与同步相同, 冲突时返回 ErrSyncConflict。
+// 服务元数据与归档不同时覆盖, 不删除。加密的敏感配置以归档密钥解密, 值未导出的敏感配置跳过, 存储中的值保持不变
+func (s *ConfigService) Import(ctx context.Context, archive *Archive, opts ImportOptions) (*ImportResult, error) {
+	if archive.Format != ArchiveFormat {
+		return nil, fmt.Errorf("%w: unknown format %q", ErrInvalidArchive, archive.Format)
+	}
+	if archive.Version < 1 || archive.Version > ArchiveVersion {
+		return nil, fmt.Errorf("%w: unsupported version %d", ErrInvalidArchive, archive.Version)
+	}
+	mode := opts.Mode
+	if mode == "" {
+		mode = ImportModeMerge
+	}
+	if mode != ImportModeMerge && mode != ImportModeReplace {
+		return nil, fmt.Errorf("%w: unknown mode %q", ErrInvalidImport, mode)
+	}
+	if err := validatePatterns(opts.Services); err != nil {
+		return nil, err
+	}
+	if err := validatePatterns(archive.Filter); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidArchive, err)
+	}
+	if err := s.OpenArchive(archive); err != nil {
+		return nil, err
+	}
+	if !opts.DryRun && s.Degraded() {
+		return nil, ErrReadOnly
+	}
+
+	result := &ImportResult{Mode: mode}
+	var (
+		services []SyncService
+		metas    = make(map[string]*ArchiveMeta)
+		included = make(map[string]bool)
+	)
+	for _, svc := range archive.Services {
+		if !matchServices(opts.Services, svc.Name) {
+			continue
+		}
+		service := SyncService{Name: svc.Name, Configs: make(map[string]SyncConfig, len(svc.Configs))}
+		for _, cfg := range svc.Configs {
+			if _, ok := service.Configs[cfg.Key]; ok {
+				return nil, fmt.Errorf("%w: config %s/%s appears more than once", ErrInvalidArchive, svc.Name, cfg.Key)
+			}
+			if cfg.Masked {
+				// 跳过的键不参与比较, replace 模式下也不会被删除
+				service.Unmanaged = append(service.Unmanaged, escapePattern(cfg.Key))
+				result.Skipped = append(result.Skipped, svc.Name+"/"+cfg.Key)
+				continue
+			}
+			service.Configs[cfg.Key] = SyncConfig{
+				Value:       cfg.Value,
+				Description: cfg.Description,
+				Encrypt:     cfg.Encrypt,
+				CreatedAt:   cfg.CreatedAt,
+				UpdatedAt:   cfg.UpdatedAt,
+			}
+		}
+		if svc.Meta != nil {
+			metas[svc.Name] = svc.Meta
+		}
+		included[svc.Name] = true
+		services = append(services, service)
+	}
+
+	if mode == ImportModeReplace {
+		names, err := s.serviceNames(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range names {
+			if !included[name] && matchServices(opts.Services, name) && matchServices(archive.Filter, name) {
+				services = append(services, SyncService{Name: name})
+			}
+		}
+	}
+
+	var (
+		plan *SyncPlan
+		err  error
+	)
+	if opts.DryRun {
+		plan, err = s.PlanSync(ctx, services, mode == ImportModeReplace)
+	} else {
+		plan, err = s.ApplySync(ctx, services, mode == ImportModeReplace)
+	}
+	if err != nil {
+		if errors.Is(err, ErrInvalidSync) {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidArchive, err)
+		}
+		return nil, err
+	}
+	result.SyncPlan = *plan
+
+	names := make([]string, 0, len(metas))
+	for name := range metas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		current, modRevision, err := getServiceMeta(ctx, s.client, name)
+		if err != nil {
+			return nil, err
+		}
+		meta := &ServiceMeta{
+			Name:        name,
+			Description: metas[name].Description,
+			Owner:       metas[name].Owner,
+			Contact:     metas[name].Contact,
+			Labels:      metas[name].Labels,
+			CreatedAt:   metas[name].CreatedAt,
+			UpdatedAt:   metas[name].UpdatedAt,
+		}
+		if current != nil {
+			meta.Seeded = current.Seeded
+			meta.Protected = current.Protected
+			if sameMeta(current, meta) {
+				continue
+			}
+		}
+		result.Metadata = append(result.Metadata, name)
+		if opts.DryRun {
+			continue
+		}
+		if err := putServiceMeta(ctx, s.client, meta, modRevision); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// serviceNames 存储中有配置的服务名
+func (s *ConfigService) serviceNames(ctx context.Context) ([]string, error) {
+	resp, err := s.client.GetWithOptions(ctx, ConfigPrefix, clientv3.WithPrefix(), clientv3.WithKeysOnly())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get services: %w", err)
+	}
+	seen := make(map[string]bool)
+	var names []string
+	for _, kv := range resp.Kvs {
+		if serviceName, _, ok := parseConfigKey(string(kv.Key)); ok && !seen[serviceName] {
+			seen[serviceName] = true
+			names = append(names, serviceName)
+		}
+	}
+	return names, nil
+}
+
+// sameMeta 比较元数据的内容, 不比较时间戳
+func sameMeta(a, b *ServiceMeta) bool {
+	labels := func(m map[string]string) map[string]string {
+		if len(m) == 0 {
+			return nil
+		}
+		return m
+	}
+	return a.Description == b.Description && a.Owner == b.Owner && a.Contact == b.Contact &&
+		a.Seeded == b.Seeded && reflect.DeepEqual(labels(a.Labels), labels(b.Labels))
+}
+
+// validatePatterns 校验服务名通配符
+func validatePatterns(patterns []string) error {
+	for _, pattern := range patterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("%w: invalid service pattern %q", ErrInvalidServiceName, pattern)
+		}
+	}
+	return nil
+}
+
+// escapePattern 转义通配符, 使其只匹配键本身
+func escapePattern(key string) string {
+	var b strings.Builder
+	for _, r := range key {
+		switch r {
+		case '*', '?', '[', ']', '\\':
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
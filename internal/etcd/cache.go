@@ -0,0 +1,547 @@
+package etcd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.uber.org/zap"
+)
+
+// cacheSyncTimeout 读取前等待缓存追上本实例写入的最长时间, 超时后回退到etcd
+const cacheSyncTimeout = 500 * time.Millisecond
+
+// cacheRetryInterval 缓存失效后重新加载的间隔
+const cacheRetryInterval = time.Second
+
+// CacheOptions 配置缓存选项
+type CacheOptions struct {
+	// ProgressInterval 监听的进度通知间隔, 0表示关闭
+	ProgressInterval time.Duration
+	// SnapshotPath 本地快照文件, 为空时不持久化。etcd不可用时由快照提供读取
+	SnapshotPath string
+	// SnapshotInterval 检查缓存变化并写入快照的间隔
+	SnapshotInterval time.Duration
+}
+
+// ReadOption 读取选项
+type ReadOption func(*readOptions)
+
+type readOptions struct {
+	linearizable bool
+}
+
+// Linearizable 为true时绕过缓存, 直接从etcd线性一致地读取
+func Linearizable(linearizable bool) ReadOption {
+	return func(o *readOptions) {
+		o.linearizable = linearizable
+	}
+}
+
+// CacheStats 配置缓存统计
+type CacheStats struct {
+	// Enabled 缓存是否在运行
+	Enabled bool `json:"enabled"`
+	// Ready 缓存是否已加载且与etcd保持同步
+	Ready bool `json:"ready"`
+	// Revision 缓存已同步到的版本号
+	Revision int64  `json:"revision"`
+	Services int    `json:"services"`
+	Keys     int    `json:"keys"`
+	Hits     uint64 `json:"hits"`
+	// Misses 回退到etcd的读取次数, 包括线性一致读取
+	Misses uint64 `json:"misses"`
+	// Invalidations 因监听出错或版本被压缩导致的失效次数
+	Invalidations uint64 `json:"invalidations"`
+	// SavedRevision 本地快照的版本号
+	SavedRevision int64 `json:"saved_revision,omitempty"`
+	// SavedAt 本地快照的写入时间(Unix时间戳)
+	SavedAt int64 `json:"saved_at,omitempty"`
+}
+
+// cacheSnapshot 本地快照文件的内容
+type cacheSnapshot struct {
+	Revision int64                             `json:"revision"`
+	SavedAt  int64                             `json:"saved_at"`
+	Services map[string]map[string]*cacheEntry `json:"services"`
+}
+
+// cacheEntry 缓存中的配置项与其在etcd中的元信息
+type cacheEntry struct {
+	Item *ConfigItem `json:"item"`
+	// Size etcd中存储的值的字节数
+	Size int `json:"size"`
+	// ModRevision 最后修改的版本号
+	ModRevision int64 `json:"mod_revision"`
+}
+
+// configCache 配置树的内存缓存, 由前缀监听按版本顺序维护
+type configCache struct {
+	enabled       atomic.Bool
+	hits          atomic.Uint64
+	misses        atomic.Uint64
+	invalidations atomic.Uint64
+	// written 本实例写入产生的最新版本, 读取前缓存需追上该版本
+	written atomic.Int64
+
+	mu       sync.RWMutex
+	ready    bool
+	revision int64
+	services map[string]map[string]*cacheEntry
+	// notify 版本推进或失效时关闭并替换, 用于唤醒等待者
+	notify chan struct{}
+	// savedRevision 与 savedAt 记录最近一次写入或恢复的本地快照
+	savedRevision int64
+	savedAt       int64
+}
+
+func newConfigCache() *configCache {
+	return &configCache{notify: make(chan struct{})}
+}
+
+// load 使用全量数据重建缓存
+func (c *configCache) load(services map[string]map[string]*cacheEntry, revision int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.services = services
+	c.revision = revision
+	c.ready = true
+	c.broadcast()
+}
+
+// apply 应用监听响应, 忽略不晚于缓存版本的事件。同一事务中的多个事件版本号相同
+func (c *configCache) apply(resp *WatchResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.ready {
+		return
+	}
+
+	applied := c.revision
+	for _, event := range resp.Events {
+		if event.Revision <= applied {
+			continue
+		}
+		switch event.Type {
+		case EventTypePut:
+			entries := c.services[event.ServiceName]
+			if entries == nil {
+				entries = make(map[string]*cacheEntry)
+				c.services[event.ServiceName] = entries
+			}
+			entries[event.Key] = &cacheEntry{Item: event.Item, Size: event.valueSize, ModRevision: event.Revision}
+		case EventTypeDelete:
+			delete(c.services[event.ServiceName], event.Key)
+			if len(c.services[event.ServiceName]) == 0 {
+				delete(c.services, event.ServiceName)
+			}
+		}
+		c.revision = event.Revision
+	}
+	if resp.Revision > c.revision {
+		c.revision = resp.Revision
+	}
+	c.broadcast()
+}
+
+// invalidate 使缓存失效, 之后的读取回退到etcd直到重新加载。
+// 失效的数据保留下来, 降级期间仍可读取
+func (c *configCache) invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.ready {
+		return
+	}
+	c.ready = false
+	c.invalidations.Add(1)
+	c.broadcast()
+}
+
+// broadcast 唤醒等待者, 调用方需持有写锁
+func (c *configCache) broadcast() {
+	close(c.notify)
+	c.notify = make(chan struct{})
+}
+
+// wrote 记录本实例写入产生的版本, 保证随后的读取能看到该写入
+func (c *configCache) wrote(revision int64) {
+	for {
+		current := c.written.Load()
+		if revision <= current || c.written.CompareAndSwap(current, revision) {
+			return
+		}
+	}
+}
+
+// wait 等待缓存追上本实例的写入, 缓存不可用或超时时返回false
+func (c *configCache) wait(ctx context.Context) bool {
+	target := c.written.Load()
+	timer := time.NewTimer(cacheSyncTimeout)
+	defer timer.Stop()
+
+	for {
+		c.mu.RLock()
+		ready, revision, notify := c.ready, c.revision, c.notify
+		c.mu.RUnlock()
+
+		if !ready {
+			return false
+		}
+		if revision >= target {
+			return true
+		}
+		select {
+		case <-notify:
+		case <-timer.C:
+			return false
+		case <-ctx.Done():
+			return false
+		}
+	}
+}
+
+// usable 判断缓存能否提供读取, stale为true时允许读取失效的数据与恢复的快照, 调用方需持有锁
+func (c *configCache) usable(stale bool) bool {
+	return c.ready || (stale && c.services != nil)
+}
+
+// current 缓存已同步到的版本号
+func (c *configCache) current() int64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.revision
+}
+
+// get 从缓存获取配置, 缓存不可用时ok为false
+func (c *configCache) get(serviceName, key string, stale bool) (item *ConfigItem, ok bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if !c.usable(stale) {
+		return nil, false
+	}
+	if entry := c.services[serviceName][key]; entry != nil {
+		return cloneItem(entry.Item), true
+	}
+	return nil, true
+}
+
+// service 从缓存获取服务的所有配置, 缓存不可用时ok为false
+func (c *configCache) service(serviceName string, stale bool) (map[string]*ConfigItem, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if !c.usable(stale) {
+		return nil, false
+	}
+	result := make(map[string]*ConfigItem, len(c.services[serviceName]))
+	for key, entry := range c.services[serviceName] {
+		result[key] = cloneItem(entry.Item)
+	}
+	return result, true
+}
+
+// serviceNames 从缓存获取服务列表, 缓存不可用时ok为false
+func (c *configCache) serviceNames(stale bool) ([]string, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if !c.usable(stale) {
+		return nil, false
+	}
+	result := make([]string, 0, len(c.services))
+	for service := range c.services {
+		result = append(result, service)
+	}
+	sortServices(result)
+	return result, true
+}
+
+// items 从缓存获取按键排序的配置与缓存版本号, 参数含义同 Snapshot, 缓存不可用时ok为false
+func (c *configCache) items(serviceName, key string, stale bool) ([]*ConfigItem, int64, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if !c.usable(stale) {
+		return nil, 0, false
+	}
+
+	var result []*ConfigItem
+	for service, entries := range c.services {
+		if serviceName != "" && service != serviceName {
+			continue
+		}
+		for k, entry := range entries {
+			if key == "" || k == key {
+				result = append(result, cloneItem(entry.Item))
+			}
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].ServiceName != result[j].ServiceName {
+			return result[i].ServiceName < result[j].ServiceName
+		}
+		return result[i].Key < result[j].Key
+	})
+	return result, c.revision, true
+}
+
+// save 将已同步的缓存写入本地快照, 版本未变化时跳过
+func (c *configCache) save(path string) (bool, error) {
+	c.mu.RLock()
+	if !c.ready || c.revision == c.savedRevision {
+		c.mu.RUnlock()
+		return false, nil
+	}
+	snapshot := cacheSnapshot{Revision: c.revision, SavedAt: time.Now().Unix(), Services: c.services}
+	data, err := json.Marshal(snapshot)
+	c.mu.RUnlock()
+	if err != nil {
+		return false, fmt.Errorf("failed to marshal config snapshot: %w", err)
+	}
+
+	// 先写临时文件再重命名, 避免中途失败留下损坏的快照
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return false, fmt.Errorf("failed to create snapshot directory: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return false, fmt.Errorf("failed to write config snapshot: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return false, fmt.Errorf("failed to write config snapshot: %w", err)
+	}
+
+	c.mu.Lock()
+	c.savedRevision = snapshot.Revision
+	c.savedAt = snapshot.SavedAt
+	c.mu.Unlock()
+	return true, nil
+}
+
+// restore 从本地快照恢复失效状态的缓存, 仅在缓存为空时生效, 文件不存在或未恢复时返回nil
+func (c *configCache) restore(path string) (*cacheSnapshot, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config snapshot: %w", err)
+	}
+
+	var snapshot cacheSnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal config snapshot: %w", err)
+	}
+	if snapshot.Services == nil {
+		snapshot.Services = make(map[string]map[string]*cacheEntry)
+	}
+	for _, entries := range snapshot.Services {
+		for key, entry := range entries {
+			if entry == nil || entry.Item == nil {
+				delete(entries, key)
+			}
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.services != nil {
+		return nil, nil
+	}
+	c.services = snapshot.Services
+	c.revision = snapshot.Revision
+	c.savedRevision = snapshot.Revision
+	c.savedAt = snapshot.SavedAt
+	return &snapshot, nil
+}
+
+// serviceStats 从缓存计算服务的统计信息, 缓存不可用时ok为false
+func (c *configCache) serviceStats(names []string, stale bool) (map[string]ServiceStats, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if !c.usable(stale) {
+		return nil, false
+	}
+	result := make(map[string]ServiceStats, len(names))
+	for _, name := range names {
+		var stats ServiceStats
+		for _, entry := range c.services[name] {
+			stats.add(entry.Item, entry.Size, entry.ModRevision)
+		}
+		result[name] = stats
+	}
+	return result, true
+}
+
+// stats 获取缓存统计
+func (c *configCache) stats() CacheStats {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	stats := CacheStats{
+		Enabled:       c.enabled.Load(),
+		Ready:         c.ready,
+		Revision:      c.revision,
+		Services:      len(c.services),
+		Hits:          c.hits.Load(),
+		Misses:        c.misses.Load(),
+		Invalidations: c.invalidations.Load(),
+		SavedRevision: c.savedRevision,
+		SavedAt:       c.savedAt,
+	}
+	for _, entries := range c.services {
+		stats.Keys += len(entries)
+	}
+	return stats
+}
+
+// cloneItem 复制配置项, 避免调用方修改缓存中的数据
+func cloneItem(item *ConfigItem) *ConfigItem {
+	clone := *item
+	return &clone
+}
+
+// RunCache 加载配置缓存并通过前缀监听保持与etcd一致, 直到ctx结束。
+// 监听出错或版本被压缩时缓存失效, 读取回退到etcd, 随后重新加载。
+// 设置了快照文件时, 启动时先从快照恢复, 运行期间定期将缓存写入快照
+func (s *ConfigService) RunCache(ctx context.Context, opts CacheOptions) {
+	s.cache.enabled.Store(true)
+	defer s.cache.enabled.Store(false)
+
+	if opts.SnapshotPath != "" {
+		snapshot, err := s.cache.restore(opts.SnapshotPath)
+		if err != nil {
+			s.logger.Warn("Failed to restore config snapshot", zap.String("path", opts.SnapshotPath), zap.Error(err))
+		} else if snapshot != nil {
+			s.logger.Info("Config snapshot restored",
+				zap.String("path", opts.SnapshotPath),
+				zap.Int64("revision", snapshot.Revision),
+				zap.Int64("saved_at", snapshot.SavedAt))
+		}
+		go s.persistCache(ctx, opts.SnapshotPath, opts.SnapshotInterval)
+	}
+
+	for {
+		err := s.syncCache(ctx, opts.ProgressInterval)
+		s.cache.invalidate()
+		if ctx.Err() != nil {
+			return
+		}
+
+		s.logger.Warn("Config cache invalidated, reloading", zap.Error(err))
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(cacheRetryInterval):
+		}
+	}
+}
+
+// persistCache 定期将缓存写入本地快照, ctx结束时再写入一次
+func (s *ConfigService) persistCache(ctx context.Context, path string, interval time.Duration) {
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	save := func() {
+		saved, err := s.cache.save(path)
+		if err != nil {
+			s.logger.Warn("Failed to save config snapshot", zap.String("path", path), zap.Error(err))
+		} else if saved {
+			s.logger.Debug("Config snapshot saved", zap.String("path", path))
+		}
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			save()
+			return
+		case <-ticker.C:
+			save()
+		}
+	}
+}
+
+// CacheStats 获取配置缓存统计
+func (s *ConfigService) CacheStats() CacheStats {
+	return s.cache.stats()
+}
+
+// syncCache 全量加载缓存后从加载版本的下一个版本开始监听, 返回导致监听结束的错误
+func (s *ConfigService) syncCache(ctx context.Context, progressInterval time.Duration) error {
+	resp, err := s.client.GetWithOptions(ctx, ConfigPrefix, clientv3.WithPrefix())
+	if err != nil {
+		return fmt.Errorf("failed to load config cache: %w", err)
+	}
+
+	services := make(map[string]map[string]*cacheEntry)
+	for _, kv := range resp.Kvs {
+		serviceName, key, ok := parseConfigKey(string(kv.Key))
+		if !ok {
+			continue
+		}
+		item := s.unmarshalItem(kv.Key, kv.Value)
+		if item == nil {
+			continue
+		}
+		if services[serviceName] == nil {
+			services[serviceName] = make(map[string]*cacheEntry)
+		}
+		services[serviceName][key] = &cacheEntry{Item: item, Size: len(kv.Value), ModRevision: kv.ModRevision}
+	}
+	s.cache.load(services, resp.Header.Revision)
+	s.logger.Info("Config cache loaded",
+		zap.Int64("revision", resp.Header.Revision),
+		zap.Int("keys", len(resp.Kvs)))
+
+	watchCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	for watchResp := range s.WatchAll(watchCtx, WatchOptions{
+		StartRevision:    resp.Header.Revision + 1,
+		ProgressInterval: progressInterval,
+	}) {
+		if watchResp.Err != nil {
+			if watchResp.CompactRevision > 0 {
+				return fmt.Errorf("config cache watch compacted at revision %d: %w",
+					watchResp.CompactRevision, watchResp.Err)
+			}
+			return watchResp.Err
+		}
+		s.cache.apply(watchResp)
+	}
+
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return errors.New("config cache watch closed")
+}
+
+// readFrom 判断本次读取能否由缓存提供, stale为true表示处于降级模式, 只能读取缓存中失效的数据
+func (s *ConfigService) readFrom(ctx context.Context, opts []ReadOption) (fromCache, stale bool, err error) {
+	var o readOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if s.Degraded() {
+		if o.linearizable {
+			return false, false, fmt.Errorf("%w: linearizable read is not possible", ErrUnavailable)
+		}
+		return true, true, nil
+	}
+	return !o.linearizable && s.cache.wait(ctx), false, nil
+}
+
+// cacheHit 记录缓存命中情况
+func (s *ConfigService) cacheHit(hit bool) {
+	if hit {
+		s.cache.hits.Add(1)
+	} else {
+		s.cache.misses.Add(1)
+	}
+}
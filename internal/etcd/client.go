@@ -40,6 +40,11 @@ func (c *Client) Put(ctx context.Context, key, value string) error {
 	return err
 }
 
+// PutWithOptions 使用自定义选项存储键值对, 返回完整的响应
+func (c *Client) PutWithOptions(ctx context.Context, key, value string, opts ...clientv3.OpOption) (*clientv3.PutResponse, error) {
+	return c.client.Put(ctx, key, value, opts...)
+}
+
 // Get 获取键值
 func (c *Client) Get(ctx context.Context, key string) (string, error) {
 	resp, err := c.client.Get(ctx, key)
@@ -54,6 +59,15 @@ func (c *Client) Get(ctx context.Context, key string) (string, error) {
 	return string(resp.Kvs[0].Value), nil
 }
 
+// Revision 获取etcd当前的版本号
+func (c *Client) Revision(ctx context.Context) (int64, error) {
+	resp, err := c.client.Get(ctx, "\x00", clientv3.WithCountOnly())
+	if err != nil {
+		return 0, err
+	}
+	return resp.Header.Revision, nil
+}
+
 // GetWithPrefix 根据前缀获取所有键值对
 func (c *Client) GetWithPrefix(ctx context.Context, prefix string) (map[string]string, error) {
 	resp, err := c.client.Get(ctx, prefix, clientv3.WithPrefix())
@@ -69,12 +83,22 @@ func (c *Client) GetWithPrefix(ctx context.Context, prefix string) (map[string]s
 	return result, nil
 }
 
+// GetWithOptions 使用自定义选项获取键值, 返回完整的响应
+func (c *Client) GetWithOptions(ctx context.Context, key string, opts ...clientv3.OpOption) (*clientv3.GetResponse, error) {
+	return c.client.Get(ctx, key, opts...)
+}
+
 // Delete 删除键
 func (c *Client) Delete(ctx context.Context, key string) error {
 	_, err := c.client.Delete(ctx, key)
 	return err
 }
 
+// DeleteWithOptions 使用自定义选项删除键, 返回完整的响应
+func (c *Client) DeleteWithOptions(ctx context.Context, key string, opts ...clientv3.OpOption) (*clientv3.DeleteResponse, error) {
+	return c.client.Delete(ctx, key, opts...)
+}
+
 // DeleteWithPrefix 根据前缀删除所有键
 func (c *Client) DeleteWithPrefix(ctx context.Context, prefix string) error {
 	_, err := c.client.Delete(ctx, prefix, clientv3.WithPrefix())
@@ -89,4 +113,19 @@ func (c *Client) Watch(ctx context.Context, key string) clientv3.WatchChan {
 // WatchWithPrefix 监听前缀的变化
 func (c *Client) WatchWithPrefix(ctx context.Context, prefix string) clientv3.WatchChan {
 	return c.client.Watch(ctx, prefix, clientv3.WithPrefix())
-}
\ No newline at end of file
+}
+
+// RequestProgress 请求ctx对应监听流上的所有监听者发送进度通知
+func (c *Client) RequestProgress(ctx context.Context) error {
+	return c.client.RequestProgress(ctx)
+}
+
+// WatchWithOptions 使用自定义选项监听键的变化
+func (c *Client) WatchWithOptions(ctx context.Context, key string, opts ...clientv3.OpOption) clientv3.WatchChan {
+	return c.client.Watch(ctx, key, opts...)
+}
+
+// Txn 创建事务
+func (c *Client) Txn(ctx context.Context) clientv3.Txn {
+	return c.client.Txn(ctx)
+}
@@ -0,0 +1,81 @@
+package etcd
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// healthCheckTimeout 单次健康检查的超时时间
+const healthCheckTimeout = 2 * time.Second
+
+var (
+	// ErrReadOnly etcd不可用期间拒绝写入
+	ErrReadOnly = errors.New("etcd is unavailable, config center is in read-only mode")
+	// ErrUnavailable etcd不可用且无法从本地快照提供读取
+	ErrUnavailable = errors.New("etcd is unavailable")
+)
+
+// Status 配置中心的运行状态
+type Status struct {
+	// Degraded etcd不可用, 读取与监听由本地快照提供, 写入被拒绝
+	Degraded bool `json:"degraded"`
+	// Since 进入降级模式的时间(Unix时间戳)
+	Since int64 `json:"since,omitempty"`
+	// Revision 降级期间提供的配置版本号
+	Revision int64 `json:"revision,omitempty"`
+}
+
+// RunHealthCheck 定期检查etcd是否可用, 不可用时进入只读的降级模式, 恢复后自动退出, 直到ctx结束
+func (s *ConfigService) RunHealthCheck(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		s.checkHealth(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// checkHealth 检查etcd是否可用并切换降级模式
+func (s *ConfigService) checkHealth(ctx context.Context) {
+	checkCtx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	_, err := s.client.Revision(checkCtx)
+	if ctx.Err() != nil {
+		return
+	}
+
+	if err != nil {
+		if s.degradedSince.CompareAndSwap(0, time.Now().Unix()) {
+			s.logger.Warn("etcd is unavailable, entering read-only mode",
+				zap.Int64("revision", s.cache.current()),
+				zap.Error(err))
+		}
+		return
+	}
+	if s.degradedSince.Swap(0) != 0 {
+		s.logger.Info("etcd recovered, leaving read-only mode")
+	}
+}
+
+// Status 获取配置中心的运行状态
+func (s *ConfigService) Status() Status {
+	since := s.degradedSince.Load()
+	if since == 0 {
+		return Status{}
+	}
+	return Status{Degraded: true, Since: since, Revision: s.cache.current()}
+}
+
+// Degraded 判断是否处于降级模式
+func (s *ConfigService) Degraded() bool {
+	return s.degradedSince.Load() != 0
+}
@@ -0,0 +1,553 @@
+package etcd
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.uber.org/zap"
+)
+
+const (
+	// DraftPrefix 草稿键前缀, 与配置分开存储, 草稿的修改不会产生配置的监听事件
+	DraftPrefix = "/drafts/"
+	// maxDraftOperations 草稿的操作数上限, 发布时所有操作在一个事务中执行, 需低于etcd的单事务操作数上限
+	maxDraftOperations = 100
+)
+
+// 草稿状态
+const (
+	DraftStatusOpen      = "open"
+	DraftStatusPublished = "published"
+	DraftStatusAbandoned = "abandoned"
+)
+
+// 草稿中的操作类型
+const (
+	DraftOpSet    = "set"
+	DraftOpDelete = "delete"
+)
+
+var (
+	// ErrInvalidDraft 草稿内容无效
+	ErrInvalidDraft = errors.New("invalid draft")
+	// ErrDraftNotFound 草稿不存在
+	ErrDraftNotFound = errors.New("draft not found")
+	// ErrDraftClosed 草稿已发布或已放弃
+	ErrDraftClosed = errors.New("draft is closed")
+	// ErrDraftConflict 草稿创建后涉及的配置或草稿本身被修改
+	ErrDraftConflict = errors.New("draft conflict")
+	// ErrDraftNotApproved 草稿的审批数不足
+	ErrDraftNotApproved = errors.New("draft is not approved")
+	// ErrSelfApproval 草稿的作者与修改过草稿的人不能审批
+	ErrSelfApproval = errors.New("authors and editors cannot approve their own drafts")
+)
+
+// Draft 待审批的一组配置修改, 发布时在一个事务中写入
+type Draft struct {
+	ID          string `json:"id"`
+	Description string `json:"description"`
+	Author      string `json:"author"`
+	// Editors 修改过草稿的人, 不含作者
+	Editors []string `json:"editors,omitempty"`
+	Status  string   `json:"status"`
+	// Operations 按服务名与配置键排序
+	Operations []DraftOperation `json:"operations"`
+	Approvals  []DraftApproval  `json:"approvals,omitempty"`
+	// RequiredApprovals 发布前需要的审批数, 创建时确定
+	RequiredApprovals int   `json:"required_approvals"`
+	CreatedAt         int64 `json:"created_at"`
+	UpdatedAt         int64 `json:"updated_at"`
+	// ClosedBy、ClosedAt 发布或放弃草稿的人与时间
+	ClosedBy string `json:"closed_by,omitempty"`
+	ClosedAt int64  `json:"closed_at,omitempty"`
+	// Revision 发布时写入配置的版本号, 未发布时为0
+	Revision int64 `json:"revision,omitempty"`
+}
+
+// DraftOperation 草稿中的一个操作
+type DraftOperation struct {
+	Op          string      `json:"op"`
+	ServiceName string      `json:"service_name"`
+	Key         string      `json:"key"`
+	Value       interface{} `json:"value,omitempty"`
+	Description string      `json:"description,omitempty"`
+	Encrypt     bool        `json:"encrypt,omitempty"`
+	// BaseRevision 创建或更新草稿时配置的修改版本号, 配置不存在时为0, 发布时要求配置未被修改
+	BaseRevision int64 `json:"base_revision"`
+}
+
+// DraftApproval 一个审批
+type DraftApproval struct {
+	Approver   string `json:"approver"`
+	ApprovedAt int64  `json:"approved_at"`
+}
+
+// Approved 审批数满足要求
+func (d *Draft) Approved() bool {
+	return len(d.Approvals) >= d.RequiredApprovals
+}
+
+// Contributor 判断 name 是否为草稿的作者或修改过草稿的人
+func (d *Draft) Contributor(name string) bool {
+	if name == d.Author {
+		return true
+	}
+	for _, editor := range d.Editors {
+		if editor == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Services 草稿涉及的服务, 按服务名排序
+func (d *Draft) Services() []string {
+	var names []string
+	for _, op := range d.Operations {
+		if len(names) == 0 || names[len(names)-1] != op.ServiceName {
+			names = append(names, op.ServiceName)
+		}
+	}
+	return names
+}
+
+// Apply 返回在服务配置上执行草稿中的操作后的结果, 不修改 configs
+func (d *Draft) Apply(serviceName string, configs map[string]*ConfigItem) map[string]*ConfigItem {
+	result := make(map[string]*ConfigItem, len(configs))
+	for key, item := range configs {
+		result[key] = item
+	}
+	now := getCurrentTimestamp()
+	for _, op := range d.Operations {
+		if op.ServiceName != serviceName {
+			continue
+		}
+		if op.Op == DraftOpDelete {
+			delete(result, op.Key)
+			continue
+		}
+		item := op.item(now)
+		if existing, ok := result[op.Key]; ok {
+			item.CreatedAt = existing.CreatedAt
+		}
+		result[op.Key] = item
+	}
+	return result
+}
+
+// Masked 返回敏感配置的值被隐藏的副本
+func (d *Draft) Masked() *Draft {
+	result := *d
+	result.Operations = make([]DraftOperation, len(d.Operations))
+	for i, op := range d.Operations {
+		if op.Encrypt && op.Op == DraftOpSet {
+			op.Value = MaskedValue
+		}
+		result.Operations[i] = op
+	}
+	return &result
+}
+
+// item 操作写入的配置项
+func (op *DraftOperation) item(now int64) *ConfigItem {
+	return &ConfigItem{
+		Key:         op.Key,
+		Value:       op.Value,
+		ServiceName: op.ServiceName,
+		Description: op.Description,
+		Encrypt:     op.Encrypt,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+}
+
+// CreateDraft 创建草稿, 记录每个操作涉及的配置当前的修改版本号
+func (s *ConfigService) CreateDraft(ctx context.Context, draft *Draft) (*Draft, error) {
+	if s.Degraded() {
+		return nil, ErrReadOnly
+	}
+	operations, err := s.prepareOperations(ctx, draft.Operations)
+	if err != nil {
+		return nil, err
+	}
+
+	now := getCurrentTimestamp()
+	result := &Draft{
+		Description:       draft.Description,
+		Author:            draft.Author,
+		Status:            DraftStatusOpen,
+		Operations:        operations,
+		RequiredApprovals: draft.RequiredApprovals,
+		CreatedAt:         now,
+		UpdatedAt:         now,
+	}
+	// 编号冲突时重新生成
+	for range 3 {
+		if result.ID, err = newDraftID(); err != nil {
+			return nil, err
+		}
+		if err = s.putDraft(ctx, result, 0); !errors.Is(err, ErrDraftConflict) {
+			break
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Draft created",
+		zap.String("id", result.ID),
+		zap.String("author", result.Author),
+		zap.Int("operations", len(result.Operations)))
+	return result, nil
+}
+
+// GetDraft 获取草稿
+func (s *ConfigService) GetDraft(ctx context.Context, id string) (*Draft, error) {
+	draft, _, err := s.getDraft(ctx, id)
+	return draft, err
+}
+
+// ListDrafts 列出草稿, status 为空时列出所有状态, 最新的在前
+func (s *ConfigService) ListDrafts(ctx context.Context, status string) ([]*Draft, error) {
+	switch status {
+	case "", DraftStatusOpen, DraftStatusPublished, DraftStatusAbandoned:
+	default:
+		return nil, fmt.Errorf("%w: unknown status %q", ErrInvalidDraft, status)
+	}
+	if s.Degraded() {
+		return nil, ErrUnavailable
+	}
+
+	resp, err := s.client.GetWithOptions(ctx, DraftPrefix, clientv3.WithPrefix())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list drafts: %w", err)
+	}
+	drafts := make([]*Draft, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		draft, err := unmarshalDraft(kv.Value, kv.ModRevision)
+		if err != nil {
+			s.logger.Warn("Failed to unmarshal draft", zap.String("key", string(kv.Key)), zap.Error(err))
+			continue
+		}
+		if status == "" || draft.Status == status {
+			drafts = append(drafts, draft)
+		}
+	}
+	sort.SliceStable(drafts, func(i, j int) bool {
+		if drafts[i].CreatedAt != drafts[j].CreatedAt {
+			return drafts[i].CreatedAt > drafts[j].CreatedAt
+		}
+		return drafts[i].ID < drafts[j].ID
+	})
+	return drafts, nil
+}
+
+// UpdateDraft 修改草稿, description 为空时保持不变, operations 为空时保留原有的操作。
+// 所有操作的基准版本更新为当前版本, 已有的审批作废, editor 此后不能审批该草稿
+func (s *ConfigService) UpdateDraft(ctx context.Context, id, editor, description string, operations []DraftOperation) (*Draft, error) {
+	if s.Degraded() {
+		return nil, ErrReadOnly
+	}
+	draft, modRevision, err := s.openDraft(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if description != "" {
+		draft.Description = description
+	}
+	if len(operations) == 0 {
+		operations = draft.Operations
+	}
+	if draft.Operations, err = s.prepareOperations(ctx, operations); err != nil {
+		return nil, err
+	}
+	if !draft.Contributor(editor) {
+		draft.Editors = append(draft.Editors, editor)
+	}
+	draft.Approvals = nil
+	draft.UpdatedAt = getCurrentTimestamp()
+	if err := s.putDraft(ctx, draft, modRevision); err != nil {
+		return nil, err
+	}
+	return draft, nil
+}
+
+// ApproveDraft 审批草稿, 同一审批人重复审批只记录一次
+func (s *ConfigService) ApproveDraft(ctx context.Context, id, approver string) (*Draft, error) {
+	if s.Degraded() {
+		return nil, ErrReadOnly
+	}
+	draft, modRevision, err := s.openDraft(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if draft.Contributor(approver) {
+		return nil, ErrSelfApproval
+	}
+	for _, approval := range draft.Approvals {
+		if approval.Approver == approver {
+			return draft, nil
+		}
+	}
+
+	draft.Approvals = append(draft.Approvals, DraftApproval{Approver: approver, ApprovedAt: getCurrentTimestamp()})
+	if err := s.putDraft(ctx, draft, modRevision); err != nil {
+		return nil, err
+	}
+	s.logger.Info("Draft approved", zap.String("id", id), zap.String("approver", approver))
+	return draft, nil
+}
+
+// AbandonDraft 放弃草稿
+func (s *ConfigService) AbandonDraft(ctx context.Context, id, by string) (*Draft, error) {
+	if s.Degraded() {
+		return nil, ErrReadOnly
+	}
+	draft, modRevision, err := s.openDraft(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	draft.Status = DraftStatusAbandoned
+	draft.ClosedBy = by
+	draft.ClosedAt = getCurrentTimestamp()
+	if err := s.putDraft(ctx, draft, modRevision); err != nil {
+		return nil, err
+	}
+	s.logger.Info("Draft abandoned", zap.String("id", id), zap.String("by", by))
+	return draft, nil
+}
+
+// PublishDraft 发布草稿, 所有操作与草稿状态在一个事务中写入, 监听方在同一版本收到所有变更。
+// 审批数不足时返回 ErrDraftNotApproved; 涉及的配置在草稿创建或更新后被修改时返回 ErrDraftConflict,
+// 更新草稿后重新审批即可
+func (s *ConfigService) PublishDraft(ctx context.Context, id, publisher string) (*Draft, error) {
+	if s.Degraded() {
+		return nil, ErrReadOnly
+	}
+	draft, modRevision, err := s.openDraft(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !draft.Approved() {
+		return nil, fmt.Errorf("%w: %d of %d required approvals", ErrDraftNotApproved, len(draft.Approvals), draft.RequiredApprovals)
+	}
+
+	draftKey := DraftPrefix + id
+	cmps := []clientv3.Cmp{clientv3.Compare(clientv3.ModRevision(draftKey), "=", modRevision)}
+	ops := make([]clientv3.Op, 0, len(draft.Operations)+1)
+	var changed []string
+	now := getCurrentTimestamp()
+	for _, op := range draft.Operations {
+		configKey := s.buildConfigKey(op.ServiceName, op.Key)
+		resp, err := s.client.GetWithOptions(ctx, configKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get config: %w", err)
+		}
+		var current *ConfigItem
+		currentRevision := int64(0)
+		if len(resp.Kvs) > 0 {
+			current = s.unmarshalItem(resp.Kvs[0].Key, resp.Kvs[0].Value)
+			currentRevision = resp.Kvs[0].ModRevision
+		}
+		if currentRevision != op.BaseRevision {
+			changed = append(changed, op.ServiceName+"/"+op.Key)
+			continue
+		}
+
+		cmps = append(cmps, clientv3.Compare(clientv3.ModRevision(configKey), "=", op.BaseRevision))
+		if op.Op == DraftOpDelete {
+			ops = append(ops, clientv3.OpDelete(configKey))
+			continue
+		}
+		item := op.item(now)
+		if current != nil {
+			item.CreatedAt = current.CreatedAt
+		}
+		data, err := json.Marshal(item)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal config item: %w", err)
+		}
+		ops = append(ops, clientv3.OpPut(configKey, string(data)))
+	}
+	if len(changed) > 0 {
+		return nil, fmt.Errorf("%w: %s changed since the draft was last updated, update the draft and get it approved again",
+			ErrDraftConflict, strings.Join(changed, ", "))
+	}
+
+	published := *draft
+	published.Status = DraftStatusPublished
+	published.ClosedBy = publisher
+	published.ClosedAt = now
+	data, err := json.Marshal(&published)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal draft: %w", err)
+	}
+	ops = append(ops, clientv3.OpPut(draftKey, string(data)))
+
+	resp, err := s.client.Txn(ctx).If(cmps...).Then(ops...).Commit()
+	if err != nil {
+		return nil, fmt.Errorf("failed to publish draft: %w", err)
+	}
+	if !resp.Succeeded {
+		return nil, fmt.Errorf("%w: the draft or its configs changed while publishing, retry", ErrDraftConflict)
+	}
+	s.cache.wrote(resp.Header.Revision)
+	published.Revision = resp.Header.Revision
+
+	s.logger.Info("Draft published",
+		zap.String("id", id),
+		zap.String("publisher", publisher),
+		zap.Int("operations", len(published.Operations)),
+		zap.Int64("revision", published.Revision))
+	return &published, nil
+}
+
+// prepareOperations 校验操作, 将值转换为与存储中相同的JSON解码形式, 并记录配置当前的修改版本号
+func (s *ConfigService) prepareOperations(ctx context.Context, operations []DraftOperation) ([]DraftOperation, error) {
+	if len(operations) == 0 {
+		return nil, fmt.Errorf("%w: no operations", ErrInvalidDraft)
+	}
+	if len(operations) > maxDraftOperations {
+		return nil, fmt.Errorf("%w: %d operations, at most %d are allowed", ErrInvalidDraft, len(operations), maxDraftOperations)
+	}
+
+	result := make([]DraftOperation, 0, len(operations))
+	seen := make(map[string]bool, len(operations))
+	for _, op := range operations {
+		if err := validateServiceName(op.ServiceName); err != nil {
+			return nil, err
+		}
+		if op.Key == "" {
+			return nil, fmt.Errorf("%w: empty key in service %q", ErrInvalidDraft, op.ServiceName)
+		}
+		name := op.ServiceName + "/" + op.Key
+		if seen[name] {
+			return nil, fmt.Errorf("%w: %s appears more than once", ErrInvalidDraft, name)
+		}
+		seen[name] = true
+
+		switch op.Op {
+		case DraftOpSet:
+			if op.Value == nil {
+				return nil, fmt.Errorf("%w: %s: value is required", ErrInvalidDraft, name)
+			}
+			data, err := json.Marshal(op.Value)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %s: %v", ErrInvalidDraft, name, err)
+			}
+			if err := json.Unmarshal(data, &op.Value); err != nil {
+				return nil, fmt.Errorf("%w: %s: %v", ErrInvalidDraft, name, err)
+			}
+		case DraftOpDelete:
+			op.Value, op.Description, op.Encrypt = nil, "", false
+		default:
+			return nil, fmt.Errorf("%w: %s: unknown op %q (set, delete)", ErrInvalidDraft, name, op.Op)
+		}
+
+		resp, err := s.client.GetWithOptions(ctx, s.buildConfigKey(op.ServiceName, op.Key), clientv3.WithKeysOnly())
+		if err != nil {
+			return nil, fmt.Errorf("failed to get config: %w", err)
+		}
+		op.BaseRevision = 0
+		if len(resp.Kvs) > 0 {
+			op.BaseRevision = resp.Kvs[0].ModRevision
+		}
+		if op.Op == DraftOpDelete && op.BaseRevision == 0 {
+			return nil, fmt.Errorf("%w: %s does not exist", ErrInvalidDraft, name)
+		}
+		result = append(result, op)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].ServiceName != result[j].ServiceName {
+			return result[i].ServiceName < result[j].ServiceName
+		}
+		return result[i].Key < result[j].Key
+	})
+	return result, nil
+}
+
+// openDraft 读取未关闭的草稿及其修改版本号
+func (s *ConfigService) openDraft(ctx context.Context, id string) (*Draft, int64, error) {
+	draft, modRevision, err := s.getDraft(ctx, id)
+	if err != nil {
+		return nil, 0, err
+	}
+	if draft.Status != DraftStatusOpen {
+		return nil, 0, fmt.Errorf("%w: %s is %s", ErrDraftClosed, id, draft.Status)
+	}
+	return draft, modRevision, nil
+}
+
+// getDraft 读取草稿及其修改版本号
+func (s *ConfigService) getDraft(ctx context.Context, id string) (*Draft, int64, error) {
+	if id == "" || strings.Contains(id, "/") {
+		return nil, 0, fmt.Errorf("%w: %q", ErrDraftNotFound, id)
+	}
+	if s.Degraded() {
+		return nil, 0, ErrUnavailable
+	}
+	resp, err := s.client.GetWithOptions(ctx, DraftPrefix+id)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get draft: %w", err)
+	}
+	if len(resp.Kvs) == 0 {
+		return nil, 0, fmt.Errorf("%w: %q", ErrDraftNotFound, id)
+	}
+	kv := resp.Kvs[0]
+	draft, err := unmarshalDraft(kv.Value, kv.ModRevision)
+	if err != nil {
+		return nil, 0, err
+	}
+	return draft, kv.ModRevision, nil
+}
+
+// putDraft 写入草稿, 要求草稿的修改版本号仍为 modRevision(新建时为0), 否则返回 ErrDraftConflict
+func (s *ConfigService) putDraft(ctx context.Context, draft *Draft, modRevision int64) error {
+	data, err := json.Marshal(draft)
+	if err != nil {
+		return fmt.Errorf("failed to marshal draft: %w", err)
+	}
+	key := DraftPrefix + draft.ID
+	resp, err := s.client.Txn(ctx).
+		If(clientv3.Compare(clientv3.ModRevision(key), "=", modRevision)).
+		Then(clientv3.OpPut(key, string(data))).
+		Commit()
+	if err != nil {
+		return fmt.Errorf("failed to save draft: %w", err)
+	}
+	if !resp.Succeeded {
+		return fmt.Errorf("%w: draft %s was modified concurrently, retry", ErrDraftConflict, draft.ID)
+	}
+	return nil
+}
+
+// unmarshalDraft 解析草稿, 已发布的草稿不再修改, 其修改版本号即为发布的版本号
+func unmarshalDraft(data []byte, modRevision int64) (*Draft, error) {
+	var draft Draft
+	if err := json.Unmarshal(data, &draft); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal draft: %w", err)
+	}
+	if draft.Status == DraftStatusPublished {
+		draft.Revision = modRevision
+	}
+	return &draft, nil
+}
+
+// newDraftID 生成随机的草稿编号
+func newDraftID() (string, error) {
+	b := make([]byte, 4)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate draft id: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
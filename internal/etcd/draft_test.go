@@ -0,0 +1,132 @@
+package etcd
+
+import (
+	"reflect"
+	"testing"
+)
+
+func testDraft() *Draft {
+	return &Draft{
+		ID:     "d1",
+		Author: "bob",
+		Operations: []DraftOperation{
+			{Op: DraftOpSet, ServiceName: "Heimdallr", Key: "Port", Value: float64(9090)},
+			{Op: DraftOpSet, ServiceName: "Palace", Key: "DBPassword", Value: "new-secret", Encrypt: true, BaseRevision: 3},
+			{Op: DraftOpDelete, ServiceName: "Palace", Key: "Debug", BaseRevision: 4},
+			{Op: DraftOpSet, ServiceName: "Palace", Key: "Host", Value: "10.0.0.1", Description: "地址"},
+		},
+	}
+}
+
+func TestDraftApply(t *testing.T) {
+	configs := map[string]*ConfigItem{
+		"DBPassword": {Key: "DBPassword", Value: "old-secret", ServiceName: "Palace", Encrypt: true, CreatedAt: 100, UpdatedAt: 100},
+		"Debug":      {Key: "Debug", Value: true, ServiceName: "Palace", CreatedAt: 100, UpdatedAt: 100},
+		"Port":       {Key: "Port", Value: float64(22222), ServiceName: "Palace", CreatedAt: 100, UpdatedAt: 100},
+	}
+	original := make(map[string]ConfigItem, len(configs))
+	for key, item := range configs {
+		original[key] = *item
+	}
+
+	result := testDraft().Apply("Palace", configs)
+
+	if len(result) != 3 {
+		t.Fatalf("Apply() returned %d configs, want DBPassword, Host and Port", len(result))
+	}
+	if _, ok := result["Debug"]; ok {
+		t.Error("Apply() kept the deleted config Debug")
+	}
+	if got := result["DBPassword"]; got.Value != "new-secret" || !got.Encrypt || got.CreatedAt != 100 || got.UpdatedAt <= 100 {
+		t.Errorf("DBPassword = %+v, want the new value with the original CreatedAt", got)
+	}
+	if got := result["Host"]; got.Value != "10.0.0.1" || got.Description != "地址" || got.ServiceName != "Palace" || got.CreatedAt == 0 {
+		t.Errorf("Host = %+v, want a new config", got)
+	}
+	// 其他服务的操作不影响结果
+	if got := result["Port"]; got != configs["Port"] {
+		t.Errorf("Port = %+v, want the unchanged config", got)
+	}
+
+	if len(configs) != len(original) {
+		t.Fatalf("Apply() modified configs: %v", configs)
+	}
+	for key, item := range configs {
+		if *item != original[key] {
+			t.Errorf("Apply() modified configs[%s] = %+v, want %+v", key, *item, original[key])
+		}
+	}
+
+	if got := testDraft().Apply("Octopus", nil); len(got) != 0 {
+		t.Errorf("Apply(Octopus) = %v, want empty", got)
+	}
+}
+
+func TestDraftMasked(t *testing.T) {
+	draft := testDraft()
+	masked := draft.Masked()
+
+	for i, op := range masked.Operations {
+		want := draft.Operations[i]
+		if op.Op == DraftOpSet && op.Encrypt {
+			want.Value = MaskedValue
+		}
+		if !reflect.DeepEqual(op, want) {
+			t.Errorf("Masked().Operations[%d] = %+v, want %+v", i, op, want)
+		}
+	}
+	if draft.Operations[1].Value != "new-secret" {
+		t.Errorf("Masked() modified the original draft: %+v", draft.Operations[1])
+	}
+	if masked.ID != draft.ID || masked.Author != draft.Author {
+		t.Errorf("Masked() = %+v, want the same draft fields", masked)
+	}
+}
+
+func TestDraftContributor(t *testing.T) {
+	draft := &Draft{Author: "bob", Editors: []string{"carol", "dave"}}
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{name: "bob", want: true},
+		{name: "carol", want: true},
+		{name: "dave", want: true},
+		{name: "alice", want: false},
+		{name: "", want: false},
+	}
+	for _, tt := range tests {
+		if got := draft.Contributor(tt.name); got != tt.want {
+			t.Errorf("Contributor(%q) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestDraftServices(t *testing.T) {
+	if got, want := testDraft().Services(), []string{"Heimdallr", "Palace"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Services() = %v, want %v", got, want)
+	}
+	if got := (&Draft{}).Services(); len(got) != 0 {
+		t.Errorf("Services() = %v, want empty", got)
+	}
+}
+
+func TestDraftApproved(t *testing.T) {
+	tests := []struct {
+		required  int
+		approvals int
+		want      bool
+	}{
+		{required: 0, approvals: 0, want: true},
+		{required: 1, approvals: 0, want: false},
+		{required: 1, approvals: 1, want: true},
+		{required: 2, approvals: 1, want: false},
+		{required: 2, approvals: 3, want: true},
+	}
+	for _, tt := range tests {
+		draft := &Draft{RequiredApprovals: tt.required, Approvals: make([]DraftApproval, tt.approvals)}
+		if got := draft.Approved(); got != tt.want {
+			t.Errorf("Approved() with %d/%d approvals = %v, want %v", tt.approvals, tt.required, got, tt.want)
+		}
+	}
+}
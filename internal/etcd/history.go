@@ -0,0 +1,44 @@
+package etcd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// ErrInvalidRevision 版本号已被压缩或尚不存在
+var ErrInvalidRevision = errors.New("invalid revision")
+
+// GetServiceConfigsAt 获取服务在指定版本时的所有配置, 直接读取etcd
+func (s *ConfigService) GetServiceConfigsAt(ctx context.Context, serviceName string, revision int64) (map[string]*ConfigItem, error) {
+	if s.Degraded() {
+		return nil, ErrUnavailable
+	}
+	if err := validateServiceName(serviceName); err != nil {
+		return nil, err
+	}
+	if revision <= 0 {
+		return nil, fmt.Errorf("%w: %d", ErrInvalidRevision, revision)
+	}
+
+	resp, err := s.client.GetWithOptions(ctx, s.buildServicePrefix(serviceName), clientv3.WithPrefix(), clientv3.WithRev(revision))
+	switch {
+	case errors.Is(err, rpctypes.ErrCompacted):
+		return nil, fmt.Errorf("%w: revision %d has been compacted", ErrInvalidRevision, revision)
+	case errors.Is(err, rpctypes.ErrFutureRev):
+		return nil, fmt.Errorf("%w: revision %d is newer than the current revision", ErrInvalidRevision, revision)
+	case err != nil:
+		return nil, fmt.Errorf("failed to get service configs: %w", err)
+	}
+
+	result := make(map[string]*ConfigItem, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		if item := s.unmarshalItem(kv.Key, kv.Value); item != nil {
+			result[item.Key] = item
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,388 @@
+package etcd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+const (
+	// ServiceMetaPrefix 服务元数据键前缀, 与配置分开存储, 不影响配置的监听
+	ServiceMetaPrefix = "/services/"
+	// metaConflictRetries 写入时服务元数据被并发修改的重试次数
+	metaConflictRetries = 3
+)
+
+var (
+	// ErrInvalidServiceName 服务名无效
+	ErrInvalidServiceName = errors.New("invalid service name")
+	// ErrProtected 受保护的服务只能通过草稿修改配置
+	ErrProtected = errors.New("service is protected, submit a draft instead")
+	// ErrMetaConflict 写入期间服务元数据被修改
+	ErrMetaConflict = errors.New("service metadata changed while writing, retry")
+)
+
+// ServiceMeta 服务元数据
+type ServiceMeta struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// Owner 负责的团队
+	Owner   string            `json:"owner"`
+	Contact string            `json:"contact"`
+	Labels  map[string]string `json:"labels,omitempty"`
+	// Seeded 服务是否在 envs.toml 中声明, 由启动时的初始化维护
+	Seeded bool `json:"seeded"`
+	// Protected 受保护的服务只能通过草稿修改配置
+	Protected bool  `json:"protected,omitempty"`
+	CreatedAt int64 `json:"created_at"`
+	UpdatedAt int64 `json:"updated_at"`
+}
+
+// ServiceStats 根据服务配置计算的统计信息
+type ServiceStats struct {
+	Keys int `json:"keys"`
+	// ValueBytes etcd中存储的值的总字节数
+	ValueBytes int64 `json:"value_bytes"`
+	// LastModified 配置项最近一次更新的时间(Unix时间戳)
+	LastModified int64 `json:"last_modified"`
+	// LastRevision 配置项最近一次修改的版本号, 不包括删除
+	LastRevision int64 `json:"last_revision"`
+}
+
+// add 累加一个配置项
+func (s *ServiceStats) add(item *ConfigItem, size int, modRevision int64) {
+	s.Keys++
+	s.ValueBytes += int64(size)
+	if item != nil && item.UpdatedAt > s.LastModified {
+		s.LastModified = item.UpdatedAt
+	}
+	if modRevision > s.LastRevision {
+		s.LastRevision = modRevision
+	}
+}
+
+// ServiceInfo 服务的元数据与统计信息
+type ServiceInfo struct {
+	Name string `json:"name"`
+	// Meta 未设置元数据的服务为nil
+	Meta  *ServiceMeta `json:"metadata,omitempty"`
+	Stats ServiceStats `json:"stats"`
+}
+
+// SetServiceMeta 创建或更新服务元数据, 保留创建时间与 Seeded 标记。
+// Protected 标记按参数写入, 调用方应先校验是否有权修改; modRevision 为校验时
+// GetServiceMeta 返回的修改版本号, 元数据在此之后被修改时返回 ErrMetaConflict
+func (s *ConfigService) SetServiceMeta(ctx context.Context, meta *ServiceMeta, modRevision int64) (*ServiceMeta, error) {
+	if s.Degraded() {
+		return nil, ErrReadOnly
+	}
+	if err := validateServiceName(meta.Name); err != nil {
+		return nil, err
+	}
+
+	existing, _, err := getServiceMeta(ctx, s.client, meta.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	result := *meta
+	result.UpdatedAt = getCurrentTimestamp()
+	result.CreatedAt = result.UpdatedAt
+	result.Seeded = false
+	if existing != nil {
+		result.CreatedAt = existing.CreatedAt
+		result.Seeded = existing.Seeded
+	}
+
+	if err := putServiceMeta(ctx, s.client, &result, modRevision); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetServiceMeta 获取服务元数据及其修改版本号, 不存在时返回nil与0
+func (s *ConfigService) GetServiceMeta(ctx context.Context, serviceName string) (*ServiceMeta, int64, error) {
+	if s.Degraded() {
+		return nil, 0, ErrUnavailable
+	}
+	if err := validateServiceName(serviceName); err != nil {
+		return nil, 0, err
+	}
+	return getServiceMeta(ctx, s.client, serviceName)
+}
+
+// DeleteServiceMeta 删除服务元数据, 服务的配置不受影响。
+// 元数据在 modRevision 之后被修改时返回 ErrMetaConflict
+func (s *ConfigService) DeleteServiceMeta(ctx context.Context, serviceName string, modRevision int64) error {
+	if s.Degraded() {
+		return ErrReadOnly
+	}
+	if err := validateServiceName(serviceName); err != nil {
+		return err
+	}
+	key := ServiceMetaPrefix + serviceName
+	resp, err := s.client.Txn(ctx).
+		If(clientv3.Compare(clientv3.ModRevision(key), "=", modRevision)).
+		Then(clientv3.OpDelete(key)).
+		Commit()
+	if err != nil {
+		return fmt.Errorf("failed to delete service metadata: %w", err)
+	}
+	if !resp.Succeeded {
+		return fmt.Errorf("%w: %s", ErrMetaConflict, serviceName)
+	}
+	return nil
+}
+
+// GetServiceInfo 获取服务的元数据与统计信息, 既没有元数据也没有配置时返回nil
+func (s *ConfigService) GetServiceInfo(ctx context.Context, serviceName string, opts ...ReadOption) (*ServiceInfo, error) {
+	if err := validateServiceName(serviceName); err != nil {
+		return nil, err
+	}
+	infos, err := s.ServiceInfos(ctx, []string{serviceName}, opts...)
+	if err != nil {
+		return nil, err
+	}
+	info := infos[0]
+	if info.Meta == nil && info.Stats.Keys == 0 {
+		return nil, nil
+	}
+	return &info, nil
+}
+
+// ServiceInfos 批量获取服务的元数据与统计信息, 顺序与names一致。
+// 统计信息缓存可用时从缓存计算, 否则读取服务的全部配置
+func (s *ConfigService) ServiceInfos(ctx context.Context, names []string, opts ...ReadOption) ([]ServiceInfo, error) {
+	metas, err := s.serviceMetas(ctx, names)
+	if err != nil {
+		return nil, err
+	}
+	stats, err := s.serviceStats(ctx, names, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	infos := make([]ServiceInfo, 0, len(names))
+	for _, name := range names {
+		infos = append(infos, ServiceInfo{Name: name, Meta: metas[name], Stats: stats[name]})
+	}
+	return infos, nil
+}
+
+// serviceMetas 读取服务元数据, 服务较多时一次读取整个前缀
+func (s *ConfigService) serviceMetas(ctx context.Context, names []string) (map[string]*ServiceMeta, error) {
+	if s.Degraded() {
+		return nil, ErrUnavailable
+	}
+
+	result := make(map[string]*ServiceMeta, len(names))
+	if len(names) == 1 {
+		meta, _, err := getServiceMeta(ctx, s.client, names[0])
+		if err != nil {
+			return nil, err
+		}
+		result[names[0]] = meta
+		return result, nil
+	}
+
+	metas, err := s.loadServiceMetas(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range names {
+		if meta, ok := metas[name]; ok {
+			result[name] = meta
+		}
+	}
+	return result, nil
+}
+
+// loadServiceMetas 读取所有服务的元数据
+func (s *ConfigService) loadServiceMetas(ctx context.Context) (map[string]*ServiceMeta, error) {
+	if s.Degraded() {
+		return nil, ErrUnavailable
+	}
+
+	data, err := s.client.GetWithPrefix(ctx, ServiceMetaPrefix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get service metadata: %w", err)
+	}
+	result := make(map[string]*ServiceMeta, len(data))
+	for key, value := range data {
+		var meta ServiceMeta
+		if err := json.Unmarshal([]byte(value), &meta); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal service metadata: %w", err)
+		}
+		result[strings.TrimPrefix(key, ServiceMetaPrefix)] = &meta
+	}
+	return result, nil
+}
+
+// serviceStats 计算服务的统计信息
+func (s *ConfigService) serviceStats(ctx context.Context, names []string, opts []ReadOption) (map[string]ServiceStats, error) {
+	fromCache, stale, err := s.readFrom(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	if fromCache {
+		if stats, ok := s.cache.serviceStats(names, stale); ok {
+			s.cacheHit(true)
+			return stats, nil
+		}
+		if stale {
+			return nil, ErrUnavailable
+		}
+	}
+	s.cacheHit(false)
+
+	result := make(map[string]ServiceStats, len(names))
+	for _, name := range names {
+		resp, err := s.client.GetWithOptions(ctx, s.buildServicePrefix(name), clientv3.WithPrefix())
+		if err != nil {
+			return nil, fmt.Errorf("failed to get service stats: %w", err)
+		}
+		var stats ServiceStats
+		for _, kv := range resp.Kvs {
+			if _, _, ok := parseConfigKey(string(kv.Key)); !ok {
+				continue
+			}
+			stats.add(s.unmarshalItem(kv.Key, kv.Value), len(kv.Value), kv.ModRevision)
+		}
+		result[name] = stats
+	}
+	return result, nil
+}
+
+// markSeeded 标记服务在 envs.toml 中声明, 元数据不存在时创建
+func markSeeded(ctx context.Context, client *Client, serviceName string) error {
+	meta, modRevision, err := getServiceMeta(ctx, client, serviceName)
+	if err != nil {
+		return err
+	}
+	if meta != nil && meta.Seeded {
+		return nil
+	}
+
+	now := getCurrentTimestamp()
+	if meta == nil {
+		meta = &ServiceMeta{Name: serviceName, CreatedAt: now}
+	}
+	meta.Seeded = true
+	meta.UpdatedAt = now
+	return putServiceMeta(ctx, client, meta, modRevision)
+}
+
+// getServiceMeta 读取服务元数据及其修改版本号, 不存在时返回nil与0
+func getServiceMeta(ctx context.Context, client *Client, serviceName string) (*ServiceMeta, int64, error) {
+	resp, err := client.GetWithOptions(ctx, ServiceMetaPrefix+serviceName)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get service metadata: %w", err)
+	}
+	if len(resp.Kvs) == 0 {
+		return nil, 0, nil
+	}
+
+	var meta ServiceMeta
+	if err := json.Unmarshal(resp.Kvs[0].Value, &meta); err != nil {
+		return nil, 0, fmt.Errorf("failed to unmarshal service metadata: %w", err)
+	}
+	return &meta, resp.Kvs[0].ModRevision, nil
+}
+
+// putServiceMeta 写入服务元数据, 要求元数据的修改版本号仍为 modRevision(新建时为0),
+// 否则返回 ErrMetaConflict, 避免覆盖读取之后的修改(如 Protected 标记)
+func putServiceMeta(ctx context.Context, client *Client, meta *ServiceMeta, modRevision int64) error {
+	data, err := json.Marshal(meta)
+	if err != nil {
+		return fmt.Errorf("failed to marshal service metadata: %w", err)
+	}
+	key := ServiceMetaPrefix + meta.Name
+	resp, err := client.Txn(ctx).
+		If(clientv3.Compare(clientv3.ModRevision(key), "=", modRevision)).
+		Then(clientv3.OpPut(key, string(data))).
+		Commit()
+	if err != nil {
+		return fmt.Errorf("failed to set service metadata: %w", err)
+	}
+	if !resp.Succeeded {
+		return fmt.Errorf("%w: %s", ErrMetaConflict, meta.Name)
+	}
+	return nil
+}
+
+// checkProtected 检查服务是否受保护, 任一服务受保护时返回 ErrProtected。
+// 同时返回每个服务要求元数据在检查之后未被修改的前置条件, 写入时加入同一事务,
+// 避免检查与写入之间服务被设为受保护
+func (s *ConfigService) checkProtected(ctx context.Context, names ...string) (map[string]clientv3.Cmp, error) {
+	guards := make(map[string]clientv3.Cmp, len(names))
+	if len(names) == 0 {
+		return guards, nil
+	}
+	if s.Degraded() {
+		return nil, ErrUnavailable
+	}
+
+	// 服务较多时一次读取整个前缀
+	key, opts := ServiceMetaPrefix+names[0], []clientv3.OpOption(nil)
+	if len(names) > 1 {
+		key, opts = ServiceMetaPrefix, []clientv3.OpOption{clientv3.WithPrefix()}
+	}
+	resp, err := s.client.GetWithOptions(ctx, key, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get service metadata: %w", err)
+	}
+	revisions := make(map[string]int64, len(resp.Kvs))
+	protectedNames := make(map[string]bool)
+	for _, kv := range resp.Kvs {
+		name := strings.TrimPrefix(string(kv.Key), ServiceMetaPrefix)
+		var meta ServiceMeta
+		if err := json.Unmarshal(kv.Value, &meta); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal service metadata: %w", err)
+		}
+		revisions[name], protectedNames[name] = kv.ModRevision, meta.Protected
+	}
+
+	var protected []string
+	for _, name := range names {
+		if protectedNames[name] {
+			protected = append(protected, name)
+		}
+		// 元数据不存在时修改版本为0, 即要求写入时仍不存在
+		guards[name] = clientv3.Compare(clientv3.ModRevision(ServiceMetaPrefix+name), "=", revisions[name])
+	}
+	if len(protected) > 0 {
+		return nil, fmt.Errorf("%w: %s", ErrProtected, strings.Join(protected, ", "))
+	}
+	return guards, nil
+}
+
+// commitUnprotected 在服务未受保护的前提下提交写入, 检查与写入在同一事务中。
+// 服务元数据在检查之后被修改时重新检查, 多次仍被修改时返回 ErrMetaConflict
+func (s *ConfigService) commitUnprotected(ctx context.Context, serviceName string, ops ...clientv3.Op) (*clientv3.TxnResponse, error) {
+	for attempt := 0; attempt < metaConflictRetries; attempt++ {
+		guards, err := s.checkProtected(ctx, serviceName)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := s.client.Txn(ctx).If(guards[serviceName]).Then(ops...).Commit()
+		if err != nil {
+			return nil, fmt.Errorf("failed to commit transaction: %w", err)
+		}
+		if resp.Succeeded {
+			return resp, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrMetaConflict, serviceName)
+}
+
+// validateServiceName 校验服务名, 服务名会作为etcd键的一段
+func validateServiceName(name string) error {
+	if name == "" || strings.ContainsAny(name, "/*?[]\\") {
+		return fmt.Errorf("%w: %q", ErrInvalidServiceName, name)
+	}
+	return nil
+}
@@ -0,0 +1,257 @@
+package etcd
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"sort"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// serviceScanBatch 列出服务时每次读取的键数量, 读到的最后一个服务的剩余键会被跳过
+const serviceScanBatch = 1000
+
+// ErrInvalidPage 分页参数无效
+var ErrInvalidPage = errors.New("invalid page request")
+
+// PageOptions 分页选项
+type PageOptions struct {
+	// Limit 每页数量, 0表示不限制
+	Limit int
+	// PageToken 上一页返回的 NextPageToken, 为空时从头开始
+	PageToken string
+	// KeysOnly 只返回配置键, 不读取值
+	KeysOnly bool
+}
+
+// ConfigPage 一页服务配置
+type ConfigPage struct {
+	// Items 按键排序的配置项, KeysOnly 时只包含键与服务名
+	Items []*ConfigItem
+	// NextPageToken 下一页的令牌, 没有更多数据时为空
+	NextPageToken string
+}
+
+// ServicePage 一页服务名
+type ServicePage struct {
+	// Services 按etcd中的键顺序排列的服务名
+	Services []string
+	// NextPageToken 下一页的令牌, 没有更多数据时为空
+	NextPageToken string
+}
+
+// GetServiceConfigsPage 分页获取服务配置, 缓存可用时从缓存读取
+func (s *ConfigService) GetServiceConfigsPage(ctx context.Context, serviceName string, page PageOptions, opts ...ReadOption) (*ConfigPage, error) {
+	if page.Limit < 0 {
+		return nil, fmt.Errorf("%w: limit must not be negative", ErrInvalidPage)
+	}
+	after, err := decodePageToken(page.PageToken)
+	if err != nil {
+		return nil, err
+	}
+
+	fromCache, stale, err := s.readFrom(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	if fromCache {
+		if configs, ok := s.cache.service(serviceName, stale); ok {
+			s.cacheHit(true)
+			keys := make([]string, 0, len(configs))
+			for key := range configs {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+
+			keys, more := paginate(keys, after, page.Limit, "")
+			result := &ConfigPage{Items: make([]*ConfigItem, 0, len(keys))}
+			for _, key := range keys {
+				item := configs[key]
+				if page.KeysOnly {
+					item = &ConfigItem{Key: key, ServiceName: serviceName}
+				}
+				result.Items = append(result.Items, item)
+			}
+			if more {
+				result.NextPageToken = encodePageToken(keys[len(keys)-1])
+			}
+			return result, nil
+		}
+		if stale {
+			return nil, ErrUnavailable
+		}
+	}
+	s.cacheHit(false)
+
+	prefix := s.buildServicePrefix(serviceName)
+	from := prefix
+	if after != "" {
+		from = prefix + after + "\x00"
+	}
+	getOpts := []clientv3.OpOption{
+		clientv3.WithRange(clientv3.GetPrefixRangeEnd(prefix)),
+		clientv3.WithSort(clientv3.SortByKey, clientv3.SortAscend),
+	}
+	if page.Limit > 0 {
+		getOpts = append(getOpts, clientv3.WithLimit(int64(page.Limit)))
+	}
+	if page.KeysOnly {
+		getOpts = append(getOpts, clientv3.WithKeysOnly())
+	}
+
+	resp, err := s.client.GetWithOptions(ctx, from, getOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get service configs: %w", err)
+	}
+
+	result := &ConfigPage{Items: make([]*ConfigItem, 0, len(resp.Kvs))}
+	for _, kv := range resp.Kvs {
+		_, key, ok := parseConfigKey(string(kv.Key))
+		if !ok {
+			continue
+		}
+		if page.KeysOnly {
+			result.Items = append(result.Items, &ConfigItem{Key: key, ServiceName: serviceName})
+		} else if item := s.unmarshalItem(kv.Key, kv.Value); item != nil {
+			result.Items = append(result.Items, item)
+		}
+	}
+	if resp.More && len(resp.Kvs) > 0 {
+		last := string(resp.Kvs[len(resp.Kvs)-1].Key)
+		result.NextPageToken = encodePageToken(last[len(prefix):])
+	}
+
+	return result, nil
+}
+
+// ListServicesPage 分页列出服务, 缓存可用时从缓存的服务索引读取, 否则只读取键并跳过已读到服务的剩余键
+func (s *ConfigService) ListServicesPage(ctx context.Context, page PageOptions, opts ...ReadOption) (*ServicePage, error) {
+	if page.Limit < 0 {
+		return nil, fmt.Errorf("%w: limit must not be negative", ErrInvalidPage)
+	}
+	after, err := decodePageToken(page.PageToken)
+	if err != nil {
+		return nil, err
+	}
+
+	fromCache, stale, err := s.readFrom(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	if fromCache {
+		if services, ok := s.cache.serviceNames(stale); ok {
+			s.cacheHit(true)
+			services, more := paginate(services, after, page.Limit, "/")
+			return newServicePage(services, more), nil
+		}
+		if stale {
+			return nil, ErrUnavailable
+		}
+	}
+	s.cacheHit(false)
+
+	services, more, err := s.scanServices(ctx, after, page.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list services: %w", err)
+	}
+	return newServicePage(services, more), nil
+}
+
+// scanServices 按键顺序扫描after之后的服务, 每批读到的最后一个服务直接跳到其前缀之后
+func (s *ConfigService) scanServices(ctx context.Context, after string, limit int) ([]string, bool, error) {
+	from := ConfigPrefix
+	if after != "" {
+		from = clientv3.GetPrefixRangeEnd(s.buildServicePrefix(after))
+	}
+	end := clientv3.GetPrefixRangeEnd(ConfigPrefix)
+
+	var services []string
+	for {
+		resp, err := s.client.GetWithOptions(ctx, from,
+			clientv3.WithRange(end),
+			clientv3.WithKeysOnly(),
+			clientv3.WithSort(clientv3.SortByKey, clientv3.SortAscend),
+			clientv3.WithLimit(serviceScanBatch))
+		if err != nil {
+			return nil, false, err
+		}
+
+		for _, kv := range resp.Kvs {
+			serviceName, _, ok := parseConfigKey(string(kv.Key))
+			if !ok {
+				continue
+			}
+			// 同一服务的键是连续的
+			if n := len(services); n > 0 && services[n-1] == serviceName {
+				continue
+			}
+			if limit > 0 && len(services) == limit {
+				return services, true, nil
+			}
+			services = append(services, serviceName)
+		}
+		if !resp.More || len(resp.Kvs) == 0 {
+			return services, false, nil
+		}
+
+		last := string(resp.Kvs[len(resp.Kvs)-1].Key)
+		if serviceName, _, ok := parseConfigKey(last); ok {
+			from = clientv3.GetPrefixRangeEnd(s.buildServicePrefix(serviceName))
+		} else {
+			from = last + "\x00"
+		}
+	}
+}
+
+// newServicePage 构建服务分页结果
+func newServicePage(services []string, more bool) *ServicePage {
+	page := &ServicePage{Services: services}
+	if page.Services == nil {
+		page.Services = []string{}
+	}
+	if more {
+		page.NextPageToken = encodePageToken(services[len(services)-1])
+	}
+	return page
+}
+
+// sortServices 按etcd中的键顺序排列服务名, 与按前缀扫描的结果一致
+func sortServices(services []string) {
+	sort.Slice(services, func(i, j int) bool {
+		return services[i]+"/" < services[j]+"/"
+	})
+}
+
+// paginate 从按 值+suffix 排序的列表中取出after之后的一页, 并返回是否还有更多
+func paginate(sorted []string, after string, limit int, suffix string) ([]string, bool) {
+	start := 0
+	if after != "" {
+		start = sort.Search(len(sorted), func(i int) bool {
+			return sorted[i]+suffix > after+suffix
+		})
+	}
+	page := sorted[start:]
+	if limit > 0 && len(page) > limit {
+		return page[:limit], true
+	}
+	return page, false
+}
+
+// encodePageToken 将本页最后一个键编码为分页令牌
+func encodePageToken(last string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(last))
+}
+
+// decodePageToken 解析分页令牌, 返回上一页最后一个键
+func decodePageToken(token string) (string, error) {
+	if token == "" {
+		return "", nil
+	}
+	last, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || len(last) == 0 {
+		return "", fmt.Errorf("%w: malformed page token", ErrInvalidPage)
+	}
+	return string(last), nil
+}
@@ -0,0 +1,99 @@
+package etcd
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestPageToken(t *testing.T) {
+	for _, last := range []string{"Port", "a", "db.host", "a/b", "键名", "with space", "+/=?&"} {
+		token := encodePageToken(last)
+		got, err := decodePageToken(token)
+		if err != nil || got != last {
+			t.Errorf("decodePageToken(encodePageToken(%q)) = %q, %v", last, got, err)
+		}
+	}
+
+	if got, err := decodePageToken(""); err != nil || got != "" {
+		t.Errorf("decodePageToken(\"\") = %q, %v, want empty", got, err)
+	}
+	for _, token := range []string{"!!!", "UG9ydA==", "a"} {
+		if _, err := decodePageToken(token); !errors.Is(err, ErrInvalidPage) {
+			t.Errorf("decodePageToken(%q) error = %v, want ErrInvalidPage", token, err)
+		}
+	}
+}
+
+func TestSortServices(t *testing.T) {
+	services := []string{"a0", "a", "a.b", "b", "a-b"}
+	sortServices(services)
+	// 按 服务名+"/" 的键顺序, '-' 与 '.' 小于 '/', '0' 大于 '/'
+	want := []string{"a-b", "a.b", "a", "a0", "b"}
+	if !reflect.DeepEqual(services, want) {
+		t.Errorf("sortServices() = %v, want %v", services, want)
+	}
+}
+
+func TestPaginate(t *testing.T) {
+	keys := []string{"a", "b", "c", "d", "e"}
+	services := []string{"a-b", "a.b", "a", "a0", "b"}
+
+	tests := []struct {
+		name     string
+		sorted   []string
+		after    string
+		limit    int
+		suffix   string
+		want     []string
+		wantMore bool
+	}{
+		{name: "no limit", sorted: keys, want: keys},
+		{name: "first page", sorted: keys, limit: 2, want: []string{"a", "b"}, wantMore: true},
+		{name: "middle page", sorted: keys, after: "b", limit: 2, want: []string{"c", "d"}, wantMore: true},
+		{name: "last page", sorted: keys, after: "c", limit: 2, want: []string{"d", "e"}},
+		{name: "exact limit", sorted: keys, limit: 5, want: keys},
+		{name: "after last", sorted: keys, after: "e", limit: 2, want: []string{}},
+		{name: "after removed key", sorted: keys, after: "bb", limit: 2, want: []string{"c", "d"}, wantMore: true},
+		{name: "after before first", sorted: keys, after: "0", want: keys},
+		{name: "empty", sorted: []string{}, limit: 2, want: []string{}},
+		{name: "service order", sorted: services, after: "a.b", limit: 2, suffix: "/", want: []string{"a", "a0"}, wantMore: true},
+		{name: "service prefix", sorted: services, after: "a", suffix: "/", want: []string{"a0", "b"}},
+		{name: "service removed", sorted: services, after: "a/", suffix: "/", want: []string{"a0", "b"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, more := paginate(tt.sorted, tt.after, tt.limit, tt.suffix)
+			if len(got) != len(tt.want) || (len(got) > 0 && !reflect.DeepEqual(got, tt.want)) || more != tt.wantMore {
+				t.Errorf("paginate(%q, %d) = %v, %v, want %v, %v", tt.after, tt.limit, got, more, tt.want, tt.wantMore)
+			}
+		})
+	}
+}
+
+func TestPaginateAllPages(t *testing.T) {
+	services := []string{"a-b", "a.b", "a", "a0", "b", "c"}
+	for limit := 1; limit <= len(services)+1; limit++ {
+		var all []string
+		token := ""
+		for pages := 0; ; pages++ {
+			if pages > len(services) {
+				t.Fatalf("limit %d: too many pages", limit)
+			}
+			after, err := decodePageToken(token)
+			if err != nil {
+				t.Fatalf("limit %d: decodePageToken() error = %v", limit, err)
+			}
+			page := newServicePage(paginate(services, after, limit, "/"))
+			all = append(all, page.Services...)
+			if page.NextPageToken == "" {
+				break
+			}
+			token = page.NextPageToken
+		}
+		if !reflect.DeepEqual(all, services) {
+			t.Errorf("limit %d: pages = %v, want %v", limit, all, services)
+		}
+	}
+}
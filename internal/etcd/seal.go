@@ -0,0 +1,108 @@
+package etcd
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// LoadKey 读取base64编码的32字节密钥
+func LoadKey(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key: %w", err)
+	}
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil || len(key) != 32 {
+		return nil, fmt.Errorf("key %s must be 32 bytes encoded in base64", path)
+	}
+	return key, nil
+}
+
+// SetArchiveKey 设置归档密钥, 有权读取敏感配置的调用方导出时敏感配置的值以此密钥加密, 为nil时不导出
+func (s *ConfigService) SetArchiveKey(key []byte) {
+	s.archiveKey = key
+}
+
+// OpenArchive 解密归档中加密的敏感配置, 解密后的配置 Sealed 为false。
+// 未配置归档密钥或密钥不同时返回 ErrInvalidArchive
+func (s *ConfigService) OpenArchive(archive *Archive) error {
+	for i := range archive.Services {
+		svc := &archive.Services[i]
+		for j := range svc.Configs {
+			cfg := &svc.Configs[j]
+			if !cfg.Sealed {
+				continue
+			}
+			if s.archiveKey == nil {
+				return fmt.Errorf("%w: it contains encrypted secrets but no archive key is configured", ErrInvalidArchive)
+			}
+			sealed, ok := cfg.Value.(string)
+			if !ok {
+				return fmt.Errorf("%w: encrypted value of %s/%s is not a string", ErrInvalidArchive, svc.Name, cfg.Key)
+			}
+			value, err := openValue(s.archiveKey, svc.Name+"/"+cfg.Key, sealed)
+			if err != nil {
+				return fmt.Errorf("%w: failed to decrypt %s/%s, the archive may be encrypted with another key",
+					ErrInvalidArchive, svc.Name, cfg.Key)
+			}
+			cfg.Value, cfg.Sealed = value, false
+		}
+	}
+	return nil
+}
+
+// sealValue 以AES-256-GCM加密值的JSON编码, name(service/key)作为附加数据, 防止密文被挪用到其他配置
+func sealValue(key []byte, name string, value interface{}) (string, error) {
+	plaintext, err := json.Marshal(value)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal value: %w", err)
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	return base64.StdEncoding.EncodeToString(gcm.Seal(nonce, nonce, plaintext, []byte(name))), nil
+}
+
+// openValue 解密 sealValue 的结果
+func openValue(key []byte, name, sealed string) (interface{}, error) {
+	data, err := base64.StdEncoding.DecodeString(sealed)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < gcm.NonceSize() {
+		return nil, errors.New("encrypted value too short")
+	}
+	plaintext, err := gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], []byte(name))
+	if err != nil {
+		return nil, err
+	}
+	var value interface{}
+	if err := json.Unmarshal(plaintext, &value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
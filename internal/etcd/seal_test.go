@@ -0,0 +1,96 @@
+package etcd
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestSealValue(t *testing.T) {
+	key := bytes.Repeat([]byte{1}, 32)
+	values := []interface{}{
+		"secret",
+		"",
+		float64(42),
+		true,
+		nil,
+		map[string]interface{}{"user": "admin", "ports": []interface{}{float64(1), float64(2)}},
+	}
+	for _, value := range values {
+		sealed, err := sealValue(key, "Palace/DBPassword", value)
+		if err != nil {
+			t.Fatalf("sealValue(%v) error = %v", value, err)
+		}
+		got, err := openValue(key, "Palace/DBPassword", sealed)
+		if err != nil {
+			t.Fatalf("openValue(%v) error = %v", value, err)
+		}
+		if !reflect.DeepEqual(got, value) {
+			t.Errorf("openValue(sealValue(%v)) = %v", value, got)
+		}
+	}
+}
+
+func TestOpenValueRejects(t *testing.T) {
+	key := bytes.Repeat([]byte{1}, 32)
+	sealed, err := sealValue(key, "Palace/DBPassword", "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		key    []byte
+		config string
+		sealed string
+	}{
+		{name: "other key", key: bytes.Repeat([]byte{2}, 32), config: "Palace/DBPassword", sealed: sealed},
+		{name: "other config", key: key, config: "Palace/AESKey", sealed: sealed},
+		{name: "not base64", key: key, config: "Palace/DBPassword", sealed: "not base64!"},
+		{name: "too short", key: key, config: "Palace/DBPassword", sealed: "AAAA"},
+		{name: "tampered", key: key, config: "Palace/DBPassword", sealed: sealed[:len(sealed)-4] + "AAAA"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := openValue(tt.key, tt.config, tt.sealed); err == nil {
+				t.Errorf("openValue() succeeded, want error")
+			}
+		})
+	}
+}
+
+func TestOpenArchive(t *testing.T) {
+	key := bytes.Repeat([]byte{1}, 32)
+	sealed, err := sealValue(key, "Palace/DBPassword", "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	newArchive := func() *Archive {
+		return &Archive{Services: []ArchiveService{{
+			Name: "Palace",
+			Configs: []ArchiveConfig{
+				{Key: "DBPassword", Value: sealed, Encrypt: true, Sealed: true},
+				{Key: "Port", Value: float64(22222)},
+			},
+		}}}
+	}
+
+	s := &ConfigService{archiveKey: key}
+	arc := newArchive()
+	if err := s.OpenArchive(arc); err != nil {
+		t.Fatalf("OpenArchive() error = %v", err)
+	}
+	if cfg := arc.Services[0].Configs[0]; cfg.Value != "secret" || cfg.Sealed {
+		t.Errorf("OpenArchive() config = %+v, want opened value", cfg)
+	}
+
+	for name, s := range map[string]*ConfigService{
+		"no key":    {},
+		"other key": {archiveKey: bytes.Repeat([]byte{2}, 32)},
+	} {
+		if err := s.OpenArchive(newArchive()); !errors.Is(err, ErrInvalidArchive) {
+			t.Errorf("%s: OpenArchive() error = %v, want ErrInvalidArchive", name, err)
+		}
+	}
+}
@@ -0,0 +1,266 @@
+package etcd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// 搜索匹配的字段
+const (
+	SearchFieldKey         = "key"
+	SearchFieldValue       = "value"
+	SearchFieldDescription = "description"
+)
+
+const (
+	// MaskedValue 未授权的调用方看到的敏感配置值
+	MaskedValue = "******"
+
+	defaultSearchLimit = 100
+	maxSearchLimit     = 1000
+)
+
+// ErrInvalidSearch 搜索参数无效
+var ErrInvalidSearch = errors.New("invalid search request")
+
+// SearchOptions 搜索条件
+type SearchOptions struct {
+	// Query 子串, Regex 为true时为正则表达式(语法同 regexp)
+	Query      string
+	Regex      bool
+	IgnoreCase bool
+	// Fields 匹配的字段, 为空时匹配键、值与描述
+	Fields []string
+	// Services 服务名, 支持通配符(语法同 path.Match), 为空时搜索所有服务
+	Services []string
+	// Labels 服务元数据中必须包含的标签
+	Labels map[string]string
+	// IncludeSecrets 调用方有权读取敏感配置, 否则敏感配置的值不参与匹配且在结果中隐藏
+	IncludeSecrets bool
+	// Limit 最多返回的结果数, 0表示使用默认值
+	Limit int
+}
+
+// SearchMatch 一个命中的配置项
+type SearchMatch struct {
+	Config *ConfigItem `json:"config"`
+	// Fields 命中的字段
+	Fields []string `json:"matched"`
+}
+
+// SearchResult 搜索结果
+type SearchResult struct {
+	// Matches 按服务名与配置键排序
+	Matches []SearchMatch `json:"matches"`
+	// Truncated 命中数超过 Limit, 结果被截断
+	Truncated bool `json:"truncated"`
+	// Revision 搜索所基于的配置版本号
+	Revision int64 `json:"revision"`
+}
+
+// Search 按子串或正则表达式搜索配置的键、值与描述, 缓存可用时从缓存读取
+func (s *ConfigService) Search(ctx context.Context, search SearchOptions, opts ...ReadOption) (*SearchResult, error) {
+	match, err := compileSearch(search)
+	if err != nil {
+		return nil, err
+	}
+	fields, err := searchFields(search.Fields)
+	if err != nil {
+		return nil, err
+	}
+	limit := search.Limit
+	if limit < 0 || limit > maxSearchLimit {
+		return nil, fmt.Errorf("%w: limit must be between 0 and %d", ErrInvalidSearch, maxSearchLimit)
+	}
+	if limit == 0 {
+		limit = defaultSearchLimit
+	}
+	for _, pattern := range search.Services {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("%w: invalid service pattern %q", ErrInvalidSearch, pattern)
+		}
+	}
+
+	// 只有一个不含通配符的服务时只读取该服务
+	serviceName := ""
+	if len(search.Services) == 1 && !strings.ContainsAny(search.Services[0], "*?[\\") {
+		serviceName = search.Services[0]
+	}
+	items, revision, err := s.searchItems(ctx, serviceName, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var metas map[string]*ServiceMeta
+	if len(search.Labels) > 0 {
+		if metas, err = s.loadServiceMetas(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].ServiceName != items[j].ServiceName {
+			return items[i].ServiceName < items[j].ServiceName
+		}
+		return items[i].Key < items[j].Key
+	})
+
+	result := &SearchResult{Matches: []SearchMatch{}, Revision: revision}
+	for _, item := range items {
+		if !matchServices(search.Services, item.ServiceName) {
+			continue
+		}
+		if len(search.Labels) > 0 && !matchLabels(metas[item.ServiceName], search.Labels) {
+			continue
+		}
+
+		hidden := item.Encrypt && !search.IncludeSecrets
+		var matched []string
+		for _, field := range fields {
+			var text string
+			switch field {
+			case SearchFieldKey:
+				text = item.Key
+			case SearchFieldDescription:
+				text = item.Description
+			case SearchFieldValue:
+				if hidden {
+					continue
+				}
+				text = valueText(item.Value)
+			}
+			if match(text) {
+				matched = append(matched, field)
+			}
+		}
+		if len(matched) == 0 {
+			continue
+		}
+
+		if len(result.Matches) == limit {
+			result.Truncated = true
+			break
+		}
+		if hidden {
+			item.Value = MaskedValue
+		}
+		result.Matches = append(result.Matches, SearchMatch{Config: item, Fields: matched})
+	}
+	return result, nil
+}
+
+// searchItems 读取待搜索的配置项, serviceName为空时读取所有服务
+func (s *ConfigService) searchItems(ctx context.Context, serviceName string, opts []ReadOption) ([]*ConfigItem, int64, error) {
+	fromCache, stale, err := s.readFrom(ctx, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	if fromCache {
+		if items, revision, ok := s.cache.items(serviceName, "", stale); ok {
+			s.cacheHit(true)
+			return items, revision, nil
+		}
+		if stale {
+			return nil, 0, ErrUnavailable
+		}
+	}
+	s.cacheHit(false)
+
+	items, revision, err := s.Snapshot(ctx, serviceName, "")
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to search configs: %w", err)
+	}
+	return items, revision, nil
+}
+
+// compileSearch 根据搜索条件构建匹配函数
+func compileSearch(search SearchOptions) (func(string) bool, error) {
+	if search.Query == "" {
+		return nil, fmt.Errorf("%w: query is required", ErrInvalidSearch)
+	}
+
+	if search.Regex {
+		expr := search.Query
+		if search.IgnoreCase {
+			expr = "(?i)" + expr
+		}
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidSearch, err)
+		}
+		return re.MatchString, nil
+	}
+
+	if search.IgnoreCase {
+		query := strings.ToLower(search.Query)
+		return func(text string) bool {
+			return strings.Contains(strings.ToLower(text), query)
+		}, nil
+	}
+	return func(text string) bool {
+		return strings.Contains(text, search.Query)
+	}, nil
+}
+
+// searchFields 校验并去重搜索字段, 为空时返回所有字段
+func searchFields(fields []string) ([]string, error) {
+	if len(fields) == 0 {
+		return []string{SearchFieldKey, SearchFieldValue, SearchFieldDescription}, nil
+	}
+
+	result := make([]string, 0, len(fields))
+	seen := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		switch field {
+		case SearchFieldKey, SearchFieldValue, SearchFieldDescription:
+		default:
+			return nil, fmt.Errorf("%w: unknown field %q", ErrInvalidSearch, field)
+		}
+		if !seen[field] {
+			seen[field] = true
+			result = append(result, field)
+		}
+	}
+	return result, nil
+}
+
+// matchServices 判断服务名是否匹配任一条件, 没有条件时全部匹配
+func matchServices(patterns []string, serviceName string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, serviceName); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// matchLabels 判断服务元数据是否包含所有标签
+func matchLabels(meta *ServiceMeta, labels map[string]string) bool {
+	if meta == nil {
+		return false
+	}
+	for name, value := range labels {
+		if v, ok := meta.Labels[name]; !ok || v != value {
+			return false
+		}
+	}
+	return true
+}
+
+// valueText 配置值的文本形式, 字符串按原样匹配, 其他值按JSON匹配
+func valueText(value interface{}) string {
+	if text, ok := value.(string); ok {
+		return text
+	}
+	data, _ := json.Marshal(value)
+	return string(data)
+}
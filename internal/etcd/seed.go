@@ -0,0 +1,353 @@
+package etcd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"nidavellir/internal/config"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.uber.org/zap"
+)
+
+// 种子配置(envs.toml)的写入模式
+const (
+	// SeedSkipIfAny 已存在任何配置时不写入
+	SeedSkipIfAny = "skip-if-any"
+	// SeedAddMissing 只创建不存在的配置, 不覆盖已有的值
+	SeedAddMissing = "add-missing"
+	// SeedForce 创建不存在的配置并覆盖值不同的配置
+	SeedForce = "force"
+)
+
+// 种子配置的变更类型
+const (
+	SeedActionCreate = "create"
+	SeedActionUpdate = "update"
+	// SeedActionConflict 已有的值与种子不同, add-missing 模式下保留已有的值
+	SeedActionConflict = "conflict"
+	// SeedActionProtected 服务受保护, 种子不写入, 需要通过草稿修改
+	SeedActionProtected = "protected"
+)
+
+var (
+	// ErrInvalidSeedMode 不支持的写入模式
+	ErrInvalidSeedMode = errors.New("invalid seed mode")
+	// ErrSeedConflict 写入期间配置被并发修改
+	ErrSeedConflict = errors.New("configs changed while seeding, retry")
+)
+
+// SeedChange 一个种子配置的变更
+type SeedChange struct {
+	Action      string `json:"action"`
+	ServiceName string `json:"service_name"`
+	Key         string `json:"key"`
+	// Value 种子中的值
+	Value       string `json:"value"`
+	Description string `json:"description,omitempty"`
+	// Current 已有的值, 新建时为空, 敏感配置隐藏
+	Current interface{} `json:"current,omitempty"`
+
+	existing    *ConfigItem
+	modRevision int64
+}
+
+// SeedPlan 种子配置的写入计划, 执行后为实际的写入结果
+type SeedPlan struct {
+	Mode   string `json:"mode"`
+	DryRun bool   `json:"dry_run"`
+	// Skipped skip-if-any 模式下已存在配置, 未做任何修改
+	Skipped bool `json:"skipped"`
+	// Changes 按服务名与配置键排序
+	Changes []SeedChange `json:"changes"`
+	// Unchanged 与已有配置相同的种子数
+	Unchanged int `json:"unchanged"`
+	// Revision 计划所基于的版本号, 写入后为写入的版本号
+	Revision int64 `json:"revision"`
+}
+
+// Count 指定类型的变更数
+func (p *SeedPlan) Count(action string) int {
+	n := 0
+	for _, change := range p.Changes {
+		if change.Action == action {
+			n++
+		}
+	}
+	return n
+}
+
+// ParseSeedMode 解析写入模式, 为空时使用 skip-if-any
+func ParseSeedMode(mode string) (string, error) {
+	switch mode {
+	case "":
+		return SeedSkipIfAny, nil
+	case SeedSkipIfAny, SeedAddMissing, SeedForce:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("%w: %q (skip-if-any, add-missing, force)", ErrInvalidSeedMode, mode)
+	}
+}
+
+// PlanSeed 比较种子配置与etcd中的配置, 生成写入计划
+func (s *ConfigService) PlanSeed(ctx context.Context, envs *config.EnvConfig, mode string) (*SeedPlan, error) {
+	mode, err := ParseSeedMode(mode)
+	if err != nil {
+		return nil, err
+	}
+	seeds := seedItems(envs)
+	for _, seed := range seeds {
+		if err := validateServiceName(seed.ServiceName); err != nil {
+			return nil, err
+		}
+	}
+
+	resp, err := s.client.GetWithOptions(ctx, ConfigPrefix, clientv3.WithPrefix())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get configs: %w", err)
+	}
+	plan := &SeedPlan{Mode: mode, DryRun: true, Changes: []SeedChange{}, Revision: resp.Header.Revision}
+	if mode == SeedSkipIfAny && len(resp.Kvs) > 0 {
+		plan.Skipped = true
+		return plan, nil
+	}
+
+	existing := make(map[string]*SeedChange, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		if item := s.unmarshalItem(kv.Key, kv.Value); item != nil {
+			existing[string(kv.Key)] = &SeedChange{existing: item, modRevision: kv.ModRevision}
+		}
+	}
+
+	for _, seed := range seeds {
+		change := SeedChange{
+			Action:      SeedActionCreate,
+			ServiceName: seed.ServiceName,
+			Key:         seed.Key,
+			Value:       valueText(seed.Value),
+			Description: seed.Description,
+		}
+		if current, ok := existing[s.buildConfigKey(seed.ServiceName, seed.Key)]; ok {
+			item := current.existing
+			sameValue := valueText(item.Value) == change.Value
+			// 种子没有描述时保留已有的描述
+			sameDescription := change.Description == "" || item.Description == change.Description
+			switch {
+			case sameValue && (mode != SeedForce || sameDescription):
+				plan.Unchanged++
+				continue
+			case mode == SeedForce:
+				change.Action = SeedActionUpdate
+			default:
+				change.Action = SeedActionConflict
+			}
+			change.existing, change.modRevision = item, current.modRevision
+			change.Current = item.Value
+			if item.Encrypt {
+				change.Current = MaskedValue
+			}
+		}
+		plan.Changes = append(plan.Changes, change)
+	}
+	return plan, s.skipProtected(ctx, plan)
+}
+
+// skipProtected 受保护的服务中的新建与覆盖改为 SeedActionProtected
+func (s *ConfigService) skipProtected(ctx context.Context, plan *SeedPlan) error {
+	var names []string
+	for _, change := range plan.Changes {
+		if len(names) == 0 || names[len(names)-1] != change.ServiceName {
+			names = append(names, change.ServiceName)
+		}
+	}
+	if len(names) == 0 {
+		return nil
+	}
+	metas, err := s.serviceMetas(ctx, names)
+	if err != nil {
+		return err
+	}
+	for i, change := range plan.Changes {
+		if meta := metas[change.ServiceName]; meta != nil && meta.Protected && change.Action != SeedActionConflict {
+			plan.Changes[i].Action = SeedActionProtected
+		}
+	}
+	return nil
+}
+
+// ApplySeed 按写入模式将种子配置写入etcd, 并标记种子中声明的服务。
+// 新建的配置要求键仍不存在, 覆盖的配置要求未被修改, 否则返回 ErrSeedConflict
+func (s *ConfigService) ApplySeed(ctx context.Context, envs *config.EnvConfig, mode string) (*SeedPlan, error) {
+	if s.Degraded() {
+		return nil, ErrReadOnly
+	}
+	plan, err := s.PlanSeed(ctx, envs, mode)
+	if err != nil {
+		return nil, err
+	}
+	plan.DryRun = false
+	// skip-if-any 模式下已存在配置时不做任何修改, 包括服务的标记
+	if plan.Skipped {
+		return plan, nil
+	}
+
+	for _, env := range envs.Service {
+		if env.Name == "" {
+			continue
+		}
+		if err := markSeeded(ctx, s.client, env.Name); err != nil {
+			return nil, fmt.Errorf("failed to mark service %s seeded: %w", env.Name, err)
+		}
+	}
+
+	batch := s.newTxnBatch(ctx, ErrSeedConflict)
+	now := getCurrentTimestamp()
+	for _, change := range plan.Changes {
+		configKey := s.buildConfigKey(change.ServiceName, change.Key)
+		item := ConfigItem{
+			Key:         change.Key,
+			Value:       change.Value,
+			ServiceName: change.ServiceName,
+			Description: change.Description,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		}
+		var cmp clientv3.Cmp
+		switch change.Action {
+		case SeedActionCreate:
+			cmp = clientv3.Compare(clientv3.CreateRevision(configKey), "=", 0)
+		case SeedActionUpdate:
+			item.CreatedAt = change.existing.CreatedAt
+			item.Encrypt = change.existing.Encrypt
+			if item.Description == "" {
+				item.Description = change.existing.Description
+			}
+			cmp = clientv3.Compare(clientv3.ModRevision(configKey), "=", change.modRevision)
+		default:
+			continue
+		}
+
+		data, err := json.Marshal(item)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal config item: %w", err)
+		}
+		if err := batch.add(cmp, clientv3.OpPut(configKey, string(data))); err != nil {
+			return nil, err
+		}
+	}
+	if err := batch.flush(); err != nil {
+		return nil, err
+	}
+	if batch.revision > 0 {
+		plan.Revision = batch.revision
+	}
+	return plan, nil
+}
+
+// seedItems 种子中的配置项, 按服务名与配置键排序, 同一配置重复声明时以最后一次为准
+func seedItems(envs *config.EnvConfig) []*ConfigItem {
+	if envs == nil {
+		return nil
+	}
+	seen := make(map[[2]string]*ConfigItem)
+	for _, env := range envs.Service {
+		if env.Name == "" {
+			continue
+		}
+		for _, e := range env.Envs {
+			if e.Key == "" {
+				continue
+			}
+			seen[[2]string{env.Name, e.Key}] = &ConfigItem{
+				Key:         e.Key,
+				Value:       e.Val,
+				ServiceName: env.Name,
+				Description: e.Description,
+			}
+		}
+	}
+
+	items := make([]*ConfigItem, 0, len(seen))
+	for _, item := range seen {
+		items = append(items, item)
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].ServiceName != items[j].ServiceName {
+			return items[i].ServiceName < items[j].ServiceName
+		}
+		return items[i].Key < items[j].Key
+	})
+	return items
+}
+
+// Seeder 读取种子文件并写入etcd, 支持启动后重新加载
+type Seeder struct {
+	service *ConfigService
+	load    func() (*config.EnvConfig, error)
+	cfg     config.SeedConfig
+	logger  *zap.Logger
+	// mu 同一时间只执行一次加载
+	mu sync.Mutex
+}
+
+// NewSeeder 创建种子加载器, load 每次加载时读取种子文件
+func NewSeeder(service *ConfigService, load func() (*config.EnvConfig, error), cfg config.SeedConfig, logger *zap.Logger) *Seeder {
+	return &Seeder{service: service, load: load, cfg: cfg, logger: logger}
+}
+
+// Reload 重新读取种子文件并按写入模式写入, mode为空时使用配置的模式, dryRun 时只返回写入计划
+func (s *Seeder) Reload(ctx context.Context, mode string, dryRun bool) (*SeedPlan, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if mode == "" {
+		mode = s.cfg.Mode
+	}
+	envs, err := s.load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load seed file: %w", err)
+	}
+	if envs == nil {
+		envs = &config.EnvConfig{}
+	}
+
+	var plan *SeedPlan
+	if dryRun {
+		plan, err = s.service.PlanSeed(ctx, envs, mode)
+	} else {
+		plan, err = s.service.ApplySeed(ctx, envs, mode)
+	}
+	if err != nil {
+		return nil, err
+	}
+	s.log(plan)
+	return plan, nil
+}
+
+// log 记录写入计划
+func (s *Seeder) log(plan *SeedPlan) {
+	if plan.Skipped {
+		s.logger.Info("Service config already init, seed skipped", zap.String("mode", plan.Mode))
+		return
+	}
+	for _, change := range plan.Changes {
+		s.logger.Info("Seed config",
+			zap.String("action", change.Action),
+			zap.String("service", change.ServiceName),
+			zap.String("key", change.Key),
+			zap.Bool("dry_run", plan.DryRun))
+	}
+	s.logger.Info("Seed finished",
+		zap.String("mode", plan.Mode),
+		zap.Bool("dry_run", plan.DryRun),
+		zap.Int("create", plan.Count(SeedActionCreate)),
+		zap.Int("update", plan.Count(SeedActionUpdate)),
+		zap.Int("conflict", plan.Count(SeedActionConflict)),
+		zap.Int("protected", plan.Count(SeedActionProtected)),
+		zap.Int("unchanged", plan.Unchanged),
+		zap.Int64("revision", plan.Revision))
+}
@@ -5,8 +5,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+	"sync/atomic"
 	"time"
 
+	clientv3 "go.etcd.io/etcd/client/v3"
 	"go.uber.org/zap"
 )
 
@@ -19,6 +21,12 @@ const (
 type ConfigService struct {
 	client *Client
 	logger *zap.Logger
+	// cache 配置缓存, 由 RunCache 维护, 未运行时读取直接访问etcd
+	cache *configCache
+	// archiveKey 归档中敏感配置的加密密钥, 为nil时不导出敏感配置的值
+	archiveKey []byte
+	// degradedSince 进入降级模式的时间, 0表示etcd可用
+	degradedSince atomic.Int64
 }
 
 // ConfigItem 配置项
@@ -27,8 +35,10 @@ type ConfigItem struct {
 	Value       interface{} `json:"value"`
 	ServiceName string      `json:"service_name"`
 	Description string      `json:"description"`
-	CreatedAt   int64       `json:"created_at"`
-	UpdatedAt   int64       `json:"updated_at"`
+	// Encrypt 敏感配置, 搜索时只有授权的调用方才能匹配和查看其值
+	Encrypt   bool  `json:"encrypt,omitempty"`
+	CreatedAt int64 `json:"created_at"`
+	UpdatedAt int64 `json:"updated_at"`
 }
 
 // NewConfigService 创建配置服务
@@ -36,11 +46,16 @@ func NewConfigService(client *Client, logger *zap.Logger) *ConfigService {
 	return &ConfigService{
 		client: client,
 		logger: logger,
+		cache:  newConfigCache(),
 	}
 }
 
-// SetConfig 设置服务配置
-func (s *ConfigService) SetConfig(ctx context.Context, serviceName, key string, value interface{}, description string) error {
+// SetConfig 设置服务配置, 受保护的服务返回 ErrProtected
+func (s *ConfigService) SetConfig(ctx context.Context, serviceName, key string, value interface{}, description string, encrypt bool) error {
+	if s.Degraded() {
+		return ErrReadOnly
+	}
+
 	configKey := s.buildConfigKey(serviceName, key)
 
 	configItem := ConfigItem{
@@ -48,6 +63,7 @@ func (s *ConfigService) SetConfig(ctx context.Context, serviceName, key string,
 		Value:       value,
 		ServiceName: serviceName,
 		Description: description,
+		Encrypt:     encrypt,
 		CreatedAt:   getCurrentTimestamp(),
 		UpdatedAt:   getCurrentTimestamp(),
 	}
@@ -70,9 +86,11 @@ func (s *ConfigService) SetConfig(ctx context.Context, serviceName, key string,
 		return fmt.Errorf("failed to marshal config item: %w", err)
 	}
 
-	if err := s.client.Put(ctx, configKey, string(data)); err != nil {
-		return fmt.Errorf("failed to set config: %w", err)
+	resp, err := s.commitUnprotected(ctx, serviceName, clientv3.OpPut(configKey, string(data)))
+	if err != nil {
+		return err
 	}
+	s.cache.wrote(resp.Header.Revision)
 
 	s.logger.Info("Config set successfully",
 		zap.String("service", serviceName),
@@ -81,8 +99,23 @@ func (s *ConfigService) SetConfig(ctx context.Context, serviceName, key string,
 	return nil
 }
 
-// GetConfig 获取服务配置
-func (s *ConfigService) GetConfig(ctx context.Context, serviceName, key string) (*ConfigItem, error) {
+// GetConfig 获取服务配置, 缓存可用时从缓存读取
+func (s *ConfigService) GetConfig(ctx context.Context, serviceName, key string, opts ...ReadOption) (*ConfigItem, error) {
+	fromCache, stale, err := s.readFrom(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	if fromCache {
+		if item, ok := s.cache.get(serviceName, key, stale); ok {
+			s.cacheHit(true)
+			return item, nil
+		}
+		if stale {
+			return nil, ErrUnavailable
+		}
+	}
+	s.cacheHit(false)
+
 	configKey := s.buildConfigKey(serviceName, key)
 
 	data, err := s.client.Get(ctx, configKey)
@@ -102,8 +135,23 @@ func (s *ConfigService) GetConfig(ctx context.Context, serviceName, key string)
 	return &configItem, nil
 }
 
-// GetServiceConfigs 获取服务的所有配置
-func (s *ConfigService) GetServiceConfigs(ctx context.Context, serviceName string) (map[string]*ConfigItem, error) {
+// GetServiceConfigs 获取服务的所有配置, 缓存可用时从缓存读取
+func (s *ConfigService) GetServiceConfigs(ctx context.Context, serviceName string, opts ...ReadOption) (map[string]*ConfigItem, error) {
+	fromCache, stale, err := s.readFrom(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	if fromCache {
+		if configs, ok := s.cache.service(serviceName, stale); ok {
+			s.cacheHit(true)
+			return configs, nil
+		}
+		if stale {
+			return nil, ErrUnavailable
+		}
+	}
+	s.cacheHit(false)
+
 	prefix := s.buildServicePrefix(serviceName)
 
 	data, err := s.client.GetWithPrefix(ctx, prefix)
@@ -130,12 +178,20 @@ func (s *ConfigService) GetServiceConfigs(ctx context.Context, serviceName strin
 	return result, nil
 }
 
-// DeleteConfig 删除服务配置
+// DeleteConfig 删除服务配置, 受保护的服务返回 ErrProtected
 func (s *ConfigService) DeleteConfig(ctx context.Context, serviceName, key string) error {
+	if s.Degraded() {
+		return ErrReadOnly
+	}
+
 	configKey := s.buildConfigKey(serviceName, key)
 
-	if err := s.client.Delete(ctx, configKey); err != nil {
-		return fmt.Errorf("failed to delete config: %w", err)
+	resp, err := s.commitUnprotected(ctx, serviceName, clientv3.OpDelete(configKey))
+	if err != nil {
+		return err
+	}
+	if resp.Responses[0].GetResponseDeleteRange().Deleted > 0 {
+		s.cache.wrote(resp.Header.Revision)
 	}
 
 	s.logger.Info("Config deleted successfully",
@@ -145,12 +201,20 @@ func (s *ConfigService) DeleteConfig(ctx context.Context, serviceName, key strin
 	return nil
 }
 
-// DeleteServiceConfigs 删除服务的所有配置
+// DeleteServiceConfigs 删除服务的所有配置, 受保护的服务返回 ErrProtected
 func (s *ConfigService) DeleteServiceConfigs(ctx context.Context, serviceName string) error {
+	if s.Degraded() {
+		return ErrReadOnly
+	}
+
 	prefix := s.buildServicePrefix(serviceName)
 
-	if err := s.client.DeleteWithPrefix(ctx, prefix); err != nil {
-		return fmt.Errorf("failed to delete service configs: %w", err)
+	resp, err := s.commitUnprotected(ctx, serviceName, clientv3.OpDelete(prefix, clientv3.WithPrefix()))
+	if err != nil {
+		return err
+	}
+	if resp.Responses[0].GetResponseDeleteRange().Deleted > 0 {
+		s.cache.wrote(resp.Header.Revision)
 	}
 
 	s.logger.Info("Service configs deleted successfully",
@@ -159,29 +223,13 @@ func (s *ConfigService) DeleteServiceConfigs(ctx context.Context, serviceName st
 	return nil
 }
 
-// ListServices 列出所有服务
-func (s *ConfigService) ListServices(ctx context.Context) ([]string, error) {
-	data, err := s.client.GetWithPrefix(ctx, ConfigPrefix)
+// ListServices 列出所有服务, 按etcd中的键顺序排列
+func (s *ConfigService) ListServices(ctx context.Context, opts ...ReadOption) ([]string, error) {
+	page, err := s.ListServicesPage(ctx, PageOptions{}, opts...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list services: %w", err)
+		return nil, err
 	}
-
-	services := make(map[string]bool)
-	for key := range data {
-		// 提取服务名称
-		relativeKey := strings.TrimPrefix(key, ConfigPrefix)
-		parts := strings.Split(relativeKey, "/")
-		if len(parts) > 0 {
-			services[parts[0]] = true
-		}
-	}
-
-	result := make([]string, 0, len(services))
-	for service := range services {
-		result = append(result, service)
-	}
-
-	return result, nil
+	return page.Services, nil
 }
 
 // buildConfigKey 构建配置键
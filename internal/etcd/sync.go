@@ -0,0 +1,322 @@
+package etcd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"reflect"
+	"sort"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// 同步计划的变更类型
+const (
+	SyncActionCreate = "create"
+	SyncActionUpdate = "update"
+	SyncActionDelete = "delete"
+)
+
+// 更新时发生变化的字段
+const (
+	SyncFieldValue       = "value"
+	SyncFieldDescription = "description"
+	SyncFieldEncrypt     = "encrypt"
+)
+
+var (
+	// ErrInvalidSync 声明的服务配置无效
+	ErrInvalidSync = errors.New("invalid sync request")
+	// ErrSyncConflict 同步期间配置被并发修改
+	ErrSyncConflict = errors.New("configs changed while syncing, retry")
+)
+
+// SyncConfig 声明的配置项
+type SyncConfig struct {
+	Value       interface{}
+	Description string
+	Encrypt     bool
+	// CreatedAt、UpdatedAt 写入时使用的时间戳, 为0时新建使用当前时间, 更新保留创建时间
+	CreatedAt int64
+	UpdatedAt int64
+}
+
+// SyncService 声明的服务配置, 同步只涉及声明的服务
+type SyncService struct {
+	Name    string
+	Configs map[string]SyncConfig
+	// Unmanaged 本地管理的键, 支持通配符(语法同 path.Match), 同步时忽略
+	Unmanaged []string
+}
+
+// SyncChange 同步计划中的一个变更
+type SyncChange struct {
+	Action      string `json:"action"`
+	ServiceName string `json:"service_name"`
+	Key         string `json:"key"`
+	// Fields 更新时发生变化的字段
+	Fields []string `json:"fields,omitempty"`
+	// Config 同步后的配置, 删除时为空
+	Config *ConfigItem `json:"config,omitempty"`
+	// Current 当前的配置, 新建时为空
+	Current *ConfigItem `json:"current,omitempty"`
+
+	modRevision int64
+}
+
+// SyncPlan 同步计划, 执行后为实际的同步结果
+type SyncPlan struct {
+	// Changes 按服务名与配置键排序
+	Changes []SyncChange `json:"changes"`
+	// Extra 存储中有但文件中没有声明的键(service/key), 未指定 prune 时不删除
+	Extra []string `json:"extra,omitempty"`
+	// Revision 计划所基于的版本号, 执行后为最后一次写入的版本号
+	Revision int64 `json:"revision"`
+	// Applied 计划已写入
+	Applied bool `json:"applied"`
+}
+
+// Drift 存储与声明不一致, prune 为true时未声明的键也视为不一致
+func (p *SyncPlan) Drift(prune bool) bool {
+	return len(p.Changes) > 0 || (prune && len(p.Extra) > 0)
+}
+
+// services 计划中有变更的服务, 按服务名排序
+func (p *SyncPlan) services() []string {
+	var names []string
+	for _, change := range p.Changes {
+		if len(names) == 0 || names[len(names)-1] != change.ServiceName {
+			names = append(names, change.ServiceName)
+		}
+	}
+	return names
+}
+
+// Masked 返回敏感配置的值被隐藏的副本
+func (p *SyncPlan) Masked() *SyncPlan {
+	result := *p
+	result.Changes = make([]SyncChange, len(p.Changes))
+	for i, change := range p.Changes {
+		change.Config = maskItem(change.Config)
+		change.Current = maskItem(change.Current)
+		result.Changes[i] = change
+	}
+	return &result
+}
+
+// maskItem 敏感配置的值显示为 MaskedValue
+func maskItem(item *ConfigItem) *ConfigItem {
+	if item == nil || !item.Encrypt {
+		return item
+	}
+	masked := *item
+	masked.Value = MaskedValue
+	return &masked
+}
+
+// PlanSync 比较声明的服务配置与存储中的配置, 生成新建、更新与删除的计划。
+// 未声明的键只有 prune 为true时才删除, 本地管理的键不参与比较
+func (s *ConfigService) PlanSync(ctx context.Context, services []SyncService, prune bool) (*SyncPlan, error) {
+	desired, err := syncDesired(services)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &SyncPlan{Changes: []SyncChange{}}
+	current := make(map[string]map[string]*SyncChange, len(services))
+	names := make([]string, 0, len(services))
+	for _, service := range services {
+		names = append(names, service.Name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		// 所有服务在同一版本下读取
+		opts := []clientv3.OpOption{clientv3.WithPrefix()}
+		if plan.Revision > 0 {
+			opts = append(opts, clientv3.WithRev(plan.Revision))
+		}
+		resp, err := s.client.GetWithOptions(ctx, s.buildServicePrefix(name), opts...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get configs: %w", err)
+		}
+		if plan.Revision == 0 {
+			plan.Revision = resp.Header.Revision
+		}
+		current[name] = make(map[string]*SyncChange, len(resp.Kvs))
+		for _, kv := range resp.Kvs {
+			if item := s.unmarshalItem(kv.Key, kv.Value); item != nil {
+				current[name][item.Key] = &SyncChange{Current: item, modRevision: kv.ModRevision}
+			}
+		}
+	}
+
+	for _, service := range services {
+		live := current[service.Name]
+		for key, cfg := range desired[service.Name] {
+			if unmanaged(service.Unmanaged, key) {
+				continue
+			}
+			item := &ConfigItem{
+				Key:         key,
+				Value:       cfg.Value,
+				ServiceName: service.Name,
+				Description: cfg.Description,
+				Encrypt:     cfg.Encrypt,
+				CreatedAt:   cfg.CreatedAt,
+				UpdatedAt:   cfg.UpdatedAt,
+			}
+			existing, ok := live[key]
+			if !ok {
+				plan.Changes = append(plan.Changes, SyncChange{
+					Action: SyncActionCreate, ServiceName: service.Name, Key: key, Config: item,
+				})
+				continue
+			}
+
+			var fields []string
+			if !reflect.DeepEqual(existing.Current.Value, cfg.Value) {
+				fields = append(fields, SyncFieldValue)
+			}
+			if existing.Current.Description != cfg.Description {
+				fields = append(fields, SyncFieldDescription)
+			}
+			if existing.Current.Encrypt != cfg.Encrypt {
+				fields = append(fields, SyncFieldEncrypt)
+			}
+			if len(fields) == 0 {
+				continue
+			}
+			if item.CreatedAt == 0 {
+				item.CreatedAt = existing.Current.CreatedAt
+			}
+			plan.Changes = append(plan.Changes, SyncChange{
+				Action: SyncActionUpdate, ServiceName: service.Name, Key: key, Fields: fields,
+				Config: item, Current: existing.Current, modRevision: existing.modRevision,
+			})
+		}
+
+		for key, existing := range live {
+			if _, ok := desired[service.Name][key]; ok || unmanaged(service.Unmanaged, key) {
+				continue
+			}
+			if !prune {
+				plan.Extra = append(plan.Extra, service.Name+"/"+key)
+				continue
+			}
+			plan.Changes = append(plan.Changes, SyncChange{
+				Action: SyncActionDelete, ServiceName: service.Name, Key: key,
+				Current: existing.Current, modRevision: existing.modRevision,
+			})
+		}
+	}
+
+	sort.Slice(plan.Changes, func(i, j int) bool {
+		if plan.Changes[i].ServiceName != plan.Changes[j].ServiceName {
+			return plan.Changes[i].ServiceName < plan.Changes[j].ServiceName
+		}
+		return plan.Changes[i].Key < plan.Changes[j].Key
+	})
+	sort.Strings(plan.Extra)
+	return plan, nil
+}
+
+// ApplySync 生成同步计划并分批在事务中写入, 每个变更要求对应的键在计划之后未被修改,
+// 否则返回 ErrSyncConflict, 此前已提交的批次不回滚, 重新同步即可。
+// 计划修改受保护的服务时返回 ErrProtected, 不写入任何变更
+func (s *ConfigService) ApplySync(ctx context.Context, services []SyncService, prune bool) (*SyncPlan, error) {
+	if s.Degraded() {
+		return nil, ErrReadOnly
+	}
+	plan, err := s.PlanSync(ctx, services, prune)
+	if err != nil {
+		return nil, err
+	}
+	guards, err := s.checkProtected(ctx, plan.services()...)
+	if err != nil {
+		return nil, err
+	}
+
+	// 每个批次同时要求涉及的服务元数据未被修改, 期间服务被设为受保护时返回 ErrSyncConflict
+	batch := s.newTxnBatch(ctx, ErrSyncConflict)
+	batch.guards = guards
+	now := getCurrentTimestamp()
+	for _, change := range plan.Changes {
+		configKey := s.buildConfigKey(change.ServiceName, change.Key)
+		op := clientv3.OpDelete(configKey)
+		if change.Action != SyncActionDelete {
+			if change.Config.UpdatedAt == 0 {
+				change.Config.UpdatedAt = now
+			}
+			if change.Config.CreatedAt == 0 {
+				change.Config.CreatedAt = now
+			}
+			data, err := json.Marshal(change.Config)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal config item: %w", err)
+			}
+			op = clientv3.OpPut(configKey, string(data))
+		}
+		// 新建时键的修改版本为0, 即要求键仍不存在
+		if err := batch.addService(change.ServiceName, clientv3.Compare(clientv3.ModRevision(configKey), "=", change.modRevision), op); err != nil {
+			return nil, err
+		}
+	}
+	if err := batch.flush(); err != nil {
+		return nil, err
+	}
+	if batch.revision > 0 {
+		plan.Revision = batch.revision
+	}
+	plan.Applied = true
+	return plan, nil
+}
+
+// syncDesired 校验声明的服务配置, 将配置值转换为与存储中相同的JSON解码形式以便比较
+func syncDesired(services []SyncService) (map[string]map[string]SyncConfig, error) {
+	desired := make(map[string]map[string]SyncConfig, len(services))
+	for _, service := range services {
+		if err := validateServiceName(service.Name); err != nil {
+			return nil, err
+		}
+		if _, ok := desired[service.Name]; ok {
+			return nil, fmt.Errorf("%w: service %q declared more than once", ErrInvalidSync, service.Name)
+		}
+		for _, pattern := range service.Unmanaged {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return nil, fmt.Errorf("%w: invalid unmanaged pattern %q", ErrInvalidSync, pattern)
+			}
+		}
+
+		configs := make(map[string]SyncConfig, len(service.Configs))
+		for key, cfg := range service.Configs {
+			if key == "" {
+				return nil, fmt.Errorf("%w: empty key in service %q", ErrInvalidSync, service.Name)
+			}
+			data, err := json.Marshal(cfg.Value)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %s/%s: %v", ErrInvalidSync, service.Name, key, err)
+			}
+			var value interface{}
+			if err := json.Unmarshal(data, &value); err != nil {
+				return nil, fmt.Errorf("%w: %s/%s: %v", ErrInvalidSync, service.Name, key, err)
+			}
+			cfg.Value = value
+			configs[key] = cfg
+		}
+		desired[service.Name] = configs
+	}
+	return desired, nil
+}
+
+// unmanaged 判断键是否由本地管理
+func unmanaged(patterns []string, key string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, key); ok {
+			return true
+		}
+	}
+	return false
+}
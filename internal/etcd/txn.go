@@ -0,0 +1,72 @@
+package etcd
+
+import (
+	"context"
+	"fmt"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// txnBatchOps 每个事务包含的写入数, 低于etcd默认的单事务操作数上限
+const txnBatchOps = 64
+
+// txnBatch 分批提交带前置条件的写入, 每批在一个事务中原子执行。
+// 某一批的前置条件不满足时返回 conflict, 之前已提交的批次不回滚
+type txnBatch struct {
+	service  *ConfigService
+	ctx      context.Context
+	conflict error
+	cmps     []clientv3.Cmp
+	ops      []clientv3.Op
+	// guards 按服务名的额外前置条件, 只加入包含该服务写入的批次
+	guards  map[string]clientv3.Cmp
+	guarded map[string]bool
+	// revision 最后一次提交的版本号
+	revision int64
+}
+
+// newTxnBatch 创建分批事务, conflict 为前置条件不满足时返回的错误
+func (s *ConfigService) newTxnBatch(ctx context.Context, conflict error) *txnBatch {
+	return &txnBatch{service: s, ctx: ctx, conflict: conflict}
+}
+
+// add 添加一个写入, 达到批次大小时提交
+func (b *txnBatch) add(cmp clientv3.Cmp, op clientv3.Op) error {
+	b.cmps = append(b.cmps, cmp)
+	b.ops = append(b.ops, op)
+	if len(b.ops) < txnBatchOps {
+		return nil
+	}
+	return b.flush()
+}
+
+// addService 添加服务的一个写入, 服务有额外的前置条件时一并加入当前批次
+func (b *txnBatch) addService(serviceName string, cmp clientv3.Cmp, op clientv3.Op) error {
+	if guard, ok := b.guards[serviceName]; ok && !b.guarded[serviceName] {
+		if b.guarded == nil {
+			b.guarded = make(map[string]bool)
+		}
+		b.guarded[serviceName] = true
+		b.cmps = append(b.cmps, guard)
+	}
+	return b.add(cmp, op)
+}
+
+// flush 提交尚未提交的写入
+func (b *txnBatch) flush() error {
+	if len(b.ops) == 0 {
+		return nil
+	}
+	resp, err := b.service.client.Txn(b.ctx).If(b.cmps...).Then(b.ops...).Commit()
+	if err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	if !resp.Succeeded {
+		return b.conflict
+	}
+	b.service.cache.wrote(resp.Header.Revision)
+	b.revision = resp.Header.Revision
+	b.cmps, b.ops = b.cmps[:0], b.ops[:0]
+	b.guarded = nil
+	return nil
+}
@@ -0,0 +1,43 @@
+package etcd
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+func TestTxnBatchAddServiceGuards(t *testing.T) {
+	guard := func(name string) clientv3.Cmp {
+		return clientv3.Compare(clientv3.ModRevision(ServiceMetaPrefix+name), "=", 0)
+	}
+	batch := (&ConfigService{}).newTxnBatch(context.Background(), ErrSyncConflict)
+	batch.guards = map[string]clientv3.Cmp{"Palace": guard("Palace"), "Heimdallr": guard("Heimdallr")}
+
+	writes := []string{"Palace", "Palace", "Heimdallr", "Palace", "Unguarded"}
+	for i, service := range writes {
+		key := ConfigPrefix + service + "/" + string(rune('a'+i))
+		cmp := clientv3.Compare(clientv3.ModRevision(key), "=", 0)
+		if err := batch.addService(service, cmp, clientv3.OpPut(key, "{}")); err != nil {
+			t.Fatalf("addService(%s) error = %v", service, err)
+		}
+	}
+
+	if len(batch.ops) != len(writes) {
+		t.Errorf("batch has %d ops, want %d", len(batch.ops), len(writes))
+	}
+	// 每个有前置条件的服务在批次中只加入一次
+	if want := len(writes) + 2; len(batch.cmps) != want {
+		t.Errorf("batch has %d cmps, want %d", len(batch.cmps), want)
+	}
+	guarded := map[string]bool{}
+	for _, cmp := range batch.cmps {
+		if name, ok := strings.CutPrefix(string(cmp.Key), ServiceMetaPrefix); ok {
+			guarded[name] = true
+		}
+	}
+	if !guarded["Palace"] || !guarded["Heimdallr"] || guarded["Unguarded"] {
+		t.Errorf("guarded services = %v, want Palace and Heimdallr", guarded)
+	}
+}
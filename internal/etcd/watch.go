@@ -0,0 +1,278 @@
+package etcd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.uber.org/zap"
+)
+
+const (
+	// EventTypePut 新增或更新事件
+	EventTypePut = "PUT"
+	// EventTypeDelete 删除事件
+	EventTypeDelete = "DELETE"
+	// EventTypeProgress 进度通知, 仅携带版本号
+	EventTypeProgress = "PROGRESS"
+	// EventTypeSnapshot 快照中的配置项
+	EventTypeSnapshot = "SNAPSHOT"
+	// EventTypeSnapshotEnd 快照结束, 携带快照的版本号
+	EventTypeSnapshotEnd = "SNAPSHOT_END"
+)
+
+// ConfigEvent 配置变更事件
+type ConfigEvent struct {
+	Type        string      `json:"type"`
+	ServiceName string      `json:"service_name"`
+	Key         string      `json:"key"`
+	Item        *ConfigItem `json:"config,omitempty"`      // DELETE事件为nil
+	PrevItem    *ConfigItem `json:"prev_config,omitempty"` // 新建时为nil, 删除时为删除前的值
+	Revision    int64       `json:"revision"`
+	// valueSize etcd中存储的值的字节数, 供缓存统计使用
+	valueSize int
+}
+
+// WatchOptions 监听选项
+type WatchOptions struct {
+	// StartRevision 大于0时从该版本开始(包含)重放历史事件
+	StartRevision int64
+	// ProgressInterval 大于0时定期请求进度通知
+	ProgressInterval time.Duration
+}
+
+// WatchResponse 监听响应, 同一响应中的事件按版本号递增排列
+type WatchResponse struct {
+	Events []*ConfigEvent
+	// Revision 响应对应的etcd版本号
+	Revision int64
+	// Progress 为true时表示进度通知, Revision之前的事件均已送达
+	Progress bool
+	// CompactRevision 请求的起始版本已被压缩时, 返回当前的压缩版本
+	CompactRevision int64
+	Err             error
+}
+
+// WatchConfigs 监听服务配置的变化, key为空时监听整个服务, serviceName为空时监听所有服务。
+// 事件携带变更前的值, ctx结束或监听出错后channel关闭, 出错时最后一个响应的Err不为空。
+func (s *ConfigService) WatchConfigs(ctx context.Context, serviceName, key string, opts WatchOptions) <-chan *WatchResponse {
+	switch {
+	case serviceName == "":
+		return s.WatchAll(ctx, opts)
+	case key != "":
+		return s.watch(ctx, s.buildConfigKey(serviceName, key), false, opts)
+	default:
+		return s.watch(ctx, s.buildServicePrefix(serviceName), true, opts)
+	}
+}
+
+// WatchAll 监听所有服务配置的变化
+func (s *ConfigService) WatchAll(ctx context.Context, opts WatchOptions) <-chan *WatchResponse {
+	return s.watch(ctx, ConfigPrefix, true, opts)
+}
+
+// CurrentRevision 获取当前的版本号
+func (s *ConfigService) CurrentRevision(ctx context.Context) (int64, error) {
+	revision, err := s.client.Revision(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get current revision: %w", err)
+	}
+	return revision, nil
+}
+
+// Snapshot 获取服务配置在同一版本下的快照, key为空时获取整个服务, serviceName为空时获取所有服务。
+// 返回的配置项按键排序, 从返回的版本号加一开始监听即可获得无遗漏的变更。
+// 降级期间从缓存中失效的数据获取快照
+func (s *ConfigService) Snapshot(ctx context.Context, serviceName, key string) ([]*ConfigItem, int64, error) {
+	if s.Degraded() {
+		items, revision, ok := s.cache.items(serviceName, key, true)
+		if !ok {
+			return nil, 0, ErrUnavailable
+		}
+		return items, revision, nil
+	}
+
+	var (
+		resp *clientv3.GetResponse
+		err  error
+	)
+	switch {
+	case serviceName == "":
+		resp, err = s.client.GetWithOptions(ctx, ConfigPrefix,
+			clientv3.WithPrefix(), clientv3.WithSort(clientv3.SortByKey, clientv3.SortAscend))
+	case key != "":
+		resp, err = s.client.GetWithOptions(ctx, s.buildConfigKey(serviceName, key))
+	default:
+		resp, err = s.client.GetWithOptions(ctx, s.buildServicePrefix(serviceName),
+			clientv3.WithPrefix(), clientv3.WithSort(clientv3.SortByKey, clientv3.SortAscend))
+	}
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get config snapshot: %w", err)
+	}
+
+	items := make([]*ConfigItem, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		if _, _, ok := parseConfigKey(string(kv.Key)); !ok {
+			continue
+		}
+		if item := s.unmarshalItem(kv.Key, kv.Value); item != nil {
+			items = append(items, item)
+		}
+	}
+
+	return items, resp.Header.Revision, nil
+}
+
+// KeyRevision 配置键的最后修改版本
+type KeyRevision struct {
+	ServiceName string
+	Key         string
+	Revision    int64
+}
+
+// KeyRevisions 获取所有配置键的最后修改版本, 只读取键不读取值
+func (s *ConfigService) KeyRevisions(ctx context.Context) ([]KeyRevision, int64, error) {
+	resp, err := s.client.GetWithOptions(ctx, ConfigPrefix, clientv3.WithPrefix(), clientv3.WithKeysOnly())
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get key revisions: %w", err)
+	}
+
+	result := make([]KeyRevision, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		serviceName, key, ok := parseConfigKey(string(kv.Key))
+		if !ok {
+			continue
+		}
+		result = append(result, KeyRevision{ServiceName: serviceName, Key: key, Revision: kv.ModRevision})
+	}
+	return result, resp.Header.Revision, nil
+}
+
+// watch 监听键或前缀的变化并转换为配置变更事件
+func (s *ConfigService) watch(ctx context.Context, watchKey string, prefix bool, opts WatchOptions) <-chan *WatchResponse {
+	watchOpts := []clientv3.OpOption{clientv3.WithPrevKV(), clientv3.WithProgressNotify()}
+	if prefix {
+		watchOpts = append(watchOpts, clientv3.WithPrefix())
+	}
+	if opts.StartRevision > 0 {
+		watchOpts = append(watchOpts, clientv3.WithRev(opts.StartRevision))
+	}
+
+	// 进度请求需要使用与监听相同的ctx
+	watchCtx := clientv3.WithRequireLeader(ctx)
+	watchChan := s.client.WatchWithOptions(watchCtx, watchKey, watchOpts...)
+
+	out := make(chan *WatchResponse)
+	go func() {
+		defer close(out)
+
+		var progress <-chan time.Time
+		if opts.ProgressInterval > 0 {
+			ticker := time.NewTicker(opts.ProgressInterval)
+			defer ticker.Stop()
+			progress = ticker.C
+		}
+
+		for {
+			var watchResp clientv3.WatchResponse
+			select {
+			case <-ctx.Done():
+				return
+			case <-progress:
+				if err := s.client.RequestProgress(watchCtx); err != nil {
+					s.logger.Warn("Failed to request watch progress", zap.Error(err))
+				}
+				continue
+			case resp, ok := <-watchChan:
+				if !ok {
+					return
+				}
+				watchResp = resp
+			}
+
+			resp := &WatchResponse{
+				Revision:        watchResp.Header.Revision,
+				Progress:        watchResp.IsProgressNotify(),
+				CompactRevision: watchResp.CompactRevision,
+				Err:             watchResp.Err(),
+			}
+			for _, event := range watchResp.Events {
+				if configEvent := s.toConfigEvent(event); configEvent != nil {
+					resp.Events = append(resp.Events, configEvent)
+				}
+			}
+			if len(resp.Events) == 0 && !resp.Progress && resp.Err == nil {
+				continue
+			}
+
+			select {
+			case out <- resp:
+			case <-ctx.Done():
+				return
+			}
+			if resp.Err != nil {
+				return
+			}
+		}
+	}()
+
+	return out
+}
+
+// toConfigEvent 将etcd事件转换为配置变更事件
+func (s *ConfigService) toConfigEvent(event *clientv3.Event) *ConfigEvent {
+	serviceName, key, ok := parseConfigKey(string(event.Kv.Key))
+	if !ok {
+		return nil
+	}
+
+	configEvent := &ConfigEvent{
+		Type:        EventTypePut,
+		ServiceName: serviceName,
+		Key:         key,
+		Revision:    event.Kv.ModRevision,
+		valueSize:   len(event.Kv.Value),
+	}
+	if event.PrevKv != nil {
+		configEvent.PrevItem = s.unmarshalItem(event.PrevKv.Key, event.PrevKv.Value)
+	}
+	if event.Type == clientv3.EventTypeDelete {
+		configEvent.Type = EventTypeDelete
+		return configEvent
+	}
+
+	configEvent.Item = s.unmarshalItem(event.Kv.Key, event.Kv.Value)
+	if configEvent.Item == nil {
+		return nil
+	}
+
+	return configEvent
+}
+
+// unmarshalItem 解析etcd中存储的配置项, 失败时返回nil
+func (s *ConfigService) unmarshalItem(key, value []byte) *ConfigItem {
+	var configItem ConfigItem
+	if err := json.Unmarshal(value, &configItem); err != nil {
+		s.logger.Warn("Failed to unmarshal config item",
+			zap.String("key", string(key)),
+			zap.Error(err))
+		return nil
+	}
+	return &configItem
+}
+
+// parseConfigKey 从完整的etcd键中解析服务名与配置键
+func parseConfigKey(fullKey string) (serviceName, key string, ok bool) {
+	relativeKey := strings.TrimPrefix(fullKey, ConfigPrefix)
+	if relativeKey == fullKey {
+		return "", "", false
+	}
+	serviceName, key, ok = strings.Cut(relativeKey, "/")
+	if !ok || serviceName == "" || key == "" {
+		return "", "", false
+	}
+	return serviceName, key, true
+}
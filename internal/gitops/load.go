@@ -0,0 +1,146 @@
+// Package gitops 从目录中的服务配置文件读取声明的配置, 并定期与存储同步
+package gitops
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"nidavellir/internal/etcd"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// ErrInvalidFile 服务配置文件格式错误
+var ErrInvalidFile = errors.New("invalid service config file")
+
+// serviceFile 服务配置文件
+//
+//	service = "Palace"        # 可选, 默认为去掉扩展名的文件名
+//	unmanaged = ["Debug*"]    # 可选, 本地管理的键, 同步时忽略
+//
+//	[configs]
+//	Host = "127.0.0.1"
+//	Port = 22222
+//
+//	[configs.DBPassword]
+//	value = "secret"
+//	description = "数据库密码"
+//	encrypt = true
+type serviceFile struct {
+	Service   string                 `toml:"service" yaml:"service"`
+	Unmanaged []string               `toml:"unmanaged" yaml:"unmanaged"`
+	Configs   map[string]interface{} `toml:"configs" yaml:"configs"`
+}
+
+// Load 读取目录下的服务配置文件(*.toml、*.yaml、*.yml, 不含子目录与隐藏文件), 按服务名排序
+func Load(dir string) ([]etcd.SyncService, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		services []etcd.SyncService
+		files    = make(map[string]string)
+	)
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || strings.HasPrefix(name, ".") {
+			continue
+		}
+		ext := filepath.Ext(name)
+		switch ext {
+		case ".toml", ".yaml", ".yml":
+		default:
+			continue
+		}
+
+		path := filepath.Join(dir, name)
+		service, err := LoadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if prev, ok := files[service.Name]; ok {
+			return nil, fmt.Errorf("%w: service %q declared in both %s and %s", ErrInvalidFile, service.Name, prev, path)
+		}
+		files[service.Name] = path
+		services = append(services, service)
+	}
+
+	sort.Slice(services, func(i, j int) bool {
+		return services[i].Name < services[j].Name
+	})
+	return services, nil
+}
+
+// LoadFile 读取一个服务配置文件, 按扩展名解析为 TOML 或 YAML
+func LoadFile(path string) (etcd.SyncService, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return etcd.SyncService{}, err
+	}
+
+	var file serviceFile
+	switch filepath.Ext(path) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &file)
+	default:
+		err = toml.Unmarshal(data, &file)
+	}
+	if err != nil {
+		return etcd.SyncService{}, fmt.Errorf("%w: %s: %v", ErrInvalidFile, path, err)
+	}
+
+	service := etcd.SyncService{
+		Name:      file.Service,
+		Configs:   make(map[string]etcd.SyncConfig, len(file.Configs)),
+		Unmanaged: file.Unmanaged,
+	}
+	if service.Name == "" {
+		service.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	for key, raw := range file.Configs {
+		cfg, err := parseConfig(raw)
+		if err != nil {
+			return etcd.SyncService{}, fmt.Errorf("%w: %s: %s: %v", ErrInvalidFile, path, key, err)
+		}
+		service.Configs[key] = cfg
+	}
+	return service, nil
+}
+
+// parseConfig 解析配置项, 只包含 value、description、encrypt 且包含 value 的表为完整形式, 其他值直接作为配置值
+func parseConfig(raw interface{}) (etcd.SyncConfig, error) {
+	table, ok := raw.(map[string]interface{})
+	if !ok {
+		return etcd.SyncConfig{Value: raw}, nil
+	}
+	if _, ok := table["value"]; !ok {
+		return etcd.SyncConfig{Value: raw}, nil
+	}
+	for field := range table {
+		switch field {
+		case "value", "description", "encrypt":
+		default:
+			return etcd.SyncConfig{Value: raw}, nil
+		}
+	}
+
+	cfg := etcd.SyncConfig{Value: table["value"]}
+	if description, ok := table["description"]; ok {
+		if cfg.Description, ok = description.(string); !ok {
+			return etcd.SyncConfig{}, errors.New("description must be a string")
+		}
+	}
+	if encrypt, ok := table["encrypt"]; ok {
+		if cfg.Encrypt, ok = encrypt.(bool); !ok {
+			return etcd.SyncConfig{}, errors.New("encrypt must be a boolean")
+		}
+	}
+	return cfg, nil
+}
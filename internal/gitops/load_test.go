@@ -0,0 +1,162 @@
+package gitops
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"nidavellir/internal/etcd"
+)
+
+func TestParseConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     interface{}
+		want    etcd.SyncConfig
+		wantErr bool
+	}{
+		{name: "string", raw: "127.0.0.1", want: etcd.SyncConfig{Value: "127.0.0.1"}},
+		{name: "integer", raw: int64(22222), want: etcd.SyncConfig{Value: int64(22222)}},
+		{name: "array", raw: []interface{}{"a", "b"}, want: etcd.SyncConfig{Value: []interface{}{"a", "b"}}},
+		{
+			name: "value only",
+			raw:  map[string]interface{}{"value": "secret"},
+			want: etcd.SyncConfig{Value: "secret"},
+		},
+		{
+			name: "full form",
+			raw:  map[string]interface{}{"value": "secret", "description": "数据库密码", "encrypt": true},
+			want: etcd.SyncConfig{Value: "secret", Description: "数据库密码", Encrypt: true},
+		},
+		{
+			name: "table value",
+			raw:  map[string]interface{}{"value": map[string]interface{}{"a": int64(1)}, "encrypt": false},
+			want: etcd.SyncConfig{Value: map[string]interface{}{"a": int64(1)}},
+		},
+		{
+			name: "table without value",
+			raw:  map[string]interface{}{"description": "x", "encrypt": true},
+			want: etcd.SyncConfig{Value: map[string]interface{}{"description": "x", "encrypt": true}},
+		},
+		{
+			name: "table with other fields",
+			raw:  map[string]interface{}{"value": "v", "unit": "ms"},
+			want: etcd.SyncConfig{Value: map[string]interface{}{"value": "v", "unit": "ms"}},
+		},
+		{name: "empty table", raw: map[string]interface{}{}, want: etcd.SyncConfig{Value: map[string]interface{}{}}},
+		{name: "nil", raw: nil, want: etcd.SyncConfig{}},
+		{name: "description not string", raw: map[string]interface{}{"value": "v", "description": int64(1)}, wantErr: true},
+		{name: "encrypt not bool", raw: map[string]interface{}{"value": "v", "encrypt": "yes"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseConfig(tt.raw)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("parseConfig() = %+v, want error", got)
+				}
+				return
+			}
+			if err != nil || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseConfig() = %+v, %v, want %+v", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"Palace.toml": `
+unmanaged = ["Debug*"]
+
+[configs]
+Host = "127.0.0.1"
+Port = 22222
+
+[configs.DBPassword]
+value = "secret"
+description = "数据库密码"
+encrypt = true
+`,
+		"heimdallr.yaml": `
+service: Heimdallr
+configs:
+  Talker: /var/run/OctopusTwig.sock
+  Jobs:
+    value: [a, b]
+    description: 任务
+`,
+		"README.md":    "ignored",
+		".hidden.toml": "not = [valid",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Mkdir(filepath.Join(dir, "sub.toml"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	services, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	want := []etcd.SyncService{
+		{
+			Name: "Heimdallr",
+			Configs: map[string]etcd.SyncConfig{
+				"Talker": {Value: "/var/run/OctopusTwig.sock"},
+				"Jobs":   {Value: []interface{}{"a", "b"}, Description: "任务"},
+			},
+		},
+		{
+			Name: "Palace",
+			Configs: map[string]etcd.SyncConfig{
+				"Host":       {Value: "127.0.0.1"},
+				"Port":       {Value: int64(22222)},
+				"DBPassword": {Value: "secret", Description: "数据库密码", Encrypt: true},
+			},
+			Unmanaged: []string{"Debug*"},
+		},
+	}
+	if !reflect.DeepEqual(services, want) {
+		t.Errorf("Load() = %+v, want %+v", services, want)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+	}{
+		{name: "invalid toml", files: map[string]string{"Palace.toml": "[configs\n"}},
+		{name: "invalid yaml", files: map[string]string{"Palace.yaml": "configs: [\n"}},
+		{name: "invalid field", files: map[string]string{"Palace.toml": "[configs.Port]\nvalue = 1\nencrypt = \"yes\"\n"}},
+		{
+			name: "duplicate service",
+			files: map[string]string{
+				"Palace.toml": "[configs]\nPort = 1\n",
+				"other.yml":   "service: Palace\n",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, content := range tt.files {
+				if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if _, err := Load(dir); !errors.Is(err, ErrInvalidFile) {
+				t.Errorf("Load() error = %v, want ErrInvalidFile", err)
+			}
+		})
+	}
+}
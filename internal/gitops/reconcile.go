@@ -0,0 +1,76 @@
+package gitops
+
+import (
+	"context"
+	"time"
+
+	"nidavellir/internal/config"
+	"nidavellir/internal/etcd"
+
+	"go.uber.org/zap"
+)
+
+// Reconciler 定期将目录中声明的配置同步到存储
+type Reconciler struct {
+	service *etcd.ConfigService
+	cfg     config.SyncConfig
+	logger  *zap.Logger
+}
+
+// NewReconciler 创建同步器
+func NewReconciler(service *etcd.ConfigService, cfg config.SyncConfig, logger *zap.Logger) *Reconciler {
+	return &Reconciler{service: service, cfg: cfg, logger: logger}
+}
+
+// Run 每隔 interval 读取目录并同步, dry_run 时只记录不一致的配置, 直到ctx结束
+func (r *Reconciler) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		r.reconcile(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// reconcile 执行一次同步
+func (r *Reconciler) reconcile(ctx context.Context) {
+	services, err := Load(r.cfg.Dir)
+	if err != nil {
+		r.logger.Error("Failed to load sync dir", zap.String("dir", r.cfg.Dir), zap.Error(err))
+		return
+	}
+
+	var plan *etcd.SyncPlan
+	if r.cfg.DryRun || r.service.Degraded() {
+		plan, err = r.service.PlanSync(ctx, services, r.cfg.Prune)
+	} else {
+		plan, err = r.service.ApplySync(ctx, services, r.cfg.Prune)
+	}
+	if err != nil {
+		r.logger.Error("Failed to sync configs", zap.String("dir", r.cfg.Dir), zap.Error(err))
+		return
+	}
+	if !plan.Drift(r.cfg.Prune) {
+		return
+	}
+
+	for _, change := range plan.Changes {
+		r.logger.Info("Sync config",
+			zap.String("action", change.Action),
+			zap.String("service", change.ServiceName),
+			zap.String("key", change.Key),
+			zap.Strings("fields", change.Fields),
+			zap.Bool("applied", plan.Applied))
+	}
+	if len(plan.Extra) > 0 {
+		r.logger.Info("Configs not declared in sync dir", zap.Strings("keys", plan.Extra))
+	}
+	if !plan.Applied {
+		r.logger.Warn("Configs drifted from sync dir", zap.String("dir", r.cfg.Dir), zap.Int("changes", len(plan.Changes)))
+	}
+}
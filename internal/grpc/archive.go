@@ -0,0 +1,93 @@
+package grpc
+
+import (
+	"bytes"
+	"context"
+	"errors"
+
+	grpcConfig "nidavellir/api/proto"
+	"nidavellir/internal/archive"
+	"nidavellir/internal/etcd"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Export 导出所有服务的配置与元数据, 敏感配置的值以归档密钥加密, 未授权的调用方导出的敏感配置不含值
+func (s *Server) Export(ctx context.Context, req *grpcConfig.ExportRequest) (*grpcConfig.ExportResponse, error) {
+	format, err := archive.ParseFormat(req.Format)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	result, err := s.configService.Export(ctx, etcd.ExportOptions{
+		Services:       req.Services,
+		IncludeSecrets: s.authorizer.CanReadSecrets(bearerToken(ctx)),
+	})
+	if err != nil {
+		if errors.Is(err, etcd.ErrInvalidServiceName) {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		s.logger.Error("Failed to export configs", zap.Error(err))
+		return nil, statusError(err, "Failed to export configs")
+	}
+
+	var buf bytes.Buffer
+	if err := archive.Encode(&buf, result, format); err != nil {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+	return &grpcConfig.ExportResponse{Archive: buf.Bytes(), Format: format, Revision: result.Revision}, nil
+}
+
+// Import 导入归档, 需要审批人的令牌, 未授权的调用方看到的敏感配置值被隐藏
+func (s *Server) Import(ctx context.Context, req *grpcConfig.ImportRequest) (*grpcConfig.ImportResponse, error) {
+	if err := s.requireApprover(ctx, "import configs"); err != nil {
+		return nil, err
+	}
+	format := req.Format
+	if format != "" {
+		var err error
+		if format, err = archive.ParseFormat(format); err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+	arc, err := archive.Decode(req.Archive, format)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	result, err := s.configService.Import(ctx, arc, etcd.ImportOptions{
+		Mode:     req.Mode,
+		Services: req.Services,
+		DryRun:   req.DryRun,
+	})
+	switch {
+	case errors.Is(err, etcd.ErrInvalidArchive) || errors.Is(err, etcd.ErrInvalidImport) ||
+		errors.Is(err, etcd.ErrInvalidServiceName):
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, etcd.ErrSyncConflict):
+		return nil, status.Error(codes.Aborted, err.Error())
+	case err != nil:
+		s.logger.Error("Failed to import configs", zap.Error(err))
+		return nil, statusError(err, "Failed to import configs")
+	}
+
+	if !s.authorizer.CanReadSecrets(bearerToken(ctx)) {
+		result = result.Masked()
+	}
+	return toProtoImport(result), nil
+}
+
+// toProtoImport 转换导入计划为protobuf格式
+func toProtoImport(result *etcd.ImportResult) *grpcConfig.ImportResponse {
+	return &grpcConfig.ImportResponse{
+		Changes:  toProtoSyncChanges(result.Changes),
+		Extra:    result.Extra,
+		Revision: result.Revision,
+		Applied:  result.Applied,
+		Mode:     result.Mode,
+		Metadata: result.Metadata,
+		Skipped:  result.Skipped,
+	}
+}
@@ -0,0 +1,90 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+
+	grpcConfig "nidavellir/api/proto"
+	"nidavellir/internal/backup"
+	"nidavellir/internal/etcd"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ListBackups 列出本地备份, 最新的在前, 需要审批人的令牌
+func (s *Server) ListBackups(ctx context.Context, req *grpcConfig.ListBackupsRequest) (*grpcConfig.ListBackupsResponse, error) {
+	if err := s.requireApprover(ctx, "list backups"); err != nil {
+		return nil, err
+	}
+	backups, err := s.backups.List()
+	if err != nil {
+		return nil, s.backupError(err, "Failed to list backups")
+	}
+	resp := &grpcConfig.ListBackupsResponse{Backups: make([]*grpcConfig.BackupInfo, 0, len(backups))}
+	for i := range backups {
+		resp.Backups = append(resp.Backups, toProtoBackup(&backups[i]))
+	}
+	return resp, nil
+}
+
+// CreateBackup 立即创建备份, 需要审批人的令牌
+func (s *Server) CreateBackup(ctx context.Context, req *grpcConfig.CreateBackupRequest) (*grpcConfig.CreateBackupResponse, error) {
+	if err := s.requireApprover(ctx, "create backups"); err != nil {
+		return nil, err
+	}
+	info, err := s.backups.Create(ctx)
+	if err != nil {
+		return nil, s.backupError(err, "Failed to create backup")
+	}
+	return &grpcConfig.CreateBackupResponse{Backup: toProtoBackup(info)}, nil
+}
+
+// RestoreBackup 从备份恢复配置, 需要审批人的令牌, 未确认时只返回计划。
+// 未授权的调用方看到的敏感配置值被隐藏
+func (s *Server) RestoreBackup(ctx context.Context, req *grpcConfig.RestoreBackupRequest) (*grpcConfig.ImportResponse, error) {
+	if err := s.requireApprover(ctx, "restore backups"); err != nil {
+		return nil, err
+	}
+	result, err := s.backups.Restore(ctx, req.Name, etcd.ImportOptions{
+		Mode:     req.Mode,
+		Services: req.Services,
+		DryRun:   req.DryRun || !req.Confirm,
+	})
+	if err != nil {
+		return nil, s.backupError(err, "Failed to restore backup")
+	}
+
+	if !s.authorizer.CanReadSecrets(bearerToken(ctx)) {
+		result = result.Masked()
+	}
+	return toProtoImport(result), nil
+}
+
+// backupError 转换备份相关的错误
+func (s *Server) backupError(err error, message string) error {
+	switch {
+	case errors.Is(err, backup.ErrDisabled) || errors.Is(err, backup.ErrNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, backup.ErrNoKey):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, etcd.ErrInvalidImport) || errors.Is(err, etcd.ErrInvalidServiceName):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, etcd.ErrSyncConflict):
+		return status.Error(codes.Aborted, err.Error())
+	}
+	s.logger.Error(message, zap.Error(err))
+	return statusError(err, message)
+}
+
+// toProtoBackup 转换备份信息为protobuf格式
+func toProtoBackup(info *backup.Info) *grpcConfig.BackupInfo {
+	return &grpcConfig.BackupInfo{
+		Name:      info.Name,
+		Revision:  info.Revision,
+		CreatedAt: info.CreatedAt,
+		Size:      info.Size,
+		Encrypted: info.Encrypted,
+	}
+}
@@ -0,0 +1,108 @@
+package grpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	grpcConfig "nidavellir/api/proto"
+	"nidavellir/internal/archive"
+	"nidavellir/internal/backup"
+	"nidavellir/internal/diff"
+	"nidavellir/internal/etcd"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Diff 比较两侧服务的配置, 未授权的调用方看到的敏感配置值被隐藏
+func (s *Server) Diff(ctx context.Context, req *grpcConfig.DiffRequest) (*grpcConfig.DiffResponse, error) {
+	left, err := parseDiffSide(req.Left, req.LeftArchive)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	right, err := parseDiffSide(req.Right, req.RightArchive)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	result, err := s.differ.Diff(ctx, left, right)
+	switch {
+	case errors.Is(err, diff.ErrInvalidSide) || errors.Is(err, etcd.ErrInvalidRevision) ||
+		errors.Is(err, etcd.ErrInvalidServiceName):
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, backup.ErrDisabled) || errors.Is(err, backup.ErrNotFound) ||
+		errors.Is(err, etcd.ErrDraftNotFound):
+		return nil, status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, diff.ErrEnvironment):
+		return nil, status.Error(codes.Unavailable, err.Error())
+	case err != nil:
+		s.logger.Error("Failed to diff configs", zap.Error(err))
+		return nil, statusError(err, "Failed to diff configs")
+	}
+
+	if !s.authorizer.CanReadSecrets(bearerToken(ctx)) {
+		result = result.Masked()
+	}
+
+	return toProtoDiff(result, req.Unified, req.Context)
+}
+
+// toProtoDiff 转换比较结果为protobuf格式, unified 时同时渲染统一格式的文本,
+// context 为0时使用默认的上下文行数, 小于0时不输出上下文
+func toProtoDiff(result *diff.Result, unified bool, context int32) (*grpcConfig.DiffResponse, error) {
+	resp := &grpcConfig.DiffResponse{
+		Left:    result.Left,
+		Right:   result.Right,
+		Changes: make([]*grpcConfig.DiffChange, 0, len(result.Changes)),
+		Added:   int32(result.Added),
+		Removed: int32(result.Removed),
+		Changed: int32(result.Changed),
+	}
+	for _, change := range result.Changes {
+		resp.Changes = append(resp.Changes, &grpcConfig.DiffChange{
+			Key:    change.Key,
+			Op:     change.Op,
+			Old:    diffValue(change.Old),
+			New:    diffValue(change.New),
+			Secret: change.Secret,
+		})
+	}
+	if unified {
+		lines := max(int(context), 0)
+		if context == 0 {
+			lines = diff.DefaultContext
+		}
+		var buf strings.Builder
+		if err := result.Unified(&buf, lines); err != nil {
+			return nil, status.Error(codes.Internal, "Failed to render diff")
+		}
+		resp.Unified = buf.String()
+	}
+	return resp, nil
+}
+
+// parseDiffSide 解析比较的一侧, file: 命名空间使用随请求上传的归档
+func parseDiffSide(spec string, data []byte) (diff.Side, error) {
+	side, err := diff.ParseSide(spec)
+	if err != nil {
+		return diff.Side{}, err
+	}
+	if side.File != "" && len(data) > 0 {
+		if side.Archive, err = archive.Decode(data, ""); err != nil {
+			return diff.Side{}, err
+		}
+	}
+	return side, nil
+}
+
+// diffValue 差异中的值序列化为JSON文本, 不存在时为空
+func diffValue(value interface{}) string {
+	if value == nil {
+		return ""
+	}
+	data, _ := json.Marshal(value)
+	return string(data)
+}
@@ -0,0 +1,235 @@
+package grpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	grpcConfig "nidavellir/api/proto"
+	"nidavellir/internal/etcd"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// CreateDraft 创建草稿, 需要作者或审批人的令牌, 令牌对应的名称即为作者
+func (s *Server) CreateDraft(ctx context.Context, req *grpcConfig.CreateDraftRequest) (*grpcConfig.DraftResponse, error) {
+	author, err := s.member(ctx, "create drafts")
+	if err != nil {
+		return nil, err
+	}
+	draft, err := s.configService.CreateDraft(ctx, &etcd.Draft{
+		Description:       req.Description,
+		Author:            author,
+		Operations:        fromProtoOperations(req.Operations),
+		RequiredApprovals: s.authorizer.RequiredApprovals(),
+	})
+	if err != nil {
+		return nil, s.draftError(err, "Failed to create draft")
+	}
+	return s.draftResponse(ctx, draft), nil
+}
+
+// GetDraft 获取草稿, diff 时同时返回每个服务当前配置与发布后配置的差异
+func (s *Server) GetDraft(ctx context.Context, req *grpcConfig.GetDraftRequest) (*grpcConfig.GetDraftResponse, error) {
+	draft, err := s.configService.GetDraft(ctx, req.Id)
+	if err != nil {
+		return nil, s.draftError(err, "Failed to get draft")
+	}
+
+	canReadSecrets := s.authorizer.CanReadSecrets(bearerToken(ctx))
+	resp := &grpcConfig.GetDraftResponse{}
+	if req.Diff {
+		results, err := s.differ.DraftDiff(ctx, draft)
+		if err != nil {
+			return nil, s.draftError(err, "Failed to diff draft")
+		}
+		for _, result := range results {
+			if !canReadSecrets {
+				result = result.Masked()
+			}
+			diff, err := toProtoDiff(result, req.Unified, req.Context)
+			if err != nil {
+				return nil, err
+			}
+			resp.Diffs = append(resp.Diffs, diff)
+		}
+	}
+	if !canReadSecrets {
+		draft = draft.Masked()
+	}
+	resp.Draft = toProtoDraft(draft)
+	return resp, nil
+}
+
+// ListDrafts 列出草稿, 最新的在前
+func (s *Server) ListDrafts(ctx context.Context, req *grpcConfig.ListDraftsRequest) (*grpcConfig.ListDraftsResponse, error) {
+	drafts, err := s.configService.ListDrafts(ctx, req.Status)
+	if err != nil {
+		return nil, s.draftError(err, "Failed to list drafts")
+	}
+
+	canReadSecrets := s.authorizer.CanReadSecrets(bearerToken(ctx))
+	resp := &grpcConfig.ListDraftsResponse{Drafts: make([]*grpcConfig.Draft, 0, len(drafts))}
+	for _, draft := range drafts {
+		if !canReadSecrets {
+			draft = draft.Masked()
+		}
+		resp.Drafts = append(resp.Drafts, toProtoDraft(draft))
+	}
+	return resp, nil
+}
+
+// UpdateDraft 修改草稿并以当前配置为基准, 需要作者或审批人的令牌, 已有的审批作废
+func (s *Server) UpdateDraft(ctx context.Context, req *grpcConfig.UpdateDraftRequest) (*grpcConfig.DraftResponse, error) {
+	editor, err := s.member(ctx, "update drafts")
+	if err != nil {
+		return nil, err
+	}
+	draft, err := s.configService.UpdateDraft(ctx, req.Id, editor, req.Description, fromProtoOperations(req.Operations))
+	if err != nil {
+		return nil, s.draftError(err, "Failed to update draft")
+	}
+	return s.draftResponse(ctx, draft), nil
+}
+
+// ApproveDraft 审批草稿, 需要审批人的令牌
+func (s *Server) ApproveDraft(ctx context.Context, req *grpcConfig.DraftActionRequest) (*grpcConfig.DraftResponse, error) {
+	approver, ok := s.authorizer.Approver(bearerToken(ctx))
+	if !ok {
+		return nil, status.Error(codes.PermissionDenied, "an approver token is required to approve drafts")
+	}
+	draft, err := s.configService.ApproveDraft(ctx, req.Id, approver)
+	if err != nil {
+		return nil, s.draftError(err, "Failed to approve draft")
+	}
+	return s.draftResponse(ctx, draft), nil
+}
+
+// PublishDraft 发布草稿, 需要审批人的令牌
+func (s *Server) PublishDraft(ctx context.Context, req *grpcConfig.DraftActionRequest) (*grpcConfig.DraftResponse, error) {
+	publisher, ok := s.authorizer.Approver(bearerToken(ctx))
+	if !ok {
+		return nil, status.Error(codes.PermissionDenied, "an approver token is required to publish drafts")
+	}
+	draft, err := s.configService.PublishDraft(ctx, req.Id, publisher)
+	if err != nil {
+		return nil, s.draftError(err, "Failed to publish draft")
+	}
+	return s.draftResponse(ctx, draft), nil
+}
+
+// AbandonDraft 放弃草稿, 需要作者或审批人的令牌
+func (s *Server) AbandonDraft(ctx context.Context, req *grpcConfig.DraftActionRequest) (*grpcConfig.DraftResponse, error) {
+	by, err := s.member(ctx, "abandon drafts")
+	if err != nil {
+		return nil, err
+	}
+	draft, err := s.configService.AbandonDraft(ctx, req.Id, by)
+	if err != nil {
+		return nil, s.draftError(err, "Failed to abandon draft")
+	}
+	return s.draftResponse(ctx, draft), nil
+}
+
+// requireApprover 要求令牌属于审批人, 否则返回 PermissionDenied
+func (s *Server) requireApprover(ctx context.Context, action string) error {
+	if _, ok := s.authorizer.Approver(bearerToken(ctx)); !ok {
+		return status.Errorf(codes.PermissionDenied, "an approver token is required to %s", action)
+	}
+	return nil
+}
+
+// member 返回令牌对应的作者或审批人的名称, 令牌不属于任何成员时返回 PermissionDenied
+func (s *Server) member(ctx context.Context, action string) (string, error) {
+	name, ok := s.authorizer.Member(bearerToken(ctx))
+	if !ok {
+		return "", status.Errorf(codes.PermissionDenied, "an author or approver token is required to %s", action)
+	}
+	return name, nil
+}
+
+// draftResponse 未授权的调用方看到的敏感配置值被隐藏
+func (s *Server) draftResponse(ctx context.Context, draft *etcd.Draft) *grpcConfig.DraftResponse {
+	if !s.authorizer.CanReadSecrets(bearerToken(ctx)) {
+		draft = draft.Masked()
+	}
+	return &grpcConfig.DraftResponse{Draft: toProtoDraft(draft)}
+}
+
+// draftError 转换草稿相关的错误
+func (s *Server) draftError(err error, message string) error {
+	switch {
+	case errors.Is(err, etcd.ErrInvalidDraft) || errors.Is(err, etcd.ErrInvalidServiceName):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, etcd.ErrDraftNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, etcd.ErrDraftClosed) || errors.Is(err, etcd.ErrDraftNotApproved):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, etcd.ErrDraftConflict):
+		return status.Error(codes.Aborted, err.Error())
+	case errors.Is(err, etcd.ErrSelfApproval):
+		return status.Error(codes.PermissionDenied, err.Error())
+	}
+	s.logger.Error(message, zap.Error(err))
+	return statusError(err, message)
+}
+
+// fromProtoOperations 转换草稿中的操作, value 按 SetConfig 的规则解析
+func fromProtoOperations(operations []*grpcConfig.DraftOperation) []etcd.DraftOperation {
+	result := make([]etcd.DraftOperation, 0, len(operations))
+	for _, op := range operations {
+		var value interface{}
+		if op.Op == etcd.DraftOpSet {
+			if err := json.Unmarshal([]byte(op.Value), &value); err != nil {
+				value = op.Value
+			}
+		}
+		result = append(result, etcd.DraftOperation{
+			Op:          op.Op,
+			ServiceName: op.ServiceName,
+			Key:         op.Key,
+			Value:       value,
+			Description: op.Description,
+			Encrypt:     op.Encrypt,
+		})
+	}
+	return result
+}
+
+// toProtoDraft 转换草稿为protobuf格式, value 序列化为JSON文本
+func toProtoDraft(draft *etcd.Draft) *grpcConfig.Draft {
+	result := &grpcConfig.Draft{
+		Id:                draft.ID,
+		Description:       draft.Description,
+		Author:            draft.Author,
+		Editors:           draft.Editors,
+		Status:            draft.Status,
+		Operations:        make([]*grpcConfig.DraftOperation, 0, len(draft.Operations)),
+		RequiredApprovals: int32(draft.RequiredApprovals),
+		CreatedAt:         draft.CreatedAt,
+		UpdatedAt:         draft.UpdatedAt,
+		ClosedBy:          draft.ClosedBy,
+		ClosedAt:          draft.ClosedAt,
+		Revision:          draft.Revision,
+	}
+	for _, op := range draft.Operations {
+		result.Operations = append(result.Operations, &grpcConfig.DraftOperation{
+			Op:           op.Op,
+			ServiceName:  op.ServiceName,
+			Key:          op.Key,
+			Value:        diffValue(op.Value),
+			Description:  op.Description,
+			Encrypt:      op.Encrypt,
+			BaseRevision: op.BaseRevision,
+		})
+	}
+	for _, approval := range draft.Approvals {
+		result.Approvals = append(result.Approvals, &grpcConfig.DraftApproval{
+			Approver:   approval.Approver,
+			ApprovedAt: approval.ApprovedAt,
+		})
+	}
+	return result
+}
@@ -0,0 +1,145 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+
+	grpcConfig "nidavellir/api/proto"
+	"nidavellir/internal/etcd"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// SetServiceMeta 创建或更新服务元数据
+func (s *Server) SetServiceMeta(ctx context.Context, req *grpcConfig.SetServiceMetaRequest) (*grpcConfig.SetServiceMetaResponse, error) {
+	if req.Metadata == nil || req.Metadata.Name == "" {
+		return nil, status.Error(codes.InvalidArgument, "metadata.name is required")
+	}
+
+	protected, modRevision, err := s.resolveProtected(ctx, req.Metadata.Name, req.Metadata.Protected)
+	if err != nil {
+		return nil, err
+	}
+
+	meta, err := s.configService.SetServiceMeta(ctx, &etcd.ServiceMeta{
+		Name:        req.Metadata.Name,
+		Description: req.Metadata.Description,
+		Owner:       req.Metadata.Owner,
+		Contact:     req.Metadata.Contact,
+		Labels:      req.Metadata.Labels,
+		Protected:   protected,
+	}, modRevision)
+	if errors.Is(err, etcd.ErrInvalidServiceName) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err != nil {
+		s.logger.Error("Failed to set service metadata", zap.Error(err))
+		return nil, statusError(err, "Failed to set service metadata")
+	}
+
+	return &grpcConfig.SetServiceMetaResponse{Metadata: toProtoServiceMeta(meta)}, nil
+}
+
+// GetServiceMeta 获取服务元数据与统计信息
+func (s *Server) GetServiceMeta(ctx context.Context, req *grpcConfig.GetServiceMetaRequest) (*grpcConfig.GetServiceMetaResponse, error) {
+	if req.ServiceName == "" {
+		return nil, status.Error(codes.InvalidArgument, "service_name is required")
+	}
+
+	info, err := s.configService.GetServiceInfo(ctx, req.ServiceName)
+	if errors.Is(err, etcd.ErrInvalidServiceName) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err != nil {
+		s.logger.Error("Failed to get service metadata", zap.Error(err))
+		return nil, statusError(err, "Failed to get service metadata")
+	}
+	if info == nil {
+		return &grpcConfig.GetServiceMetaResponse{Found: false}, nil
+	}
+
+	return &grpcConfig.GetServiceMetaResponse{Service: toProtoServiceInfo(info), Found: true}, nil
+}
+
+// DeleteServiceMeta 删除服务元数据
+func (s *Server) DeleteServiceMeta(ctx context.Context, req *grpcConfig.DeleteServiceMetaRequest) (*grpcConfig.DeleteServiceMetaResponse, error) {
+	if req.ServiceName == "" {
+		return nil, status.Error(codes.InvalidArgument, "service_name is required")
+	}
+
+	// 删除元数据会同时取消保护
+	unprotected := false
+	_, modRevision, err := s.resolveProtected(ctx, req.ServiceName, &unprotected)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.configService.DeleteServiceMeta(ctx, req.ServiceName, modRevision)
+	if errors.Is(err, etcd.ErrInvalidServiceName) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err != nil {
+		s.logger.Error("Failed to delete service metadata", zap.Error(err))
+		return nil, statusError(err, "Failed to delete service metadata")
+	}
+
+	return &grpcConfig.DeleteServiceMetaResponse{
+		Success: true,
+		Message: "Service metadata deleted successfully",
+	}, nil
+}
+
+// resolveProtected 计算写入后的 protected 标记, requested 为nil时保持不变, 修改标记需要审批人的令牌。
+// 同时返回校验时元数据的修改版本号, 写入时要求元数据未被修改
+func (s *Server) resolveProtected(ctx context.Context, serviceName string, requested *bool) (bool, int64, error) {
+	current, modRevision, err := s.configService.GetServiceMeta(ctx, serviceName)
+	if errors.Is(err, etcd.ErrInvalidServiceName) {
+		return false, 0, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err != nil {
+		s.logger.Error("Failed to get service metadata", zap.Error(err))
+		return false, 0, statusError(err, "Failed to get service metadata")
+	}
+	protected := current != nil && current.Protected
+	if requested == nil || *requested == protected {
+		return protected, modRevision, nil
+	}
+	if _, ok := s.authorizer.Approver(bearerToken(ctx)); !ok {
+		return false, 0, status.Error(codes.PermissionDenied, "an approver token is required to change the protected flag")
+	}
+	return *requested, modRevision, nil
+}
+
+// toProtoServiceMeta 转换服务元数据为protobuf格式
+func toProtoServiceMeta(meta *etcd.ServiceMeta) *grpcConfig.ServiceMeta {
+	if meta == nil {
+		return nil
+	}
+	return &grpcConfig.ServiceMeta{
+		Name:        meta.Name,
+		Description: meta.Description,
+		Owner:       meta.Owner,
+		Contact:     meta.Contact,
+		Labels:      meta.Labels,
+		Seeded:      meta.Seeded,
+		Protected:   &meta.Protected,
+		CreatedAt:   meta.CreatedAt,
+		UpdatedAt:   meta.UpdatedAt,
+	}
+}
+
+// toProtoServiceInfo 转换服务信息为protobuf格式
+func toProtoServiceInfo(info *etcd.ServiceInfo) *grpcConfig.ServiceInfo {
+	return &grpcConfig.ServiceInfo{
+		Name:     info.Name,
+		Metadata: toProtoServiceMeta(info.Meta),
+		Stats: &grpcConfig.ServiceStats{
+			Keys:         int64(info.Stats.Keys),
+			ValueBytes:   info.Stats.ValueBytes,
+			LastModified: info.Stats.LastModified,
+			LastRevision: info.Stats.LastRevision,
+		},
+	}
+}
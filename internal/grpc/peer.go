@@ -0,0 +1,56 @@
+package grpc
+
+import (
+	"context"
+	"strings"
+
+	"nidavellir/internal/auth"
+	"nidavellir/internal/clients"
+
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// pidAddr 能够提供对端进程号的连接地址, 由UDS监听器设置
+type pidAddr interface {
+	PID() int32
+}
+
+// clientIdentity 根据连接信息构造客户端身份, 名称取自User-Agent
+func clientIdentity(ctx context.Context, kind string) clients.Identity {
+	identity := clients.Identity{Kind: kind, Transport: clients.TransportTCP}
+
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		if addr, ok := p.Addr.(pidAddr); ok {
+			identity.PID = addr.PID()
+		}
+		if p.Addr.Network() == "unix" {
+			identity.Transport = clients.TransportUDS
+		} else {
+			identity.Peer = p.Addr.String()
+		}
+	}
+
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if ua := md.Get("user-agent"); len(ua) > 0 {
+			// 去掉gRPC库追加的版本信息
+			name, _, _ := strings.Cut(ua[0], "grpc-go/")
+			identity.Name = strings.TrimSpace(name)
+		}
+	}
+
+	return identity
+}
+
+// bearerToken 从请求元数据的 authorization 中解析访问令牌
+func bearerToken(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		return ""
+	}
+	return auth.BearerToken(values[0])
+}
@@ -0,0 +1,51 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+
+	grpcConfig "nidavellir/api/proto"
+	"nidavellir/internal/etcd"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Search 搜索配置, 携带允许读取敏感配置的令牌时敏感配置的值参与匹配
+func (s *Server) Search(ctx context.Context, req *grpcConfig.SearchRequest) (*grpcConfig.SearchResponse, error) {
+	if req.Query == "" {
+		return nil, status.Error(codes.InvalidArgument, "query is required")
+	}
+
+	result, err := s.configService.Search(ctx, etcd.SearchOptions{
+		Query:          req.Query,
+		Regex:          req.Regex,
+		IgnoreCase:     req.IgnoreCase,
+		Fields:         req.Fields,
+		Services:       req.Services,
+		Labels:         req.Labels,
+		IncludeSecrets: s.authorizer.CanReadSecrets(bearerToken(ctx)),
+		Limit:          int(req.Limit),
+	}, etcd.Linearizable(req.Linearizable))
+	if errors.Is(err, etcd.ErrInvalidSearch) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err != nil {
+		s.logger.Error("Failed to search configs", zap.Error(err))
+		return nil, statusError(err, "Failed to search configs")
+	}
+
+	resp := &grpcConfig.SearchResponse{
+		Matches:   make([]*grpcConfig.SearchMatch, 0, len(result.Matches)),
+		Truncated: result.Truncated,
+		Revision:  result.Revision,
+	}
+	for _, match := range result.Matches {
+		resp.Matches = append(resp.Matches, &grpcConfig.SearchMatch{
+			Config:        toProtoConfig(match.Config),
+			MatchedFields: match.Fields,
+		})
+	}
+	return resp, nil
+}
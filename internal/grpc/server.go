@@ -3,17 +3,25 @@ package grpc
 import (
 	"context"
 	"encoding/json"
-	"fmt"
+	"errors"
 	"net"
+	"strconv"
+	"sync"
+	"time"
 
 	grpcConfig "nidavellir/api/proto"
+	"nidavellir/internal/auth"
+	"nidavellir/internal/backup"
+	"nidavellir/internal/clients"
 	"nidavellir/internal/config"
+	"nidavellir/internal/diff"
 	"nidavellir/internal/etcd"
+	"nidavellir/internal/watch"
 
-	clientv3 "go.etcd.io/etcd/client/v3"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 )
 
@@ -21,15 +29,37 @@ import (
 type Server struct {
 	grpcConfig.UnimplementedConfigServiceServer
 	configService *etcd.ConfigService
+	backups       *backup.Manager
+	differ        *diff.Resolver
+	hub           *watch.Hub
 	logger        *zap.Logger
 	grpcServer    *grpc.Server
+	registry      *clients.Registry
+	authorizer    *auth.Authorizer
+	// heartbeatInterval Session会话的心跳间隔
+	heartbeatInterval time.Duration
+	// done 关闭时通知长连接(监听流与会话)退出
+	done      chan struct{}
+	closeOnce sync.Once
 }
 
 // NewServer 创建gRPC服务器
-func NewServer(cfg config.GRPCConfig, configService *etcd.ConfigService, logger *zap.Logger) *Server {
+func NewServer(cfg config.GRPCConfig, configService *etcd.ConfigService, backups *backup.Manager, differ *diff.Resolver, hub *watch.Hub, registry *clients.Registry, authorizer *auth.Authorizer, logger *zap.Logger) *Server {
+	heartbeatInterval := time.Duration(cfg.SessionHeartbeatInterval) * time.Second
+	if heartbeatInterval <= 0 {
+		heartbeatInterval = 10 * time.Second
+	}
+
 	s := &Server{
-		configService: configService,
-		logger:        logger,
+		configService:     configService,
+		backups:           backups,
+		differ:            differ,
+		hub:               hub,
+		registry:          registry,
+		authorizer:        authorizer,
+		logger:            logger,
+		heartbeatInterval: heartbeatInterval,
+		done:              make(chan struct{}),
 	}
 
 	// 创建gRPC服务器
@@ -50,11 +80,37 @@ func (s *Server) Serve(lis net.Listener) error {
 	return s.grpcServer.Serve(lis)
 }
 
-// GracefulStop 优雅停止gRPC服务器
+// GracefulStop 优雅停止gRPC服务器, 先通知长连接退出再等待请求结束
 func (s *Server) GracefulStop() {
+	s.closeOnce.Do(func() {
+		close(s.done)
+	})
 	s.grpcServer.GracefulStop()
 }
 
+// streamContext 返回服务器关闭时取消的流上下文
+func (s *Server) streamContext(parent context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(parent)
+	go func() {
+		select {
+		case <-s.done:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	return ctx, cancel
+}
+
+// shutdownError 流因服务器关闭而结束时返回 UNAVAILABLE, 客户端可以按版本重连
+func (s *Server) shutdownError(err error) error {
+	select {
+	case <-s.done:
+		return status.Error(codes.Unavailable, "server is shutting down")
+	default:
+		return err
+	}
+}
+
 // SetConfig 设置配置
 func (s *Server) SetConfig(ctx context.Context, req *grpcConfig.SetConfigRequest) (*grpcConfig.SetConfigResponse, error) {
 	if req.ServiceName == "" || req.Key == "" {
@@ -68,9 +124,9 @@ func (s *Server) SetConfig(ctx context.Context, req *grpcConfig.SetConfigRequest
 		value = req.Value
 	}
 
-	if err := s.configService.SetConfig(ctx, req.ServiceName, req.Key, value, req.Description); err != nil {
+	if err := s.configService.SetConfig(ctx, req.ServiceName, req.Key, value, req.Description, req.Encrypt); err != nil {
 		s.logger.Error("Failed to set config", zap.Error(err))
-		return nil, status.Error(codes.Internal, "Failed to set config")
+		return nil, statusError(err, "Failed to set config")
 	}
 
 	return &grpcConfig.SetConfigResponse{
@@ -85,10 +141,10 @@ func (s *Server) GetConfig(ctx context.Context, req *grpcConfig.GetConfigRequest
 		return nil, status.Error(codes.InvalidArgument, "service_name and key are required")
 	}
 
-	configItem, err := s.configService.GetConfig(ctx, req.ServiceName, req.Key)
+	configItem, err := s.configService.GetConfig(ctx, req.ServiceName, req.Key, etcd.Linearizable(req.Linearizable))
 	if err != nil {
 		s.logger.Error("Failed to get config", zap.Error(err))
-		return nil, status.Error(codes.Internal, "Failed to get config")
+		return nil, statusError(err, "Failed to get config")
 	}
 
 	if configItem == nil {
@@ -97,19 +153,8 @@ func (s *Server) GetConfig(ctx context.Context, req *grpcConfig.GetConfigRequest
 		}, nil
 	}
 
-	// 转换为protobuf格式
-	valueBytes, _ := json.Marshal(configItem.Value)
-	protoConfig := &grpcConfig.ConfigItem{
-		Key:         configItem.Key,
-		Value:       string(valueBytes),
-		ServiceName: configItem.ServiceName,
-		Description: configItem.Description,
-		CreatedAt:   configItem.CreatedAt,
-		UpdatedAt:   configItem.UpdatedAt,
-	}
-
 	return &grpcConfig.GetConfigResponse{
-		Config: protoConfig,
+		Config: toProtoConfig(configItem),
 		Found:  true,
 	}, nil
 }
@@ -120,29 +165,38 @@ func (s *Server) GetServiceConfigs(ctx context.Context, req *grpcConfig.GetServi
 		return nil, status.Error(codes.InvalidArgument, "service_name is required")
 	}
 
-	configs, err := s.configService.GetServiceConfigs(ctx, req.ServiceName)
+	page, err := s.configService.GetServiceConfigsPage(ctx, req.ServiceName, etcd.PageOptions{
+		Limit:     int(req.Limit),
+		PageToken: req.PageToken,
+		KeysOnly:  req.KeysOnly,
+	}, etcd.Linearizable(req.Linearizable))
+	if errors.Is(err, etcd.ErrInvalidPage) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
 	if err != nil {
 		s.logger.Error("Failed to get service configs", zap.Error(err))
-		return nil, status.Error(codes.Internal, "Failed to get service configs")
-	}
-
-	// 转换为protobuf格式
-	protoConfigs := make(map[string]*grpcConfig.ConfigItem)
-	for key, configItem := range configs {
-		valueBytes, _ := json.Marshal(configItem.Value)
-		protoConfigs[key] = &grpcConfig.ConfigItem{
-			Key:         configItem.Key,
-			Value:       string(valueBytes),
-			ServiceName: configItem.ServiceName,
-			Description: configItem.Description,
-			CreatedAt:   configItem.CreatedAt,
-			UpdatedAt:   configItem.UpdatedAt,
+		return nil, statusError(err, "Failed to get service configs")
+	}
+
+	resp := &grpcConfig.GetServiceConfigsResponse{NextPageToken: page.NextPageToken}
+	if req.KeysOnly {
+		resp.Keys = make([]string, 0, len(page.Items))
+		for _, configItem := range page.Items {
+			resp.Keys = append(resp.Keys, configItem.Key)
 		}
+		return resp, nil
 	}
 
-	return &grpcConfig.GetServiceConfigsResponse{
-		Configs: protoConfigs,
-	}, nil
+	// 转换为protobuf格式, items 保持按键排序, configs 为兼容旧客户端保留
+	resp.Items = make([]*grpcConfig.ConfigItem, 0, len(page.Items))
+	resp.Configs = make(map[string]*grpcConfig.ConfigItem, len(page.Items))
+	for _, configItem := range page.Items {
+		item := toProtoConfig(configItem)
+		resp.Items = append(resp.Items, item)
+		resp.Configs[configItem.Key] = item
+	}
+
+	return resp, nil
 }
 
 // DeleteConfig 删除配置
@@ -153,7 +207,7 @@ func (s *Server) DeleteConfig(ctx context.Context, req *grpcConfig.DeleteConfigR
 
 	if err := s.configService.DeleteConfig(ctx, req.ServiceName, req.Key); err != nil {
 		s.logger.Error("Failed to delete config", zap.Error(err))
-		return nil, status.Error(codes.Internal, "Failed to delete config")
+		return nil, statusError(err, "Failed to delete config")
 	}
 
 	return &grpcConfig.DeleteConfigResponse{
@@ -170,7 +224,7 @@ func (s *Server) DeleteServiceConfigs(ctx context.Context, req *grpcConfig.Delet
 
 	if err := s.configService.DeleteServiceConfigs(ctx, req.ServiceName); err != nil {
 		s.logger.Error("Failed to delete service configs", zap.Error(err))
-		return nil, status.Error(codes.Internal, "Failed to delete service configs")
+		return nil, statusError(err, "Failed to delete service configs")
 	}
 
 	return &grpcConfig.DeleteServiceConfigsResponse{
@@ -181,74 +235,116 @@ func (s *Server) DeleteServiceConfigs(ctx context.Context, req *grpcConfig.Delet
 
 // ListServices 列出所有服务
 func (s *Server) ListServices(ctx context.Context, req *grpcConfig.ListServicesRequest) (*grpcConfig.ListServicesResponse, error) {
-	services, err := s.configService.ListServices(ctx)
+	page, err := s.configService.ListServicesPage(ctx, etcd.PageOptions{
+		Limit:     int(req.Limit),
+		PageToken: req.PageToken,
+	}, etcd.Linearizable(req.Linearizable))
+	if errors.Is(err, etcd.ErrInvalidPage) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
 	if err != nil {
 		s.logger.Error("Failed to list services", zap.Error(err))
-		return nil, status.Error(codes.Internal, "Failed to list services")
+		return nil, statusError(err, "Failed to list services")
 	}
 
-	return &grpcConfig.ListServicesResponse{
-		Services: services,
-	}, nil
+	resp := &grpcConfig.ListServicesResponse{
+		Services:      page.Services,
+		NextPageToken: page.NextPageToken,
+	}
+	if req.WithMetadata {
+		infos, err := s.configService.ServiceInfos(ctx, page.Services, etcd.Linearizable(req.Linearizable))
+		if err != nil {
+			s.logger.Error("Failed to get service metadata", zap.Error(err))
+			return nil, statusError(err, "Failed to get service metadata")
+		}
+		for i := range infos {
+			resp.Details = append(resp.Details, toProtoServiceInfo(&infos[i]))
+		}
+	}
+
+	return resp, nil
 }
 
 // WatchConfig 监听配置变化
 func (s *Server) WatchConfig(req *grpcConfig.WatchConfigRequest, stream grpcConfig.ConfigService_WatchConfigServer) error {
-	if req.ServiceName == "" {
-		return status.Error(codes.InvalidArgument, "service_name is required")
+	filter, err := watchFilter(req)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	if req.StartRevision < 0 {
+		return status.Error(codes.InvalidArgument, "start_revision must not be negative")
+	}
+	if req.Snapshot && req.StartRevision > 0 {
+		return status.Error(codes.InvalidArgument, "snapshot cannot be combined with start_revision")
 	}
 
-	// 构建监听键
-	var watchKey string
-	if req.Key != "" {
-		watchKey = fmt.Sprintf("/nidavellir/config/%s/%s", req.ServiceName, req.Key)
-	} else {
-		watchKey = fmt.Sprintf("/nidavellir/config/%s/", req.ServiceName)
+	client := s.registry.Register(clientIdentity(stream.Context(), clients.KindWatch), filter.Strings())
+	defer s.registry.Unregister(client)
+	if req.StartRevision > 0 {
+		client.Delivered(req.StartRevision - 1)
+	} else if !req.Snapshot {
+		client.Delivered(s.hub.Revision())
 	}
 
-	// 创建etcd客户端用于监听
-	etcdClient := s.configService.GetEtcdClient() // 需要在ConfigService中添加此方法
-	var watchChan clientv3.WatchChan
-	if req.Key != "" {
-		watchChan = etcdClient.Watch(stream.Context(), watchKey)
-	} else {
-		watchChan = etcdClient.WatchWithPrefix(stream.Context(), watchKey)
+	send := func(resp *grpcConfig.WatchConfigResponse) error {
+		if err := stream.Send(resp); err != nil {
+			s.logger.Error("Failed to send watch response", zap.Error(err))
+			return err
+		}
+		if resp.EventType != etcd.EventTypeSnapshot {
+			client.Delivered(resp.Revision)
+		}
+		return nil
 	}
 
-	for watchResp := range watchChan {
-		for _, event := range watchResp.Events {
-			// 解析配置项
-			var configItem etcd.ConfigItem
-			if err := json.Unmarshal(event.Kv.Value, &configItem); err != nil {
-				s.logger.Warn("Failed to unmarshal config item", zap.Error(err))
-				continue
-			}
+	ctx, cancel := s.streamContext(stream.Context())
+	defer cancel()
 
-			// 转换为protobuf格式
-			valueBytes, _ := json.Marshal(configItem.Value)
-			protoConfig := &grpcConfig.ConfigItem{
-				Key:         configItem.Key,
-				Value:       string(valueBytes),
-				ServiceName: configItem.ServiceName,
-				Description: configItem.Description,
-				CreatedAt:   configItem.CreatedAt,
-				UpdatedAt:   configItem.UpdatedAt,
-			}
+	startRevision := req.StartRevision
+	if req.Snapshot {
+		revision, err := s.sendSnapshot(ctx, filter, send)
+		if err != nil {
+			return s.shutdownError(err)
+		}
+		startRevision = revision + 1
+	}
 
-			// 确定事件类型
-			eventType := "PUT"
-			if event.Type == clientv3.EventTypeDelete {
-				eventType = "DELETE"
-			}
+	return s.shutdownError(s.forwardWatch(ctx, filter, startRevision, send))
+}
+
+// forwardWatch 从分发中心订阅变更并逐条发送, 直到ctx结束或订阅出错
+func (s *Server) forwardWatch(ctx context.Context, filter watch.Filter, startRevision int64, send func(*grpcConfig.WatchConfigResponse) error) error {
+	watchChan := s.hub.Watch(ctx, filter, startRevision)
+
+	for watchResp := range watchChan {
+		if watchResp.CompactRevision != 0 {
+			return status.Errorf(codes.OutOfRange,
+				"required revision %d has been compacted, compact revision is %d",
+				startRevision, watchResp.CompactRevision)
+		}
+		if errors.Is(watchResp.Err, watch.ErrSlowConsumer) {
+			return status.Error(codes.ResourceExhausted, "watch consumer too slow, resume with start_revision")
+		}
+		if errors.Is(watchResp.Err, watch.ErrResync) {
+			return status.Error(codes.Aborted, "watch history lost, reload and watch again")
+		}
+		if watchResp.Err != nil {
+			s.logger.Error("Watch failed", zap.Error(watchResp.Err))
+			return status.Error(codes.Unavailable, "Watch failed")
+		}
 
-			// 发送响应
-			response := &grpcConfig.WatchConfigResponse{
-				EventType: eventType,
-				Config:    protoConfig,
+		if watchResp.Progress && len(watchResp.Events) == 0 {
+			if err := send(&grpcConfig.WatchConfigResponse{
+				EventType: etcd.EventTypeProgress,
+				Revision:  watchResp.Revision,
+			}); err != nil {
+				return err
 			}
+			continue
+		}
 
-			if err := stream.Send(response); err != nil {
-				s.logger.Error("Failed to send watch response", zap.Error(err))
+		for _, event := range watchResp.Events {
+			if err := send(toWatchResponse(event)); err != nil {
 				return err
 			}
 		}
@@ -257,14 +353,179 @@ func (s *Server) WatchConfig(req *grpcConfig.WatchConfigRequest, stream grpcConf
 	return nil
 }
 
+// ListClients 列出已连接的客户端及其版本漂移情况
+func (s *Server) ListClients(ctx context.Context, req *grpcConfig.ListClientsRequest) (*grpcConfig.ListClientsResponse, error) {
+	latest, revision, err := clients.LoadLatest(ctx, s.configService, s.hub)
+	if err != nil {
+		s.logger.Error("Failed to load latest revisions", zap.Error(err))
+		return nil, status.Error(codes.Internal, "Failed to list clients")
+	}
+
+	var infos []clients.Info
+	if req.DriftOnly {
+		infos = s.registry.Drift(latest)
+	} else {
+		infos = s.registry.List(latest)
+	}
+
+	resp := &grpcConfig.ListClientsResponse{Revision: revision}
+	for _, info := range infos {
+		resp.Clients = append(resp.Clients, &grpcConfig.ClientInfo{
+			Id:                info.ID,
+			Kind:              info.Kind,
+			Name:              info.Name,
+			InstanceId:        info.InstanceID,
+			Labels:            info.Labels,
+			Transport:         info.Transport,
+			Peer:              info.Peer,
+			Pid:               info.PID,
+			Selectors:         info.Selectors,
+			ConnectedAt:       info.ConnectedAt,
+			DeliveredRevision: info.DeliveredRevision,
+			AckedRevision:     info.AckedRevision,
+			AppliedRevision:   info.AppliedRevision,
+			RequiredRevision:  info.RequiredRevision,
+			BehindServices:    info.BehindServices,
+		})
+	}
+
+	return resp, nil
+}
+
+// watchFilter 根据service_name、key与selectors构造过滤条件, 均为空时监听所有服务。
+// service_name与key按原样匹配, 只有selectors支持通配符。
+func watchFilter(req *grpcConfig.WatchConfigRequest) (watch.Filter, error) {
+	var filter watch.Filter
+	if req.ServiceName != "" {
+		filter.Selectors = append(filter.Selectors, watch.ExactSelector(req.ServiceName, req.Key))
+	} else if req.Key != "" {
+		return filter, errors.New("key requires service_name")
+	}
+
+	for _, s := range req.Selectors {
+		selector, err := watch.ParseSelector(s)
+		if err != nil {
+			return filter, err
+		}
+		filter.Selectors = append(filter.Selectors, selector)
+	}
+	return filter, nil
+}
+
+// sendSnapshot 推送配置的快照并以SNAPSHOT_END结束, 返回快照的版本号
+func (s *Server) sendSnapshot(ctx context.Context, filter watch.Filter, send func(*grpcConfig.WatchConfigResponse) error) (int64, error) {
+	service, key := filter.Scope()
+	items, revision, err := s.configService.Snapshot(ctx, service, key)
+	if err != nil {
+		s.logger.Error("Failed to get config snapshot", zap.Error(err))
+		return 0, status.Error(codes.Unavailable, "Failed to get config snapshot")
+	}
+
+	for _, item := range items {
+		if !filter.MatchKey(item.ServiceName, item.Key) {
+			continue
+		}
+		if err := send(&grpcConfig.WatchConfigResponse{
+			EventType: etcd.EventTypeSnapshot,
+			Config:    toProtoConfig(item),
+			Revision:  revision,
+		}); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := send(&grpcConfig.WatchConfigResponse{
+		EventType: etcd.EventTypeSnapshotEnd,
+		Revision:  revision,
+	}); err != nil {
+		return 0, err
+	}
+
+	return revision, nil
+}
+
+// toWatchResponse 转换配置变更事件, DELETE事件的config为删除前的值
+func toWatchResponse(event *etcd.ConfigEvent) *grpcConfig.WatchConfigResponse {
+	response := &grpcConfig.WatchConfigResponse{
+		EventType: event.Type,
+		Revision:  event.Revision,
+	}
+
+	if event.Type == etcd.EventTypeDelete {
+		response.Config = toProtoConfig(event.PrevItem)
+		if response.Config == nil {
+			// 删除前的值不可用时至少携带被删除的键
+			response.Config = &grpcConfig.ConfigItem{}
+		}
+		response.Config.Key = event.Key
+		response.Config.ServiceName = event.ServiceName
+		return response
+	}
+
+	response.Config = toProtoConfig(event.Item)
+	response.PrevConfig = toProtoConfig(event.PrevItem)
+	return response
+}
+
+// toProtoConfig 转换为protobuf格式, value序列化为JSON文本
+func toProtoConfig(configItem *etcd.ConfigItem) *grpcConfig.ConfigItem {
+	if configItem == nil {
+		return nil
+	}
+
+	valueBytes, _ := json.Marshal(configItem.Value)
+	return &grpcConfig.ConfigItem{
+		Key:         configItem.Key,
+		Value:       string(valueBytes),
+		ServiceName: configItem.ServiceName,
+		Description: configItem.Description,
+		Encrypt:     configItem.Encrypt,
+		CreatedAt:   configItem.CreatedAt,
+		UpdatedAt:   configItem.UpdatedAt,
+	}
+}
+
 // unaryInterceptor 一元拦截器
 func (s *Server) unaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
 	s.logger.Info("gRPC unary call", zap.String("method", info.FullMethod))
+	if md := s.degradedMetadata(); md != nil {
+		_ = grpc.SetHeader(ctx, md)
+	}
 	return handler(ctx, req)
 }
 
 // streamInterceptor 流拦截器
 func (s *Server) streamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
 	s.logger.Info("gRPC stream call", zap.String("method", info.FullMethod))
+	if md := s.degradedMetadata(); md != nil {
+		_ = ss.SetHeader(md)
+	}
 	return handler(srv, ss)
 }
+
+// degradedMetadata 降级期间标记数据可能过期的响应头, 未降级时返回nil
+func (s *Server) degradedMetadata() metadata.MD {
+	st := s.configService.Status()
+	if !st.Degraded {
+		return nil
+	}
+	return metadata.Pairs(
+		"x-nidavellir-degraded", "true",
+		"x-nidavellir-stale-since", strconv.FormatInt(st.Since, 10),
+		"x-nidavellir-revision", strconv.FormatInt(st.Revision, 10),
+	)
+}
+
+// statusError 转换为gRPC错误, etcd不可用(降级模式)时返回 UNAVAILABLE 与具体原因, 服务受保护时返回 PERMISSION_DENIED, 写入期间服务元数据被修改时返回 ABORTED
+func statusError(err error, message string) error {
+	if errors.Is(err, etcd.ErrReadOnly) || errors.Is(err, etcd.ErrUnavailable) {
+		return status.Error(codes.Unavailable, err.Error())
+	}
+	if errors.Is(err, etcd.ErrProtected) {
+		return status.Error(codes.PermissionDenied, err.Error())
+	}
+	if errors.Is(err, etcd.ErrMetaConflict) {
+		return status.Error(codes.Aborted, err.Error())
+	}
+	return status.Error(codes.Internal, message)
+}
@@ -0,0 +1,355 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	grpcConfig "nidavellir/api/proto"
+	"nidavellir/internal/clients"
+	"nidavellir/internal/etcd"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// sessionTimeoutBeats 连续多少个心跳间隔未收到客户端消息时关闭会话
+const sessionTimeoutBeats = 3
+
+// session 会话状态, 身份与版本记录在客户端登记表中
+type session struct {
+	id     string
+	client *clients.Client
+
+	mu            sync.Mutex
+	subscriptions map[string]*sessionSubscription
+	lastSeen      time.Time
+}
+
+// sessionSubscription 会话中的一个订阅
+type sessionSubscription struct {
+	selectors []string
+	cancel    context.CancelFunc
+}
+
+// Session 长连接会话, 在同一个流上处理订阅、确认与心跳
+func (s *Server) Session(stream grpcConfig.ConfigService_SessionServer) error {
+	ctx, cancel := s.streamContext(stream.Context())
+	defer cancel()
+
+	first, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	hello := first.GetHello()
+	if hello == nil {
+		return status.Error(codes.InvalidArgument, "first session message must be hello")
+	}
+	if hello.ClientName == "" {
+		return status.Error(codes.InvalidArgument, "client_name is required")
+	}
+
+	identity := clientIdentity(ctx, clients.KindSession)
+	identity.Name = hello.ClientName
+	identity.InstanceID = hello.InstanceId
+	identity.Labels = hello.Labels
+
+	sess := s.openSession(identity)
+	defer s.closeSession(sess)
+
+	if err := stream.Send(&grpcConfig.SessionResponse{
+		Response: &grpcConfig.SessionResponse_Welcome{Welcome: &grpcConfig.SessionWelcome{
+			SessionId:         sess.id,
+			HeartbeatInterval: int64(s.heartbeatInterval / time.Second),
+		}},
+	}); err != nil {
+		return err
+	}
+
+	// 接收协程, 请求统一交给主循环处理
+	reqs := make(chan *grpcConfig.SessionRequest)
+	recvErr := make(chan error, 1)
+	go func() {
+		for {
+			req, err := stream.Recv()
+			if err != nil {
+				recvErr <- err
+				return
+			}
+			select {
+			case reqs <- req:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	// 订阅协程产生的响应, 由主循环统一发送
+	out := make(chan *grpcConfig.SessionResponse)
+
+	heartbeat := time.NewTicker(s.heartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return s.shutdownError(ctx.Err())
+		case err := <-recvErr:
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		case req := <-reqs:
+			sess.touch()
+			if resp := s.handleSessionRequest(ctx, sess, req, out); resp != nil {
+				if err := stream.Send(resp); err != nil {
+					return err
+				}
+			}
+		case resp := <-out:
+			if err := stream.Send(resp); err != nil {
+				return err
+			}
+			sess.markDelivered(resp)
+		case <-heartbeat.C:
+			if sess.idle() > sessionTimeoutBeats*s.heartbeatInterval {
+				s.logger.Warn("Session heartbeat timeout",
+					zap.String("session", sess.id),
+					zap.String("client", identity.Name))
+				return status.Error(codes.DeadlineExceeded, "session heartbeat timeout")
+			}
+			if err := stream.Send(&grpcConfig.SessionResponse{
+				Response: &grpcConfig.SessionResponse_Heartbeat{Heartbeat: &grpcConfig.SessionHeartbeat{
+					Timestamp: time.Now().UnixMilli(),
+					Revision:  s.hub.Revision(),
+				}},
+			}); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// handleSessionRequest 处理会话请求, 返回需要立即发送的响应
+func (s *Server) handleSessionRequest(ctx context.Context, sess *session, req *grpcConfig.SessionRequest, out chan<- *grpcConfig.SessionResponse) *grpcConfig.SessionResponse {
+	switch r := req.Request.(type) {
+	case *grpcConfig.SessionRequest_Subscribe:
+		return s.subscribe(ctx, sess, r.Subscribe, out)
+	case *grpcConfig.SessionRequest_Unsubscribe:
+		id := r.Unsubscribe.SubscriptionId
+		if !sess.unsubscribe(id) {
+			return sessionError(id, codes.NotFound, "subscription not found")
+		}
+		return &grpcConfig.SessionResponse{
+			Response: &grpcConfig.SessionResponse_Unsubscribed{Unsubscribed: &grpcConfig.SessionUnsubscribed{SubscriptionId: id}},
+		}
+	case *grpcConfig.SessionRequest_Ack:
+		if r.Ack.Revision < 0 {
+			return sessionError("", codes.InvalidArgument, "revision must not be negative")
+		}
+		if r.Ack.Revision > s.hub.Revision() {
+			return sessionError("", codes.InvalidArgument, "revision is ahead of the server")
+		}
+		sess.client.Ack(r.Ack.Revision)
+		return nil
+	case *grpcConfig.SessionRequest_Heartbeat:
+		return nil
+	case *grpcConfig.SessionRequest_Hello:
+		return sessionError("", codes.FailedPrecondition, "session already established")
+	default:
+		return sessionError("", codes.InvalidArgument, "unknown session request")
+	}
+}
+
+// subscribe 校验订阅请求并启动订阅协程
+func (s *Server) subscribe(ctx context.Context, sess *session, req *grpcConfig.SessionSubscribe, out chan<- *grpcConfig.SessionResponse) *grpcConfig.SessionResponse {
+	id := req.SubscriptionId
+	if id == "" {
+		return sessionError("", codes.InvalidArgument, "subscription_id is required")
+	}
+	if req.StartRevision < 0 {
+		return sessionError(id, codes.InvalidArgument, "start_revision must not be negative")
+	}
+	if req.Snapshot && req.StartRevision > 0 {
+		return sessionError(id, codes.InvalidArgument, "snapshot cannot be combined with start_revision")
+	}
+	filter, err := watchFilter(&grpcConfig.WatchConfigRequest{Selectors: req.Selectors})
+	if err != nil {
+		return sessionError(id, codes.InvalidArgument, err.Error())
+	}
+
+	subCtx, cancel := context.WithCancel(ctx)
+	if !sess.addSubscription(id, &sessionSubscription{selectors: filter.Strings(), cancel: cancel}) {
+		cancel()
+		return sessionError(id, codes.AlreadyExists, "subscription already exists")
+	}
+
+	go func() {
+		defer cancel()
+
+		emit := func(resp *grpcConfig.SessionResponse) error {
+			select {
+			case out <- resp:
+				return nil
+			case <-subCtx.Done():
+				return subCtx.Err()
+			}
+		}
+		send := func(event *grpcConfig.WatchConfigResponse) error {
+			return emit(&grpcConfig.SessionResponse{
+				Response: &grpcConfig.SessionResponse_Event{Event: &grpcConfig.SessionEvent{
+					SubscriptionId: id,
+					Event:          event,
+				}},
+			})
+		}
+
+		err := emit(&grpcConfig.SessionResponse{
+			Response: &grpcConfig.SessionResponse_Subscribed{Subscribed: &grpcConfig.SessionSubscribed{SubscriptionId: id}},
+		})
+		if err == nil {
+			startRevision := req.StartRevision
+			if req.Snapshot {
+				var revision int64
+				revision, err = s.sendSnapshot(subCtx, filter, send)
+				startRevision = revision + 1
+			}
+			if err == nil {
+				err = s.forwardWatch(subCtx, filter, startRevision, send)
+			}
+		}
+
+		// 取消订阅或会话结束时无需通知
+		if subCtx.Err() != nil {
+			return
+		}
+		sess.removeSubscription(id)
+		if err == nil {
+			err = status.Error(codes.Unavailable, "watch closed")
+		}
+		st := status.Convert(err)
+		_ = emit(sessionError(id, st.Code(), st.Message()))
+	}()
+
+	return nil
+}
+
+// openSession 登记会话
+func (s *Server) openSession(identity clients.Identity) *session {
+	client := s.registry.Register(identity, nil)
+	sess := &session{
+		id:            client.ID(),
+		client:        client,
+		subscriptions: make(map[string]*sessionSubscription),
+		lastSeen:      time.Now(),
+	}
+
+	s.logger.Info("Session established",
+		zap.String("session", sess.id),
+		zap.String("client", identity.Name),
+		zap.String("instance", identity.InstanceID),
+		zap.String("transport", identity.Transport),
+		zap.Int32("pid", identity.PID))
+	return sess
+}
+
+// closeSession 注销会话并取消其所有订阅
+func (s *Server) closeSession(sess *session) {
+	s.registry.Unregister(sess.client)
+
+	sess.mu.Lock()
+	for _, sub := range sess.subscriptions {
+		sub.cancel()
+	}
+	sess.mu.Unlock()
+
+	s.logger.Info("Session closed", zap.String("session", sess.id))
+}
+
+// touch 记录收到客户端消息的时间
+func (s *session) touch() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastSeen = time.Now()
+}
+
+// idle 距离上次收到客户端消息的时间
+func (s *session) idle() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return time.Since(s.lastSeen)
+}
+
+// markDelivered 记录已发送的事件版本
+func (s *session) markDelivered(resp *grpcConfig.SessionResponse) {
+	event := resp.GetEvent().GetEvent()
+	if event == nil || event.EventType == etcd.EventTypeSnapshot {
+		return
+	}
+	s.client.Delivered(event.Revision)
+}
+
+// addSubscription 添加订阅, 标识已存在时返回false
+func (s *session) addSubscription(id string, sub *sessionSubscription) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.subscriptions[id]; ok {
+		return false
+	}
+	s.subscriptions[id] = sub
+	s.updateSelectors()
+	return true
+}
+
+// removeSubscription 移除订阅
+func (s *session) removeSubscription(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.subscriptions, id)
+	s.updateSelectors()
+}
+
+// unsubscribe 取消并移除订阅, 不存在时返回false
+func (s *session) unsubscribe(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sub, ok := s.subscriptions[id]
+	if !ok {
+		return false
+	}
+	sub.cancel()
+	delete(s.subscriptions, id)
+	s.updateSelectors()
+	return true
+}
+
+// updateSelectors 将所有订阅的条件合并记录到登记表, 调用方需持有锁
+func (s *session) updateSelectors() {
+	var selectors []string
+	seen := make(map[string]bool)
+	for _, sub := range s.subscriptions {
+		for _, selector := range sub.selectors {
+			if !seen[selector] {
+				seen[selector] = true
+				selectors = append(selectors, selector)
+			}
+		}
+	}
+	sort.Strings(selectors)
+	s.client.SetSelectors(selectors)
+}
+
+// sessionError 构造会话错误响应
+func sessionError(subscriptionID string, code codes.Code, message string) *grpcConfig.SessionResponse {
+	return &grpcConfig.SessionResponse{
+		Response: &grpcConfig.SessionResponse_Error{Error: &grpcConfig.SessionError{
+			SubscriptionId: subscriptionID,
+			Code:           code.String(),
+			Message:        message,
+		}},
+	}
+}
@@ -0,0 +1,82 @@
+package grpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	grpcConfig "nidavellir/api/proto"
+	"nidavellir/internal/etcd"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Sync 将声明的服务配置同步到存储, 未授权的调用方看到的敏感配置值被隐藏
+func (s *Server) Sync(ctx context.Context, req *grpcConfig.SyncRequest) (*grpcConfig.SyncResponse, error) {
+	services := make([]etcd.SyncService, 0, len(req.Services))
+	for _, svc := range req.Services {
+		service := etcd.SyncService{
+			Name:      svc.Name,
+			Configs:   make(map[string]etcd.SyncConfig, len(svc.Configs)),
+			Unmanaged: svc.Unmanaged,
+		}
+		for _, cfg := range svc.Configs {
+			if _, ok := service.Configs[cfg.Key]; ok {
+				return nil, status.Errorf(codes.InvalidArgument, "config %s/%s declared more than once", svc.Name, cfg.Key)
+			}
+			var value interface{}
+			if err := json.Unmarshal([]byte(cfg.Value), &value); err != nil {
+				value = cfg.Value
+			}
+			service.Configs[cfg.Key] = etcd.SyncConfig{Value: value, Description: cfg.Description, Encrypt: cfg.Encrypt}
+		}
+		services = append(services, service)
+	}
+
+	var (
+		plan *etcd.SyncPlan
+		err  error
+	)
+	if req.DryRun {
+		plan, err = s.configService.PlanSync(ctx, services, req.Prune)
+	} else {
+		plan, err = s.configService.ApplySync(ctx, services, req.Prune)
+	}
+	switch {
+	case errors.Is(err, etcd.ErrInvalidSync) || errors.Is(err, etcd.ErrInvalidServiceName):
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, etcd.ErrSyncConflict):
+		return nil, status.Error(codes.Aborted, err.Error())
+	case err != nil:
+		s.logger.Error("Failed to sync configs", zap.Error(err))
+		return nil, statusError(err, "Failed to sync configs")
+	}
+
+	if !s.authorizer.CanReadSecrets(bearerToken(ctx)) {
+		plan = plan.Masked()
+	}
+	return &grpcConfig.SyncResponse{
+		Changes:  toProtoSyncChanges(plan.Changes),
+		Extra:    plan.Extra,
+		Revision: plan.Revision,
+		Applied:  plan.Applied,
+	}, nil
+}
+
+// toProtoSyncChanges 转换同步计划中的变更
+func toProtoSyncChanges(changes []etcd.SyncChange) []*grpcConfig.SyncChange {
+	result := make([]*grpcConfig.SyncChange, 0, len(changes))
+	for _, change := range changes {
+		result = append(result, &grpcConfig.SyncChange{
+			Action:      change.Action,
+			ServiceName: change.ServiceName,
+			Key:         change.Key,
+			Fields:      change.Fields,
+			Config:      toProtoConfig(change.Config),
+			Current:     toProtoConfig(change.Current),
+		})
+	}
+	return result
+}
@@ -5,6 +5,8 @@ package grpc
 import (
 	"go.uber.org/zap"
 	"net"
+	"strconv"
+	"syscall"
 )
 
 func (s *Server) ServeUDS(unixAddr string) error {
@@ -17,5 +19,76 @@ func (s *Server) ServeUDS(unixAddr string) error {
 		s.logger.Fatal("Failed to listen gRPC uds", zap.Error(err))
 	}
 	s.logger.Info("Starting gRPC UDS server", zap.String("addr", unixAddr))
-	return s.grpcServer.Serve(udsLis)
+	return s.grpcServer.Serve(&credListener{UnixListener: udsLis, logger: s.logger})
+}
+
+// credListener 读取对端进程凭证(SO_PEERCRED)的UDS监听器
+type credListener struct {
+	*net.UnixListener
+	logger *zap.Logger
+}
+
+// Accept 接受连接并将对端进程号附加到连接地址
+func (l *credListener) Accept() (net.Conn, error) {
+	conn, err := l.AcceptUnix()
+	if err != nil {
+		return nil, err
+	}
+
+	cred, err := peerCred(conn)
+	if err != nil {
+		l.logger.Warn("Failed to get uds peer credentials", zap.Error(err))
+		return conn, nil
+	}
+	return &credConn{UnixConn: conn, addr: &credAddr{pid: cred.Pid}}, nil
+}
+
+// peerCred 获取对端进程凭证
+func peerCred(conn *net.UnixConn) (*syscall.Ucred, error) {
+	raw, err := conn.SyscallConn()
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		cred    *syscall.Ucred
+		credErr error
+	)
+	if err := raw.Control(func(fd uintptr) {
+		cred, credErr = syscall.GetsockoptUcred(int(fd), syscall.SOL_SOCKET, syscall.SO_PEERCRED)
+	}); err != nil {
+		return nil, err
+	}
+	return cred, credErr
+}
+
+// credConn 带有对端进程凭证的UDS连接
+type credConn struct {
+	*net.UnixConn
+	addr *credAddr
+}
+
+// RemoteAddr 返回带有进程号的对端地址
+func (c *credConn) RemoteAddr() net.Addr {
+	return c.addr
+}
+
+// credAddr 带有进程号的UDS地址
+type credAddr struct {
+	pid int32
+}
+
+// PID 对端进程号
+func (a *credAddr) PID() int32 {
+	return a.pid
+}
+
+// Network 网络类型
+func (a *credAddr) Network() string {
+	return "unix"
+}
+
+// String 以 pid:进程号 表示对端
+func (a *credAddr) String() string {
+	return "pid:" + strconv.Itoa(int(a.pid))
 }
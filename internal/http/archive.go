@@ -0,0 +1,104 @@
+package http
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"nidavellir/internal/archive"
+	"nidavellir/internal/auth"
+	"nidavellir/internal/etcd"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// maxArchiveBytes 导入的归档大小上限
+const maxArchiveBytes = 64 << 20
+
+// exportConfigs 导出所有服务的配置与元数据, 以附件下载
+//
+// 查询参数: format 归档格式(json、toml), 默认为 json,
+// service 服务名, 支持通配符, 可重复, 为空时导出所有服务。
+// 敏感配置的值以归档密钥加密, 未授权的调用方或未配置归档密钥时不含值, 导入时跳过
+func (s *Server) exportConfigs(c *gin.Context) {
+	format, err := archive.ParseFormat(c.Query("format"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := s.configService.Export(c.Request.Context(), etcd.ExportOptions{
+		Services:       c.QueryArray("service"),
+		IncludeSecrets: s.authorizer.CanReadSecrets(auth.BearerToken(c.GetHeader("Authorization"))),
+	})
+	if err != nil {
+		s.logger.Error("failed to export configs", zap.Error(err))
+		respondError(c, err, "Failed to export configs")
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := archive.Encode(&buf, result, format); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+	contentType := "application/json"
+	if format == archive.FormatTOML {
+		contentType = "application/toml"
+	}
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="nidavellir-%d.%s"`, result.Revision, format))
+	c.Header("X-Nidavellir-Revision", strconv.FormatInt(result.Revision, 10))
+	c.Data(http.StatusOK, contentType, buf.Bytes())
+}
+
+// importConfigs 导入请求体中的归档
+//
+// 查询参数: mode 导入模式(merge、replace), 默认为 merge,
+// service 只导入匹配的服务, 支持通配符, 可重复,
+// format 归档格式(json、toml), 为空时按内容判断,
+// dry_run 只返回将要执行的变更, 不写入
+func (s *Server) importConfigs(c *gin.Context) {
+	format := c.Query("format")
+	if format != "" {
+		var err error
+		if format, err = archive.ParseFormat(format); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	data, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxArchiveBytes))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	arc, err := archive.Decode(data, format)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	dryRun, _ := strconv.ParseBool(c.Query("dry_run"))
+	result, err := s.configService.Import(c.Request.Context(), arc, etcd.ImportOptions{
+		Mode:     c.Query("mode"),
+		Services: c.QueryArray("service"),
+		DryRun:   dryRun,
+	})
+	if err != nil {
+		if errors.Is(err, etcd.ErrSyncConflict) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		s.logger.Error("failed to import configs", zap.Error(err))
+		respondError(c, err, "Failed to import configs")
+		return
+	}
+
+	if !s.authorizer.CanReadSecrets(auth.BearerToken(c.GetHeader("Authorization"))) {
+		result = result.Masked()
+	}
+	c.JSON(http.StatusOK, result)
+}
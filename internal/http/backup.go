@@ -0,0 +1,75 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"nidavellir/internal/auth"
+	"nidavellir/internal/backup"
+	"nidavellir/internal/etcd"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// listBackups 列出本地备份, 最新的在前
+func (s *Server) listBackups(c *gin.Context) {
+	backups, err := s.backups.List()
+	if err != nil {
+		s.respondBackupError(c, err, "Failed to list backups")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"backups": backups})
+}
+
+// createBackup 立即创建备份
+func (s *Server) createBackup(c *gin.Context) {
+	info, err := s.backups.Create(c.Request.Context())
+	if err != nil {
+		s.respondBackupError(c, err, "Failed to create backup")
+		return
+	}
+	c.JSON(http.StatusOK, info)
+}
+
+// restoreBackup 从备份恢复配置, 默认只返回将要执行的变更, 确认后才写入
+//
+// 查询参数: service 只恢复匹配的服务, 支持通配符, 可重复, 为空时恢复所有服务,
+// mode 恢复模式(replace、merge), 默认为 replace, 即恢复到备份时的状态,
+// confirm 为 true 时写入, dry_run 为 true 时只返回计划, 优先于 confirm
+func (s *Server) restoreBackup(c *gin.Context) {
+	dryRun, _ := strconv.ParseBool(c.Query("dry_run"))
+	confirmed, _ := strconv.ParseBool(c.Query("confirm"))
+	result, err := s.backups.Restore(c.Request.Context(), c.Param("name"), etcd.ImportOptions{
+		Mode:     c.Query("mode"),
+		Services: c.QueryArray("service"),
+		DryRun:   dryRun || !confirmed,
+	})
+	if err != nil {
+		s.respondBackupError(c, err, "Failed to restore backup")
+		return
+	}
+
+	if !s.authorizer.CanReadSecrets(auth.BearerToken(c.GetHeader("Authorization"))) {
+		result = result.Masked()
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// respondBackupError 返回备份相关的错误
+func (s *Server) respondBackupError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, backup.ErrDisabled) || errors.Is(err, backup.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, backup.ErrNoKey):
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	case errors.Is(err, etcd.ErrInvalidImport) || errors.Is(err, etcd.ErrInvalidServiceName):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, etcd.ErrSyncConflict):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		s.logger.Error(message, zap.Error(err))
+		respondError(c, err, message)
+	}
+}
@@ -0,0 +1,85 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"nidavellir/internal/auth"
+	"nidavellir/internal/backup"
+	"nidavellir/internal/diff"
+	"nidavellir/internal/etcd"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// diffConfigs 比较两侧服务的配置
+//
+// 查询参数: left、right 比较的两侧, 格式为 [<namespace>/]<service>[@<revision>],
+// namespace 为 backup:<name> 时为本地备份, 为 draft:<id> 时为发布草稿后的配置, 否则为配置的环境名, 归档文件(file:)只能通过gRPC上传;
+// format 输出格式(json、unified), 默认为 json, context 统一格式中的上下文行数, 默认为 3
+func (s *Server) diffConfigs(c *gin.Context) {
+	left, err := diff.ParseSide(c.Query("left"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	right, err := diff.ParseSide(c.Query("right"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if left.File != "" || right.File != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "file sides are only supported over gRPC"})
+		return
+	}
+
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "unified" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be json or unified"})
+		return
+	}
+	context := diff.DefaultContext
+	if v := c.Query("context"); v != "" {
+		if context, err = strconv.Atoi(v); err != nil || context < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "context must be a non-negative integer"})
+			return
+		}
+	}
+
+	result, err := s.differ.Diff(c.Request.Context(), left, right)
+	switch {
+	case errors.Is(err, diff.ErrInvalidSide) || errors.Is(err, etcd.ErrInvalidRevision) ||
+		errors.Is(err, etcd.ErrInvalidServiceName):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, backup.ErrDisabled) || errors.Is(err, backup.ErrNotFound) ||
+		errors.Is(err, etcd.ErrDraftNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, diff.ErrEnvironment):
+		c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		s.logger.Error("failed to diff configs", zap.Error(err))
+		respondError(c, err, "Failed to diff configs")
+		return
+	}
+
+	if !s.authorizer.CanReadSecrets(auth.BearerToken(c.GetHeader("Authorization"))) {
+		result = result.Masked()
+	}
+	if format == "unified" {
+		var buf strings.Builder
+		if err := result.Unified(&buf, context); err != nil {
+			respondError(c, err, "Failed to render diff")
+			return
+		}
+		c.String(http.StatusOK, buf.String())
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,221 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"nidavellir/internal/auth"
+	"nidavellir/internal/diff"
+	"nidavellir/internal/etcd"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// createDraft 创建草稿, 需要作者或审批人的令牌, 令牌对应的名称即为作者
+func (s *Server) createDraft(c *gin.Context) {
+	var req struct {
+		Description string                `json:"description"`
+		Operations  []etcd.DraftOperation `json:"operations" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	author, ok := s.member(c, "create drafts")
+	if !ok {
+		return
+	}
+
+	draft, err := s.configService.CreateDraft(c.Request.Context(), &etcd.Draft{
+		Description:       req.Description,
+		Author:            author,
+		Operations:        req.Operations,
+		RequiredApprovals: s.authorizer.RequiredApprovals(),
+	})
+	if err != nil {
+		s.respondDraftError(c, err, "Failed to create draft")
+		return
+	}
+	s.respondDraft(c, draft)
+}
+
+// listDrafts 列出草稿, 最新的在前
+//
+// 查询参数: status 草稿状态(open、published、abandoned), 为空时列出所有草稿
+func (s *Server) listDrafts(c *gin.Context) {
+	drafts, err := s.configService.ListDrafts(c.Request.Context(), c.Query("status"))
+	if err != nil {
+		s.respondDraftError(c, err, "Failed to list drafts")
+		return
+	}
+	if !s.authorizer.CanReadSecrets(auth.BearerToken(c.GetHeader("Authorization"))) {
+		for i, draft := range drafts {
+			drafts[i] = draft.Masked()
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{"drafts": drafts})
+}
+
+// getDraft 获取草稿
+func (s *Server) getDraft(c *gin.Context) {
+	draft, err := s.configService.GetDraft(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		s.respondDraftError(c, err, "Failed to get draft")
+		return
+	}
+	s.respondDraft(c, draft)
+}
+
+// updateDraft 修改草稿并以当前配置为基准, 需要作者或审批人的令牌, 已有的审批作废。
+// description 为空时保持不变, operations 为空时保留原有的操作, 可用于发布冲突后重新基于当前配置
+func (s *Server) updateDraft(c *gin.Context) {
+	var req struct {
+		Description string                `json:"description"`
+		Operations  []etcd.DraftOperation `json:"operations"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	editor, ok := s.member(c, "update drafts")
+	if !ok {
+		return
+	}
+
+	draft, err := s.configService.UpdateDraft(c.Request.Context(), c.Param("id"), editor, req.Description, req.Operations)
+	if err != nil {
+		s.respondDraftError(c, err, "Failed to update draft")
+		return
+	}
+	s.respondDraft(c, draft)
+}
+
+// draftDiff 比较草稿涉及的每个服务当前的配置与发布后的配置
+//
+// 查询参数: format 输出格式(json、unified), 默认为 json, context 统一格式中的上下文行数, 默认为 3
+func (s *Server) draftDiff(c *gin.Context) {
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "unified" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be json or unified"})
+		return
+	}
+	context := diff.DefaultContext
+	if v := c.Query("context"); v != "" {
+		var err error
+		if context, err = strconv.Atoi(v); err != nil || context < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "context must be a non-negative integer"})
+			return
+		}
+	}
+
+	draft, err := s.configService.GetDraft(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		s.respondDraftError(c, err, "Failed to get draft")
+		return
+	}
+	results, err := s.differ.DraftDiff(c.Request.Context(), draft)
+	if err != nil {
+		s.respondDraftError(c, err, "Failed to diff draft")
+		return
+	}
+	if !s.authorizer.CanReadSecrets(auth.BearerToken(c.GetHeader("Authorization"))) {
+		for i, result := range results {
+			results[i] = result.Masked()
+		}
+	}
+
+	if format == "unified" {
+		var buf strings.Builder
+		for _, result := range results {
+			if err := result.Unified(&buf, context); err != nil {
+				respondError(c, err, "Failed to render diff")
+				return
+			}
+		}
+		c.String(http.StatusOK, buf.String())
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"id": draft.ID, "status": draft.Status, "diffs": results})
+}
+
+// approveDraft 审批草稿, 需要审批人的令牌, 草稿的作者与修改过草稿的人不能审批
+func (s *Server) approveDraft(c *gin.Context) {
+	approver, ok := s.authorizer.Approver(auth.BearerToken(c.GetHeader("Authorization")))
+	if !ok {
+		c.JSON(http.StatusForbidden, gin.H{"error": "an approver token is required to approve drafts"})
+		return
+	}
+	draft, err := s.configService.ApproveDraft(c.Request.Context(), c.Param("id"), approver)
+	if err != nil {
+		s.respondDraftError(c, err, "Failed to approve draft")
+		return
+	}
+	s.respondDraft(c, draft)
+}
+
+// publishDraft 发布草稿, 需要审批人的令牌, 所有操作在一个事务中写入
+func (s *Server) publishDraft(c *gin.Context) {
+	publisher, ok := s.authorizer.Approver(auth.BearerToken(c.GetHeader("Authorization")))
+	if !ok {
+		c.JSON(http.StatusForbidden, gin.H{"error": "an approver token is required to publish drafts"})
+		return
+	}
+	draft, err := s.configService.PublishDraft(c.Request.Context(), c.Param("id"), publisher)
+	if err != nil {
+		s.respondDraftError(c, err, "Failed to publish draft")
+		return
+	}
+	s.respondDraft(c, draft)
+}
+
+// abandonDraft 放弃草稿, 需要作者或审批人的令牌
+func (s *Server) abandonDraft(c *gin.Context) {
+	by, ok := s.member(c, "abandon drafts")
+	if !ok {
+		return
+	}
+	draft, err := s.configService.AbandonDraft(c.Request.Context(), c.Param("id"), by)
+	if err != nil {
+		s.respondDraftError(c, err, "Failed to abandon draft")
+		return
+	}
+	s.respondDraft(c, draft)
+}
+
+// member 返回令牌对应的作者或审批人的名称, 令牌不属于任何成员时返回403
+func (s *Server) member(c *gin.Context, action string) (string, bool) {
+	name, ok := s.authorizer.Member(auth.BearerToken(c.GetHeader("Authorization")))
+	if !ok {
+		c.JSON(http.StatusForbidden, gin.H{"error": "an author or approver token is required to " + action})
+	}
+	return name, ok
+}
+
+// respondDraft 返回草稿, 未授权的调用方看到的敏感配置值被隐藏
+func (s *Server) respondDraft(c *gin.Context, draft *etcd.Draft) {
+	if !s.authorizer.CanReadSecrets(auth.BearerToken(c.GetHeader("Authorization"))) {
+		draft = draft.Masked()
+	}
+	c.JSON(http.StatusOK, draft)
+}
+
+// respondDraftError 返回草稿相关的错误
+func (s *Server) respondDraftError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, etcd.ErrInvalidDraft) || errors.Is(err, etcd.ErrInvalidServiceName):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, etcd.ErrDraftNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, etcd.ErrDraftClosed) || errors.Is(err, etcd.ErrDraftNotApproved) ||
+		errors.Is(err, etcd.ErrDraftConflict):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, etcd.ErrSelfApproval):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	default:
+		s.logger.Error(message, zap.Error(err))
+		respondError(c, err, message)
+	}
+}
@@ -0,0 +1,116 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"nidavellir/internal/auth"
+	"nidavellir/internal/etcd"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// setServiceMeta 创建或更新服务元数据, 修改 protected 标记需要审批人的令牌。
+// 校验之后元数据被并发修改时返回409
+func (s *Server) setServiceMeta(c *gin.Context) {
+	var req struct {
+		Description string            `json:"description"`
+		Owner       string            `json:"owner"`
+		Contact     string            `json:"contact"`
+		Labels      map[string]string `json:"labels"`
+		// Protected 为空时保持不变
+		Protected *bool `json:"protected"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	current, modRevision, err := s.configService.GetServiceMeta(ctx, c.Param("service"))
+	if err != nil {
+		s.logger.Error("Failed to get service metadata", zap.Error(err))
+		respondError(c, err, "Failed to set service metadata")
+		return
+	}
+	protected := current != nil && current.Protected
+	if req.Protected != nil && *req.Protected != protected {
+		if !s.canChangeProtected(c) {
+			return
+		}
+		protected = *req.Protected
+	}
+
+	meta, err := s.configService.SetServiceMeta(ctx, &etcd.ServiceMeta{
+		Name:        c.Param("service"),
+		Description: req.Description,
+		Owner:       req.Owner,
+		Contact:     req.Contact,
+		Labels:      req.Labels,
+		Protected:   protected,
+	}, modRevision)
+	if err != nil {
+		s.logger.Error("Failed to set service metadata", zap.Error(err))
+		respondError(c, err, "Failed to set service metadata")
+		return
+	}
+
+	c.JSON(http.StatusOK, meta)
+}
+
+// getServiceMeta 获取服务元数据与统计信息
+func (s *Server) getServiceMeta(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	info, err := s.configService.GetServiceInfo(ctx, c.Param("service"), linearizable(c))
+	if err != nil {
+		s.logger.Error("Failed to get service metadata", zap.Error(err))
+		respondError(c, err, "Failed to get service metadata")
+		return
+	}
+	if info == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Service not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, info)
+}
+
+// deleteServiceMeta 删除服务元数据, 服务的配置不受影响
+func (s *Server) deleteServiceMeta(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// 删除元数据会同时取消保护
+	current, modRevision, err := s.configService.GetServiceMeta(ctx, c.Param("service"))
+	if err != nil {
+		s.logger.Error("Failed to get service metadata", zap.Error(err))
+		respondError(c, err, "Failed to delete service metadata")
+		return
+	}
+	if current != nil && current.Protected && !s.canChangeProtected(c) {
+		return
+	}
+
+	if err := s.configService.DeleteServiceMeta(ctx, c.Param("service"), modRevision); err != nil {
+		s.logger.Error("Failed to delete service metadata", zap.Error(err))
+		respondError(c, err, "Failed to delete service metadata")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Service metadata deleted successfully"})
+}
+
+// canChangeProtected 修改服务的 protected 标记需要审批人的令牌, 否则返回403
+func (s *Server) canChangeProtected(c *gin.Context) bool {
+	if _, ok := s.authorizer.Approver(auth.BearerToken(c.GetHeader("Authorization"))); ok {
+		return true
+	}
+	c.JSON(http.StatusForbidden, gin.H{"error": "an approver token is required to change the protected flag"})
+	return false
+}
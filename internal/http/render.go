@@ -0,0 +1,108 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"nidavellir/internal/render"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// acceptFormats 可以通过 Accept 头协商的输出格式
+var acceptFormats = map[string]string{
+	"application/yaml":       render.FormatYAML,
+	"application/x-yaml":     render.FormatYAML,
+	"text/yaml":              render.FormatYAML,
+	"text/x-yaml":            render.FormatYAML,
+	"application/toml":       render.FormatTOML,
+	"text/x-java-properties": render.FormatProperties,
+}
+
+// renderFormat 从 format 查询参数或 Accept 头确定输出格式, 都未指定时返回空串
+func renderFormat(c *gin.Context) string {
+	if format := c.Query("format"); format != "" {
+		return format
+	}
+	for _, part := range strings.Split(c.GetHeader("Accept"), ",") {
+		mediaType, _, _ := strings.Cut(part, ";")
+		if format, ok := acceptFormats[strings.ToLower(strings.TrimSpace(mediaType))]; ok {
+			return format
+		}
+	}
+	return ""
+}
+
+// renderServiceConfigs 将服务的全部配置渲染为指定格式
+//
+// 查询参数 key_case 指定键名转换(upper_snake、lower_snake), prefix 为转换后添加的前缀
+func (s *Server) renderServiceConfigs(c *gin.Context, service, format string) {
+	if !render.Valid(format) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "unknown format " + format,
+			"formats": render.Formats(),
+		})
+		return
+	}
+	keyCase, err := render.ParseCase(c.Query("key_case"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	configs, err := s.configService.GetServiceConfigs(ctx, service, linearizable(c))
+	if err != nil {
+		s.logger.Error("Failed to get service configs", zap.Error(err))
+		respondError(c, err, "Failed to get service configs")
+		return
+	}
+
+	entries := make([]render.Entry, 0, len(configs))
+	for key, configItem := range configs {
+		entries = append(entries, render.Entry{Key: key, Value: configItem.Value})
+	}
+
+	var buf bytes.Buffer
+	err = render.Render(&buf, format, entries, render.KeyOptions{Case: keyCase, Prefix: c.Query("prefix")})
+	if errors.Is(err, render.ErrInvalidKey) || errors.Is(err, render.ErrDuplicateKey) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		s.logger.Error("Failed to render service configs", zap.String("format", format), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to render service configs"})
+		return
+	}
+
+	c.Data(http.StatusOK, render.ContentType(format), buf.Bytes())
+}
+
+// getRawConfig 以纯文本返回配置值, 字符串原样返回, 其他值返回紧凑的JSON
+func (s *Server) getRawConfig(c *gin.Context) {
+	service := c.Param("service")
+	key := c.Param("key")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	configItem, err := s.configService.GetConfig(ctx, service, key, linearizable(c))
+	if err != nil {
+		s.logger.Error("Failed to get config", zap.Error(err))
+		respondError(c, err, "Failed to get config")
+		return
+	}
+	if configItem == nil {
+		c.String(http.StatusNotFound, "config not found\n")
+		return
+	}
+
+	c.Data(http.StatusOK, "text/plain; charset=utf-8", []byte(render.Text(configItem.Value)))
+}
@@ -0,0 +1,73 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"nidavellir/internal/auth"
+	"nidavellir/internal/etcd"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// search 搜索配置的键、值与描述
+//
+// 查询参数: q 子串或正则表达式, regex 按正则匹配, ignore_case 忽略大小写,
+// field 匹配的字段(可重复或逗号分隔), service 服务名(可重复, 支持通配符),
+// label 服务标签 name=value(可重复), limit 最多返回的结果数。
+// 携带允许读取敏感配置的令牌时敏感配置的值参与匹配, 否则其值不参与匹配且在结果中隐藏
+func (s *Server) search(c *gin.Context) {
+	opts := etcd.SearchOptions{
+		Query:          c.Query("q"),
+		Services:       c.QueryArray("service"),
+		IncludeSecrets: s.authorizer.CanReadSecrets(auth.BearerToken(c.GetHeader("Authorization"))),
+	}
+	if opts.Query == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "q is required"})
+		return
+	}
+	opts.Regex, _ = strconv.ParseBool(c.Query("regex"))
+	opts.IgnoreCase, _ = strconv.ParseBool(c.Query("ignore_case"))
+	for _, value := range c.QueryArray("field") {
+		for _, field := range strings.Split(value, ",") {
+			if field = strings.TrimSpace(field); field != "" {
+				opts.Fields = append(opts.Fields, field)
+			}
+		}
+	}
+	for _, value := range c.QueryArray("label") {
+		name, labelValue, ok := strings.Cut(value, "=")
+		if !ok || name == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "label must be in the form name=value"})
+			return
+		}
+		if opts.Labels == nil {
+			opts.Labels = make(map[string]string)
+		}
+		opts.Labels[name] = labelValue
+	}
+	if raw := c.Query("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a non-negative integer"})
+			return
+		}
+		opts.Limit = limit
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	result, err := s.configService.Search(ctx, opts, linearizable(c))
+	if err != nil {
+		s.logger.Error("Failed to search configs", zap.Error(err))
+		respondError(c, err, "Failed to search configs")
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,37 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"nidavellir/internal/etcd"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// reloadSeed 重新读取种子文件(envs.toml)并写入
+//
+// 查询参数: mode 写入模式(skip-if-any、add-missing、force), 为空时使用配置的模式,
+// dry_run 只返回将要新建、覆盖的配置以及与已有值冲突的配置, 不写入
+func (s *Server) reloadSeed(c *gin.Context) {
+	dryRun, _ := strconv.ParseBool(c.Query("dry_run"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	plan, err := s.seeder.Reload(ctx, c.Query("mode"), dryRun)
+	if err != nil {
+		if errors.Is(err, etcd.ErrSeedConflict) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		s.logger.Error("failed to reload seed configs", zap.Error(err))
+		respondError(c, err, "Failed to reload seed configs")
+		return
+	}
+	c.JSON(http.StatusOK, plan)
+}
@@ -2,12 +2,20 @@ package http
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
+	"sync"
 	"time"
 
+	"nidavellir/internal/auth"
+	"nidavellir/internal/backup"
+	"nidavellir/internal/clients"
 	"nidavellir/internal/config"
+	"nidavellir/internal/diff"
 	"nidavellir/internal/etcd"
+	"nidavellir/internal/watch"
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
@@ -17,20 +25,37 @@ import (
 type Server struct {
 	server        *http.Server
 	configService *etcd.ConfigService
+	seeder        *etcd.Seeder
+	backups       *backup.Manager
+	differ        *diff.Resolver
+	hub           *watch.Hub
+	registry      *clients.Registry
+	authorizer    *auth.Authorizer
 	logger        *zap.Logger
+	// done 关闭时通知长连接(SSE)退出
+	done      chan struct{}
+	closeOnce sync.Once
 }
 
 // NewServer 创建HTTP服务器
-func NewServer(cfg config.HTTPConfig, configService *etcd.ConfigService, logger *zap.Logger) *Server {
+func NewServer(cfg config.HTTPConfig, configService *etcd.ConfigService, seeder *etcd.Seeder, backups *backup.Manager, differ *diff.Resolver, hub *watch.Hub, registry *clients.Registry, authorizer *auth.Authorizer, logger *zap.Logger) *Server {
 	gin.SetMode(gin.ReleaseMode)
 	router := gin.New()
 	router.Use(gin.Recovery())
 	router.Use(corsMiddleware())
 	router.Use(loggingMiddleware(logger))
+	router.Use(degradedMiddleware(configService))
 
 	s := &Server{
 		configService: configService,
+		seeder:        seeder,
+		backups:       backups,
+		differ:        differ,
+		hub:           hub,
+		registry:      registry,
+		authorizer:    authorizer,
 		logger:        logger,
+		done:          make(chan struct{}),
 	}
 
 	// 注册路由
@@ -50,8 +75,11 @@ func (s *Server) Start() error {
 	return s.server.ListenAndServe()
 }
 
-// Shutdown 关闭HTTP服务器
+// Shutdown 关闭HTTP服务器, 先通知SSE连接退出再等待请求结束
 func (s *Server) Shutdown(ctx context.Context) error {
+	s.closeOnce.Do(func() {
+		close(s.done)
+	})
 	return s.server.Shutdown(ctx)
 }
 
@@ -69,8 +97,14 @@ func (s *Server) registerRoutes(router *gin.Engine) {
 			configs.PUT("/:service/:key", s.setConfig)
 			// 获取配置
 			configs.GET("/:service/:key", s.getConfig)
+			// 以纯文本获取配置值
+			configs.GET("/:service/:key/raw", s.getRawConfig)
 			// 获取服务所有配置
 			configs.GET("/:service", s.getServiceConfigs)
+			// 监听服务所有配置(SSE)
+			configs.GET("/:service/watch", s.watchServiceConfigs)
+			// 监听配置(SSE)
+			configs.GET("/:service/:key/watch", s.watchConfig)
 			// 删除配置
 			configs.DELETE("/:service/:key", s.deleteConfig)
 			// 删除服务所有配置
@@ -79,18 +113,111 @@ func (s *Server) registerRoutes(router *gin.Engine) {
 
 		// 服务管理
 		api.GET("/services", s.listServices)
+		// 服务元数据
+		api.GET("/services/:service", s.getServiceMeta)
+		api.PUT("/services/:service", s.setServiceMeta)
+		api.DELETE("/services/:service", s.deleteServiceMeta)
+
+		// 搜索配置
+		api.GET("/search", s.search)
+		// 比较服务配置
+		api.GET("/diff", s.diffConfigs)
+
+		// 按订阅条件监听多个服务(SSE)
+		api.GET("/watch", s.watchSelectors)
+		// 监听分发统计
+		api.GET("/watch/stats", s.watchStats)
+		// 配置缓存统计
+		api.GET("/cache/stats", s.cacheStats)
+
+		// 已连接的客户端
+		api.GET("/clients", s.listClients)
+		// 版本落后的客户端
+		api.GET("/clients/drift", s.listDriftClients)
+
+		// 导出所有服务的配置与元数据
+		api.GET("/export", s.exportConfigs)
+
+		// 管理接口, 会覆盖线上配置, 需要审批人的令牌
+		admin := api.Group("/admin", s.requireApprover)
+		{
+			// 重新加载种子配置(envs.toml)
+			admin.POST("/seed", s.reloadSeed)
+
+			// 导入归档, replace 模式会删除归档中没有的配置
+			admin.POST("/import", s.importConfigs)
+
+			// 本地备份
+			admin.GET("/backups", s.listBackups)
+			admin.POST("/backups", s.createBackup)
+			admin.POST("/backups/:name/restore", s.restoreBackup)
+		}
+
+		// 草稿: 审批后原子发布的一组配置修改
+		drafts := api.Group("/drafts")
+		{
+			drafts.GET("", s.listDrafts)
+			drafts.POST("", s.createDraft)
+			drafts.GET("/:id", s.getDraft)
+			drafts.PUT("/:id", s.updateDraft)
+			drafts.GET("/:id/diff", s.draftDiff)
+			drafts.POST("/:id/approve", s.approveDraft)
+			drafts.POST("/:id/publish", s.publishDraft)
+			drafts.POST("/:id/abandon", s.abandonDraft)
+		}
 	}
 }
 
 // healthCheck 健康检查
 func (s *Server) healthCheck(c *gin.Context) {
+	status := "ok"
+	if s.configService.Status().Degraded {
+		status = "degraded"
+	}
 	c.JSON(http.StatusOK, gin.H{
-		"status":    "ok",
+		"status":    status,
 		"timestamp": time.Now().Unix(),
 		"service":   "Nidavellir",
+		"etcd":      s.configService.Status(),
 	})
 }
 
+// watchStats 获取监听分发中心的订阅者数量与滞后情况
+func (s *Server) watchStats(c *gin.Context) {
+	c.JSON(http.StatusOK, s.hub.Stats())
+}
+
+// listClients 列出已连接的客户端及其版本漂移情况
+func (s *Server) listClients(c *gin.Context) {
+	s.respondClients(c, false)
+}
+
+// listDriftClients 列出版本落后于订阅范围内最新版本的客户端
+func (s *Server) listDriftClients(c *gin.Context) {
+	s.respondClients(c, true)
+}
+
+// respondClients 计算客户端的漂移情况并返回
+func (s *Server) respondClients(c *gin.Context, driftOnly bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	latest, revision, err := clients.LoadLatest(ctx, s.configService, s.hub)
+	if err != nil {
+		s.logger.Error("Failed to load latest revisions", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list clients"})
+		return
+	}
+
+	var infos []clients.Info
+	if driftOnly {
+		infos = s.registry.Drift(latest)
+	} else {
+		infos = s.registry.List(latest)
+	}
+	c.JSON(http.StatusOK, gin.H{"revision": revision, "clients": infos})
+}
+
 // setConfig 设置配置
 func (s *Server) setConfig(c *gin.Context) {
 	service := c.Param("service")
@@ -99,6 +226,7 @@ func (s *Server) setConfig(c *gin.Context) {
 	var req struct {
 		Value       interface{} `json:"value" binding:"required"`
 		Description string      `json:"description"`
+		Encrypt     bool        `json:"encrypt"`
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -109,9 +237,9 @@ func (s *Server) setConfig(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	if err := s.configService.SetConfig(ctx, service, key, req.Value, req.Description); err != nil {
+	if err := s.configService.SetConfig(ctx, service, key, req.Value, req.Description, req.Encrypt); err != nil {
 		s.logger.Error("Failed to set config", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to set config"})
+		respondError(c, err, "Failed to set config")
 		return
 	}
 
@@ -126,10 +254,10 @@ func (s *Server) getConfig(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	configItem, err := s.configService.GetConfig(ctx, service, key)
+	configItem, err := s.configService.GetConfig(ctx, service, key, linearizable(c))
 	if err != nil {
 		s.logger.Error("Failed to get config", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get config"})
+		respondError(c, err, "Failed to get config")
 		return
 	}
 
@@ -141,21 +269,46 @@ func (s *Server) getConfig(c *gin.Context) {
 	c.JSON(http.StatusOK, configItem)
 }
 
-// getServiceConfigs 获取服务所有配置
+// getServiceConfigs 获取服务所有配置, 指定 format 或通过 Accept 协商时渲染为对应格式
 func (s *Server) getServiceConfigs(c *gin.Context) {
 	service := c.Param("service")
+	if format := renderFormat(c); format != "" {
+		s.renderServiceConfigs(c, service, format)
+		return
+	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	configs, err := s.configService.GetServiceConfigs(ctx, service)
+	pageOpts, ok := pageOptions(c)
+	if !ok {
+		return
+	}
+	page, err := s.configService.GetServiceConfigsPage(ctx, service, pageOpts, linearizable(c))
 	if err != nil {
 		s.logger.Error("Failed to get service configs", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get service configs"})
+		respondError(c, err, "Failed to get service configs")
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"configs": configs})
+	resp := gin.H{}
+	if page.NextPageToken != "" {
+		resp["next_page_token"] = page.NextPageToken
+	}
+	if pageOpts.KeysOnly {
+		keys := make([]string, 0, len(page.Items))
+		for _, configItem := range page.Items {
+			keys = append(keys, configItem.Key)
+		}
+		resp["keys"] = keys
+	} else {
+		configs := make(map[string]*etcd.ConfigItem, len(page.Items))
+		for _, configItem := range page.Items {
+			configs[configItem.Key] = configItem
+		}
+		resp["configs"] = configs
+	}
+	c.JSON(http.StatusOK, resp)
 }
 
 // deleteConfig 删除配置
@@ -168,7 +321,7 @@ func (s *Server) deleteConfig(c *gin.Context) {
 
 	if err := s.configService.DeleteConfig(ctx, service, key); err != nil {
 		s.logger.Error("Failed to delete config", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete config"})
+		respondError(c, err, "Failed to delete config")
 		return
 	}
 
@@ -184,7 +337,7 @@ func (s *Server) deleteServiceConfigs(c *gin.Context) {
 
 	if err := s.configService.DeleteServiceConfigs(ctx, service); err != nil {
 		s.logger.Error("Failed to delete service configs", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete service configs"})
+		respondError(c, err, "Failed to delete service configs")
 		return
 	}
 
@@ -196,14 +349,101 @@ func (s *Server) listServices(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	services, err := s.configService.ListServices(ctx)
+	pageOpts, ok := pageOptions(c)
+	if !ok {
+		return
+	}
+	page, err := s.configService.ListServicesPage(ctx, pageOpts, linearizable(c))
 	if err != nil {
 		s.logger.Error("Failed to list services", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list services"})
+		respondError(c, err, "Failed to list services")
+		return
+	}
+
+	resp := gin.H{"services": page.Services}
+	if page.NextPageToken != "" {
+		resp["next_page_token"] = page.NextPageToken
+	}
+	if withMetadata, _ := strconv.ParseBool(c.Query("metadata")); withMetadata {
+		infos, err := s.configService.ServiceInfos(ctx, page.Services, linearizable(c))
+		if err != nil {
+			s.logger.Error("Failed to get service metadata", zap.Error(err))
+			respondError(c, err, "Failed to get service metadata")
+			return
+		}
+		resp["details"] = infos
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// cacheStats 获取配置缓存的同步版本与命中情况
+func (s *Server) cacheStats(c *gin.Context) {
+	c.JSON(http.StatusOK, s.configService.CacheStats())
+}
+
+// linearizable 解析 linearizable 查询参数, 为true时绕过缓存读取
+func linearizable(c *gin.Context) etcd.ReadOption {
+	value, _ := strconv.ParseBool(c.Query("linearizable"))
+	return etcd.Linearizable(value)
+}
+
+// pageOptions 解析 limit、page_token 与 keys_only 查询参数, 参数无效时输出400并返回false
+func pageOptions(c *gin.Context) (etcd.PageOptions, bool) {
+	opts := etcd.PageOptions{PageToken: c.Query("page_token")}
+	if raw := c.Query("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a non-negative integer"})
+			return opts, false
+		}
+		opts.Limit = limit
+	}
+	opts.KeysOnly, _ = strconv.ParseBool(c.Query("keys_only"))
+	return opts, true
+}
+
+// requireApprover 中间件, 要求令牌属于审批人, 否则返回403
+func (s *Server) requireApprover(c *gin.Context) {
+	if _, ok := s.authorizer.Approver(auth.BearerToken(c.GetHeader("Authorization"))); !ok {
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "an approver token is required for admin endpoints"})
 		return
 	}
+	c.Next()
+}
 
-	c.JSON(http.StatusOK, gin.H{"services": services})
+// respondError 输出请求失败的响应, 分页参数、搜索条件、服务名或种子写入模式无效时返回400, etcd不可用(降级模式)时返回503与具体原因, 服务受保护时返回403, 写入期间服务元数据被修改时返回409
+func respondError(c *gin.Context, err error, message string) {
+	if errors.Is(err, etcd.ErrInvalidPage) || errors.Is(err, etcd.ErrInvalidSearch) ||
+		errors.Is(err, etcd.ErrInvalidServiceName) || errors.Is(err, etcd.ErrInvalidSeedMode) ||
+		errors.Is(err, etcd.ErrInvalidArchive) || errors.Is(err, etcd.ErrInvalidImport) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, etcd.ErrReadOnly) || errors.Is(err, etcd.ErrUnavailable) {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, etcd.ErrProtected) {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, etcd.ErrMetaConflict) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+}
+
+// degradedMiddleware 降级期间在响应头中标记数据可能过期
+func degradedMiddleware(configService *etcd.ConfigService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if status := configService.Status(); status.Degraded {
+			c.Header("X-Nidavellir-Degraded", "true")
+			c.Header("X-Nidavellir-Stale-Since", strconv.FormatInt(status.Since, 10))
+			c.Header("X-Nidavellir-Revision", strconv.FormatInt(status.Revision, 10))
+		}
+		c.Next()
+	}
 }
 
 // corsMiddleware CORS中间件
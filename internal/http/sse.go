@@ -0,0 +1,195 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"nidavellir/internal/clients"
+	"nidavellir/internal/etcd"
+	"nidavellir/internal/watch"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// sseHeartbeatInterval SSE心跳间隔
+const sseHeartbeatInterval = 15 * time.Second
+
+// sseEvent SSE事件数据
+type sseEvent struct {
+	Type        string      `json:"type"`
+	Service     string      `json:"service"`
+	Key         string      `json:"key"`
+	Value       interface{} `json:"value,omitempty"`
+	PrevValue   interface{} `json:"prev_value,omitempty"`
+	Description string      `json:"description,omitempty"`
+	Revision    int64       `json:"revision"`
+}
+
+// watchServiceConfigs 以SSE方式监听服务所有配置
+func (s *Server) watchServiceConfigs(c *gin.Context) {
+	s.streamWatch(c, watch.NewFilter(c.Param("service"), ""))
+}
+
+// watchConfig 以SSE方式监听单个配置
+func (s *Server) watchConfig(c *gin.Context) {
+	s.streamWatch(c, watch.NewFilter(c.Param("service"), c.Param("key")))
+}
+
+// watchSelectors 以SSE方式按订阅条件监听多个服务, 如 ?selector=Palace&selector=Heimdallr/Job*,
+// 未指定时监听所有服务
+func (s *Server) watchSelectors(c *gin.Context) {
+	var filter watch.Filter
+	for _, value := range c.QueryArray("selector") {
+		selector, err := watch.ParseSelector(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		filter.Selectors = append(filter.Selectors, selector)
+	}
+	s.streamWatch(c, filter)
+}
+
+// streamWatch 推送配置变更事件, 支持通过Last-Event-ID从指定版本之后恢复
+func (s *Server) streamWatch(c *gin.Context, filter watch.Filter) {
+	startRevision, err := lastEventRevision(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Last-Event-ID"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	watchChan := s.hub.Watch(ctx, filter, startRevision)
+
+	name := c.GetHeader("X-Client-Name")
+	if name == "" {
+		name = c.Request.UserAgent()
+	}
+	client := s.registry.Register(clients.Identity{
+		Kind:      clients.KindSSE,
+		Name:      name,
+		Transport: clients.TransportHTTP,
+		Peer:      c.ClientIP(),
+	}, filter.Strings())
+	defer s.registry.Unregister(client)
+	if startRevision > 0 {
+		client.Delivered(startRevision - 1)
+	} else {
+		client.Delivered(s.hub.Revision())
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	// 立即发送一次心跳, 让客户端确认连接已建立
+	s.writeSSEComment(c, "connected")
+
+	heartbeat := time.NewTicker(sseHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.done:
+			s.writeSSE(c, "", "shutdown", gin.H{"message": "server shutting down"})
+			return
+		case <-heartbeat.C:
+			s.writeSSEComment(c, "heartbeat "+strconv.FormatInt(time.Now().Unix(), 10))
+		case resp, ok := <-watchChan:
+			if !ok {
+				return
+			}
+			if resp.CompactRevision > 0 {
+				// 请求的版本已被压缩, 客户端需要全量同步
+				s.writeSSE(c, "", "reset", gin.H{
+					"compact_revision": resp.CompactRevision,
+					"revision":         resp.Revision,
+				})
+				return
+			}
+			if errors.Is(resp.Err, watch.ErrSlowConsumer) || errors.Is(resp.Err, watch.ErrResync) {
+				// 客户端按Last-Event-ID重连即可恢复
+				s.writeSSE(c, "", "error", gin.H{"error": resp.Err.Error()})
+				return
+			}
+			if resp.Err != nil {
+				s.logger.Error("SSE watch failed", zap.Strings("filter", filter.Strings()), zap.Error(resp.Err))
+				s.writeSSE(c, "", "error", gin.H{"error": "watch failed"})
+				return
+			}
+			s.writeWatchEvents(c, resp.Events)
+			client.Delivered(resp.Revision)
+		}
+	}
+}
+
+// writeWatchEvents 发送一组变更事件, 仅在同一版本的最后一个事件上携带id,
+// 保证按Last-Event-ID恢复时不会遗漏同一版本内的其他事件
+func (s *Server) writeWatchEvents(c *gin.Context, events []*etcd.ConfigEvent) {
+	for i, event := range events {
+		id := ""
+		if i == len(events)-1 || events[i+1].Revision != event.Revision {
+			id = strconv.FormatInt(event.Revision, 10)
+		}
+
+		data := sseEvent{
+			Type:     event.Type,
+			Service:  event.ServiceName,
+			Key:      event.Key,
+			Revision: event.Revision,
+		}
+		if event.Item != nil {
+			data.Value = event.Item.Value
+			data.Description = event.Item.Description
+		}
+		if event.PrevItem != nil {
+			data.PrevValue = event.PrevItem.Value
+		}
+		s.writeSSE(c, id, event.Type, data)
+	}
+}
+
+// writeSSE 写入一条SSE事件
+func (s *Server) writeSSE(c *gin.Context, id, event string, data interface{}) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		s.logger.Error("Failed to marshal SSE event", zap.Error(err))
+		return
+	}
+	if id != "" {
+		fmt.Fprintf(c.Writer, "id: %s\n", id)
+	}
+	fmt.Fprintf(c.Writer, "event: %s\ndata: %s\n\n", event, payload)
+	c.Writer.Flush()
+}
+
+// writeSSEComment 写入SSE注释行, 用于心跳
+func (s *Server) writeSSEComment(c *gin.Context, comment string) {
+	fmt.Fprintf(c.Writer, ": %s\n\n", comment)
+	c.Writer.Flush()
+}
+
+// lastEventRevision 根据Last-Event-ID计算恢复的起始版本
+func lastEventRevision(c *gin.Context) (int64, error) {
+	id := c.GetHeader("Last-Event-ID")
+	if id == "" {
+		id = c.Query("last_event_id")
+	}
+	if id == "" {
+		return 0, nil
+	}
+
+	revision, err := strconv.ParseInt(id, 10, 64)
+	if err != nil || revision < 0 {
+		return 0, fmt.Errorf("invalid last event id %q", id)
+	}
+	return revision + 1, nil
+}
@@ -0,0 +1,90 @@
+package render
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// 键名转换方式
+const (
+	// CaseNone 保持原样
+	CaseNone = ""
+	// CaseUpperSnake 转换为大写下划线形式, 如 dbHost、db.host 均转换为 DB_HOST
+	CaseUpperSnake = "upper_snake"
+	// CaseLowerSnake 转换为小写下划线形式, 如 DBHost 转换为 db_host
+	CaseLowerSnake = "lower_snake"
+)
+
+// KeyOptions 键名转换选项
+type KeyOptions struct {
+	// Case 键名转换方式
+	Case string
+	// Prefix 转换后添加的前缀, 原样添加
+	Prefix string
+}
+
+// ParseCase 解析键名转换方式, 同时接受 upper-snake 形式, none 等同于不转换
+func ParseCase(s string) (string, error) {
+	switch c := strings.ReplaceAll(strings.ToLower(s), "-", "_"); c {
+	case CaseNone, "none":
+		return CaseNone, nil
+	case CaseUpperSnake, CaseLowerSnake:
+		return c, nil
+	default:
+		return "", fmt.Errorf("%w: unknown key case %q (upper_snake, lower_snake)", ErrInvalidKey, s)
+	}
+}
+
+// Transform 转换配置键
+func (o KeyOptions) Transform(key string) (string, error) {
+	switch o.Case {
+	case CaseNone:
+	case CaseUpperSnake:
+		key = strings.ToUpper(snake(key))
+	case CaseLowerSnake:
+		key = strings.ToLower(snake(key))
+	default:
+		return "", fmt.Errorf("%w: unknown key case %q", ErrInvalidKey, o.Case)
+	}
+	key = o.Prefix + key
+	if key == "" {
+		return "", fmt.Errorf("%w: empty key", ErrInvalidKey)
+	}
+	return key, nil
+}
+
+// snake 按非字母数字字符与大小写边界拆分单词并以下划线连接, 连续的大写视为一个单词(HTTPServer -> HTTP_Server)
+func snake(key string) string {
+	runes := []rune(key)
+	words := make([]string, 0, 4)
+	var word []rune
+	flush := func() {
+		if len(word) > 0 {
+			words = append(words, string(word))
+			word = word[:0]
+		}
+	}
+
+	for i, r := range runes {
+		if !isAlnum(r) {
+			flush()
+			continue
+		}
+		if unicode.IsUpper(r) && len(word) > 0 {
+			prev := word[len(word)-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				flush()
+			}
+		}
+		word = append(word, r)
+	}
+	flush()
+	return strings.Join(words, "_")
+}
+
+// isAlnum 判断是否为ASCII字母或数字
+func isAlnum(r rune) bool {
+	return (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9')
+}
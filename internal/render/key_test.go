@@ -0,0 +1,93 @@
+package render
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSnake(t *testing.T) {
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{key: "", want: ""},
+		{key: "host", want: "host"},
+		{key: "dbHost", want: "db_Host"},
+		{key: "db.host", want: "db_host"},
+		{key: "db-host_name", want: "db_host_name"},
+		{key: "DBHost", want: "DB_Host"},
+		{key: "HTTPServer", want: "HTTP_Server"},
+		{key: "getHTTPResponseCode", want: "get_HTTP_Response_Code"},
+		{key: "ID", want: "ID"},
+		{key: "userID", want: "user_ID"},
+		{key: "v2Api", want: "v2_Api"},
+		{key: "oauth2Client", want: "oauth2_Client"},
+		{key: "..a..b..", want: "a_b"},
+		{key: "a b;c\nd", want: "a_b_c_d"},
+		{key: "名称", want: ""},
+	}
+
+	for _, tt := range tests {
+		if got := snake(tt.key); got != tt.want {
+			t.Errorf("snake(%q) = %q, want %q", tt.key, got, tt.want)
+		}
+	}
+}
+
+func TestTransform(t *testing.T) {
+	tests := []struct {
+		key     string
+		options KeyOptions
+		want    string
+		wantErr bool
+	}{
+		{key: "dbHost", options: KeyOptions{}, want: "dbHost"},
+		{key: "dbHost", options: KeyOptions{Case: CaseUpperSnake}, want: "DB_HOST"},
+		{key: "db.host", options: KeyOptions{Case: CaseUpperSnake}, want: "DB_HOST"},
+		{key: "DBHost", options: KeyOptions{Case: CaseLowerSnake}, want: "db_host"},
+		{key: "port", options: KeyOptions{Case: CaseUpperSnake, Prefix: "APP_"}, want: "APP_PORT"},
+		{key: "名称", options: KeyOptions{Case: CaseUpperSnake}, wantErr: true},
+		{key: "port", options: KeyOptions{Case: "camel"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := tt.options.Transform(tt.key)
+		if tt.wantErr {
+			if !errors.Is(err, ErrInvalidKey) {
+				t.Errorf("Transform(%q, %+v) error = %v, want ErrInvalidKey", tt.key, tt.options, err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("Transform(%q, %+v) = %q, %v, want %q", tt.key, tt.options, got, err, tt.want)
+		}
+	}
+}
+
+func TestParseCase(t *testing.T) {
+	tests := []struct {
+		s       string
+		want    string
+		wantErr bool
+	}{
+		{s: "", want: CaseNone},
+		{s: "none", want: CaseNone},
+		{s: "upper_snake", want: CaseUpperSnake},
+		{s: "Upper-Snake", want: CaseUpperSnake},
+		{s: "lower_snake", want: CaseLowerSnake},
+		{s: "kebab", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseCase(tt.s)
+		if tt.wantErr {
+			if !errors.Is(err, ErrInvalidKey) {
+				t.Errorf("ParseCase(%q) error = %v, want ErrInvalidKey", tt.s, err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("ParseCase(%q) = %q, %v, want %q", tt.s, got, err, tt.want)
+		}
+	}
+}
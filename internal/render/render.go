@@ -0,0 +1,327 @@
+// Package render 将服务配置渲染为环境变量文件、YAML、TOML、JSON、properties 等格式
+package render
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// 支持的输出格式
+const (
+	FormatDotenv     = "dotenv"
+	FormatExport     = "export"
+	FormatYAML       = "yaml"
+	FormatTOML       = "toml"
+	FormatJSON       = "json"
+	FormatProperties = "properties"
+	FormatSystemd    = "systemd"
+)
+
+var (
+	// ErrUnknownFormat 不支持的输出格式
+	ErrUnknownFormat = errors.New("unknown render format")
+	// ErrInvalidKey 转换后的键不能用作该格式的变量名
+	ErrInvalidKey = errors.New("invalid key for render format")
+	// ErrDuplicateKey 不同的配置键转换后相同
+	ErrDuplicateKey = errors.New("duplicate key after transform")
+)
+
+// Entry 待渲染的配置项
+type Entry struct {
+	Key   string
+	Value interface{}
+}
+
+// Formats 返回所有支持的输出格式
+func Formats() []string {
+	return []string{FormatDotenv, FormatExport, FormatYAML, FormatTOML, FormatJSON, FormatProperties, FormatSystemd}
+}
+
+// Valid 判断是否为支持的输出格式
+func Valid(format string) bool {
+	for _, f := range Formats() {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
+
+// ContentType 输出格式对应的 Content-Type
+func ContentType(format string) string {
+	switch format {
+	case FormatYAML:
+		return "application/yaml; charset=utf-8"
+	case FormatTOML:
+		return "application/toml; charset=utf-8"
+	case FormatJSON:
+		return "application/json; charset=utf-8"
+	default:
+		return "text/plain; charset=utf-8"
+	}
+}
+
+// Render 按键转换后的顺序将配置项渲染为指定格式
+func Render(w io.Writer, format string, entries []Entry, keys KeyOptions) error {
+	if !Valid(format) {
+		return fmt.Errorf("%w: %q", ErrUnknownFormat, format)
+	}
+
+	transformed, err := transformEntries(entries, keys)
+	if err != nil {
+		return err
+	}
+	// 环境变量类的格式通常会被 source 或由 shell 读取, 不合法的键可能注入额外的行或语句
+	if format == FormatDotenv || format == FormatExport || format == FormatSystemd {
+		for _, entry := range transformed {
+			if !isEnvName(entry.Key) {
+				return fmt.Errorf("%w: %q is not a valid environment variable name, try key_case=upper_snake", ErrInvalidKey, entry.Key)
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	switch format {
+	case FormatDotenv:
+		for _, entry := range transformed {
+			fmt.Fprintf(&buf, "%s=%s\n", entry.Key, dotenvQuote(Text(entry.Value)))
+		}
+	case FormatExport:
+		for _, entry := range transformed {
+			fmt.Fprintf(&buf, "export %s=%s\n", entry.Key, shellQuote(Text(entry.Value)))
+		}
+	case FormatSystemd:
+		for _, entry := range transformed {
+			fmt.Fprintf(&buf, "%s=%s\n", entry.Key, systemdQuote(Text(entry.Value)))
+		}
+	case FormatProperties:
+		for _, entry := range transformed {
+			fmt.Fprintf(&buf, "%s=%s\n", propertiesEscape(entry.Key, true), propertiesEscape(Text(entry.Value), false))
+		}
+	case FormatJSON:
+		enc := json.NewEncoder(&buf)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(entryMap(transformed, false)); err != nil {
+			return fmt.Errorf("failed to render json: %w", err)
+		}
+	case FormatYAML:
+		enc := yaml.NewEncoder(&buf)
+		enc.SetIndent(2)
+		if err := enc.Encode(entryMap(transformed, false)); err != nil {
+			return fmt.Errorf("failed to render yaml: %w", err)
+		}
+		if err := enc.Close(); err != nil {
+			return fmt.Errorf("failed to render yaml: %w", err)
+		}
+	case FormatTOML:
+		data, err := toml.Marshal(entryMap(transformed, true))
+		if err != nil {
+			return fmt.Errorf("failed to render toml: %w", err)
+		}
+		buf.Write(data)
+	}
+
+	_, err = w.Write(buf.Bytes())
+	return err
+}
+
+// Env 将配置项转换为 KEY=value 形式的环境变量, 按键排序, 转换后的键必须是合法的环境变量名
+func Env(entries []Entry, keys KeyOptions) ([]string, error) {
+	transformed, err := transformEntries(entries, keys)
+	if err != nil {
+		return nil, err
+	}
+
+	env := make([]string, 0, len(transformed))
+	for _, entry := range transformed {
+		if !isEnvName(entry.Key) {
+			return nil, fmt.Errorf("%w: %q is not a valid environment variable name", ErrInvalidKey, entry.Key)
+		}
+		env = append(env, entry.Key+"="+Text(entry.Value))
+	}
+	return env, nil
+}
+
+// Text 配置值的文本形式, 字符串按原样输出, null为空串, 其他值输出为紧凑的JSON
+func Text(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
+
+// transformEntries 转换键名并按转换后的键排序, 转换后重复时返回错误
+func transformEntries(entries []Entry, keys KeyOptions) ([]Entry, error) {
+	result := make([]Entry, 0, len(entries))
+	origin := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		key, err := keys.Transform(entry.Key)
+		if err != nil {
+			return nil, err
+		}
+		if prev, ok := origin[key]; ok {
+			return nil, fmt.Errorf("%w: %q and %q both become %q", ErrDuplicateKey, prev, entry.Key, key)
+		}
+		origin[key] = entry.Key
+		result = append(result, Entry{Key: key, Value: entry.Value})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Key < result[j].Key
+	})
+	return result, nil
+}
+
+// entryMap 转换为键值映射, noNull 为true时null按空串输出(toml 不支持null)
+func entryMap(entries []Entry, noNull bool) map[string]interface{} {
+	result := make(map[string]interface{}, len(entries))
+	for _, entry := range entries {
+		result[entry.Key] = normalize(entry.Value, noNull)
+	}
+	return result
+}
+
+// normalize 将JSON解码得到的整数形式的float64还原为int64, 避免 8080 输出为 8080.0
+func normalize(value interface{}, noNull bool) interface{} {
+	switch v := value.(type) {
+	case nil:
+		if noNull {
+			return ""
+		}
+		return nil
+	case float64:
+		if v == math.Trunc(v) && math.Abs(v) < 1<<53 {
+			return int64(v)
+		}
+		return v
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			result[key] = normalize(item, noNull)
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(v))
+		for i, item := range v {
+			result[i] = normalize(item, noNull)
+		}
+		return result
+	default:
+		return value
+	}
+}
+
+// isEnvName 判断是否为合法的环境变量名
+func isEnvName(name string) bool {
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		return false
+	}
+	for _, r := range name {
+		if r != '_' && !isAlnum(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// dotenvQuote dotenv格式的值, 包含特殊字符时使用双引号并转义
+func dotenvQuote(value string) string {
+	if value != "" && !strings.ContainsAny(value, " \t\r\n\"'#$\\`=") {
+		return value
+	}
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range value {
+		switch r {
+		case '\\', '"', '$', '`':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// shellQuote 使用单引号引用, 值中的单引号先结束引用再转义
+func shellQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
+
+// systemdQuote systemd EnvironmentFile 的值, 包含特殊字符时使用双引号, 换行保留在引号内
+func systemdQuote(value string) string {
+	if value != "" && !strings.ContainsAny(value, " \t\r\n\"'#;\\") {
+		return value
+	}
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+	return `"` + replacer.Replace(value) + `"`
+}
+
+// propertiesEscape 按 java.util.Properties 的规则转义, 非ASCII字符写作 \uXXXX
+func propertiesEscape(value string, key bool) string {
+	var b strings.Builder
+	for i, r := range value {
+		switch r {
+		case '\\':
+			b.WriteString(`\\`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		case '\f':
+			b.WriteString(`\f`)
+		case '=', ':', '#', '!':
+			// 只有键中的分隔符与注释符需要转义
+			if key {
+				b.WriteByte('\\')
+			}
+			b.WriteRune(r)
+		case ' ':
+			// 键中的空格以及值开头的空格需要转义
+			if key || i == 0 {
+				b.WriteByte('\\')
+			}
+			b.WriteRune(r)
+		default:
+			if r < 0x20 || r > 0x7e {
+				if r > 0xffff {
+					hi, lo := utf16Surrogates(r)
+					fmt.Fprintf(&b, `\u%04x\u%04x`, hi, lo)
+				} else {
+					fmt.Fprintf(&b, `\u%04x`, r)
+				}
+				continue
+			}
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// utf16Surrogates 将基本平面之外的字符拆分为UTF-16代理对
+func utf16Surrogates(r rune) (rune, rune) {
+	r -= 0x10000
+	return 0xd800 + (r>>10)&0x3ff, 0xdc00 + r&0x3ff
+}
@@ -0,0 +1,199 @@
+package render
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestDotenvQuote(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{value: "", want: `""`},
+		{value: "plain", want: "plain"},
+		{value: "8080", want: "8080"},
+		{value: "a b", want: `"a b"`},
+		{value: "tab\there", want: "\"tab\there\""},
+		{value: "line1\nline2", want: `"line1\nline2"`},
+		{value: "cr\r", want: `"cr\r"`},
+		{value: `say "hi"`, want: `"say \"hi\""`},
+		{value: "it's", want: `"it's"`},
+		{value: "#comment", want: `"#comment"`},
+		{value: "$HOME", want: `"\$HOME"`},
+		{value: `C:\path`, want: `"C:\\path"`},
+		{value: "`id`", want: "\"\\`id\\`\""},
+		{value: "a=b", want: `"a=b"`},
+		{value: "中文", want: "中文"},
+	}
+
+	for _, tt := range tests {
+		if got := dotenvQuote(tt.value); got != tt.want {
+			t.Errorf("dotenvQuote(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestShellQuote(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{value: "", want: `''`},
+		{value: "plain", want: `'plain'`},
+		{value: "$HOME `id` \"x\"", want: `'$HOME ` + "`id`" + ` "x"'`},
+		{value: "it's", want: `'it'\''s'`},
+		{value: "''", want: `''\'''\'''`},
+		{value: "a\nb", want: "'a\nb'"},
+	}
+
+	for _, tt := range tests {
+		if got := shellQuote(tt.value); got != tt.want {
+			t.Errorf("shellQuote(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestSystemdQuote(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{value: "", want: `""`},
+		{value: "plain", want: "plain"},
+		{value: "a;b", want: `"a;b"`},
+		{value: `say "hi" \o/`, want: `"say \"hi\" \\o/"`},
+		{value: "a\nb", want: "\"a\nb\""},
+	}
+
+	for _, tt := range tests {
+		if got := systemdQuote(tt.value); got != tt.want {
+			t.Errorf("systemdQuote(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestPropertiesEscape(t *testing.T) {
+	tests := []struct {
+		value string
+		key   bool
+		want  string
+	}{
+		{value: "db.host", key: true, want: "db.host"},
+		{value: "a=b:c#d!e", key: true, want: `a\=b\:c\#d\!e`},
+		{value: "a=b:c#d!e", key: false, want: "a=b:c#d!e"},
+		{value: "my key", key: true, want: `my\ key`},
+		{value: " leading and inner", key: false, want: `\ leading and inner`},
+		{value: `C:\path`, key: false, want: `C:\\path`},
+		{value: "a\nb\rc\td\fe", key: false, want: `a\nb\rc\td\fe`},
+		{value: "\x01", key: false, want: `\u0001`},
+		{value: "\x7f", key: false, want: `\u007f`},
+		{value: "中文", key: false, want: `\u4e2d\u6587`},
+		{value: "é", key: true, want: `\u00e9`},
+		{value: "😀", key: false, want: `\ud83d\ude00`},
+		{value: "", key: false, want: ""},
+	}
+
+	for _, tt := range tests {
+		if got := propertiesEscape(tt.value, tt.key); got != tt.want {
+			t.Errorf("propertiesEscape(%q, %v) = %q, want %q", tt.value, tt.key, got, tt.want)
+		}
+	}
+}
+
+func TestIsEnvName(t *testing.T) {
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{name: "DB_HOST", want: true},
+		{name: "_private", want: true},
+		{name: "a1", want: true},
+		{name: "", want: false},
+		{name: "1A", want: false},
+		{name: "db.host", want: false},
+		{name: "A B", want: false},
+		{name: "A=B", want: false},
+		{name: "A;rm", want: false},
+		{name: "A\nB", want: false},
+		{name: "ÄBC", want: false},
+	}
+
+	for _, tt := range tests {
+		if got := isEnvName(tt.name); got != tt.want {
+			t.Errorf("isEnvName(%q) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestRenderRejectsInvalidEnvKeys(t *testing.T) {
+	keys := []string{"A\nB", "A=B", "A B", "A;touch x", "db.host"}
+	formats := []string{FormatDotenv, FormatExport, FormatSystemd}
+
+	for _, format := range formats {
+		for _, key := range keys {
+			var buf bytes.Buffer
+			err := Render(&buf, format, []Entry{{Key: key, Value: "v"}}, KeyOptions{})
+			if !errors.Is(err, ErrInvalidKey) {
+				t.Errorf("Render(%s, %q) error = %v, want ErrInvalidKey", format, key, err)
+			}
+			if buf.Len() != 0 {
+				t.Errorf("Render(%s, %q) wrote %q, want nothing", format, key, buf.String())
+			}
+		}
+	}
+}
+
+func TestRender(t *testing.T) {
+	entries := []Entry{
+		{Key: "db.host", Value: "localhost"},
+		{Key: "dbPort", Value: float64(5432)},
+		{Key: "greeting", Value: "hello world"},
+		{Key: "tags", Value: []interface{}{"a", "b"}},
+		{Key: "empty", Value: nil},
+	}
+	upper := KeyOptions{Case: CaseUpperSnake, Prefix: "APP_"}
+
+	tests := []struct {
+		format string
+		keys   KeyOptions
+		want   string
+	}{
+		{
+			format: FormatDotenv,
+			keys:   upper,
+			want: "APP_DB_HOST=localhost\nAPP_DB_PORT=5432\nAPP_EMPTY=\"\"\n" +
+				"APP_GREETING=\"hello world\"\nAPP_TAGS=\"[\\\"a\\\",\\\"b\\\"]\"\n",
+		},
+		{
+			format: FormatExport,
+			keys:   upper,
+			want: "export APP_DB_HOST='localhost'\nexport APP_DB_PORT='5432'\nexport APP_EMPTY=''\n" +
+				"export APP_GREETING='hello world'\nexport APP_TAGS='[\"a\",\"b\"]'\n",
+		},
+		{
+			format: FormatProperties,
+			want:   "db.host=localhost\ndbPort=5432\nempty=\ngreeting=hello world\ntags=[\"a\",\"b\"]\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := Render(&buf, tt.format, entries, tt.keys); err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("Render() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderUnknownFormat(t *testing.T) {
+	err := Render(&bytes.Buffer{}, "ini", nil, KeyOptions{})
+	if !errors.Is(err, ErrUnknownFormat) {
+		t.Errorf("Render(ini) error = %v, want ErrUnknownFormat", err)
+	}
+}
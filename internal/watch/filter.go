@@ -0,0 +1,144 @@
+package watch
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"nidavellir/internal/etcd"
+)
+
+// Selector 订阅条件, 服务名与配置键均支持通配符(语法同 path.Match)
+type Selector struct {
+	// Service 服务名称或通配符
+	Service string
+	// Key 配置键或通配符, 为空时匹配服务下的所有配置
+	Key string
+}
+
+// ParseSelector 解析 service 或 service/key 形式的订阅条件, 如 Heimdallr/Job*
+func ParseSelector(s string) (Selector, error) {
+	service, key, _ := strings.Cut(s, "/")
+	if service == "" {
+		return Selector{}, fmt.Errorf("invalid selector %q: empty service", s)
+	}
+	for _, pattern := range []string{service, key} {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return Selector{}, fmt.Errorf("invalid selector %q: %w", s, err)
+		}
+	}
+	return Selector{Service: service, Key: key}, nil
+}
+
+// ExactSelector 只匹配指定服务(与配置键)本身的订阅条件, 名称中的通配符被转义
+func ExactSelector(service, key string) Selector {
+	return Selector{Service: escape(service), Key: escape(key)}
+}
+
+// Match 判断配置是否满足订阅条件
+func (s Selector) Match(service, key string) bool {
+	if ok, _ := path.Match(s.Service, service); !ok {
+		return false
+	}
+	if s.Key == "" {
+		return true
+	}
+	ok, _ := path.Match(s.Key, key)
+	return ok
+}
+
+// String 订阅条件的文本形式
+func (s Selector) String() string {
+	if s.Key == "" {
+		return s.Service
+	}
+	return s.Service + "/" + s.Key
+}
+
+// Filter 订阅过滤条件, 满足任一订阅条件即可, 为空时匹配所有服务
+type Filter struct {
+	Selectors []Selector
+}
+
+// NewFilter 创建单个服务(或单个配置)的过滤条件, 服务名与配置键按原样匹配
+func NewFilter(service, key string) Filter {
+	return Filter{Selectors: []Selector{ExactSelector(service, key)}}
+}
+
+// Match 判断事件是否满足过滤条件
+func (f Filter) Match(event *etcd.ConfigEvent) bool {
+	return f.MatchKey(event.ServiceName, event.Key)
+}
+
+// MatchKey 判断配置是否满足过滤条件
+func (f Filter) MatchKey(service, key string) bool {
+	if len(f.Selectors) == 0 {
+		return true
+	}
+	for _, selector := range f.Selectors {
+		if selector.Match(service, key) {
+			return true
+		}
+	}
+	return false
+}
+
+// Scope 获取能够覆盖过滤条件的最小读取范围, 服务名为空表示所有服务。
+// 仅在单个不含通配符的条件下才能缩小范围。
+func (f Filter) Scope() (service, key string) {
+	if len(f.Selectors) != 1 {
+		return "", ""
+	}
+	service, ok := literal(f.Selectors[0].Service)
+	if !ok {
+		return "", ""
+	}
+	if key, ok = literal(f.Selectors[0].Key); !ok {
+		return service, ""
+	}
+	return service, key
+}
+
+// Strings 过滤条件的文本形式, 匹配所有服务时为 *
+func (f Filter) Strings() []string {
+	if len(f.Selectors) == 0 {
+		return []string{"*"}
+	}
+	result := make([]string, 0, len(f.Selectors))
+	for _, selector := range f.Selectors {
+		result = append(result, selector.String())
+	}
+	return result
+}
+
+// escape 转义通配符, 使其只匹配名称本身
+func escape(name string) string {
+	var b strings.Builder
+	for _, r := range name {
+		switch r {
+		case '*', '?', '[', ']', '\\':
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// literal 去除转义, 返回通配符只能匹配的那一个名称, 含未转义的通配符时返回false
+func literal(pattern string) (string, bool) {
+	var b strings.Builder
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; c {
+		case '*', '?', '[':
+			return "", false
+		case '\\':
+			if i++; i == len(pattern) {
+				return "", false
+			}
+			b.WriteByte(pattern[i])
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String(), true
+}
@@ -0,0 +1,177 @@
+package watch
+
+import (
+	"reflect"
+	"testing"
+
+	"nidavellir/internal/etcd"
+)
+
+func TestParseSelector(t *testing.T) {
+	tests := []struct {
+		s       string
+		want    Selector
+		wantErr bool
+	}{
+		{s: "Heimdallr", want: Selector{Service: "Heimdallr"}},
+		{s: "Heimdallr/", want: Selector{Service: "Heimdallr"}},
+		{s: "Heimdallr/Job*", want: Selector{Service: "Heimdallr", Key: "Job*"}},
+		{s: "*/Port", want: Selector{Service: "*", Key: "Port"}},
+		{s: "H?imdallr/[A-Z]*", want: Selector{Service: "H?imdallr", Key: "[A-Z]*"}},
+		{s: "Palace/a/b", want: Selector{Service: "Palace", Key: "a/b"}},
+		{s: "", wantErr: true},
+		{s: "/Port", wantErr: true},
+		{s: "Heim[dallr", wantErr: true},
+		{s: "Heimdallr/Job[", wantErr: true},
+		{s: `Heimdallr/Job\`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseSelector(tt.s)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseSelector(%q) = %+v, want error", tt.s, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("ParseSelector(%q) = %+v, %v, want %+v", tt.s, got, err, tt.want)
+		}
+		if got.String() != tt.want.String() {
+			t.Errorf("ParseSelector(%q).String() = %q", tt.s, got.String())
+		}
+	}
+}
+
+func TestSelectorMatch(t *testing.T) {
+	tests := []struct {
+		selector Selector
+		service  string
+		key      string
+		want     bool
+	}{
+		{selector: Selector{Service: "Heimdallr"}, service: "Heimdallr", key: "Port", want: true},
+		{selector: Selector{Service: "Heimdallr"}, service: "Heimdallr2", key: "Port", want: false},
+		{selector: Selector{Service: "Heimdallr", Key: "Port"}, service: "Heimdallr", key: "Port", want: true},
+		{selector: Selector{Service: "Heimdallr", Key: "Port"}, service: "Heimdallr", key: "Ports", want: false},
+		{selector: Selector{Service: "Heimdallr", Key: "Job*"}, service: "Heimdallr", key: "JobQueue", want: true},
+		{selector: Selector{Service: "Heimdallr", Key: "Job*"}, service: "Heimdallr", key: "Job", want: true},
+		{selector: Selector{Service: "Heimdallr", Key: "Job*"}, service: "Heimdallr", key: "job", want: false},
+		{selector: Selector{Service: "*"}, service: "Palace", key: "Port", want: true},
+		{selector: Selector{Service: "*", Key: "Mongo?RL"}, service: "Palace", key: "MongoURL", want: true},
+		{selector: Selector{Service: "H*"}, service: "Palace", key: "Port", want: false},
+		{selector: Selector{Service: "[HP]*", Key: "[^a-z]*"}, service: "Palace", key: "Port", want: true},
+		{selector: Selector{Service: "[HP]*", Key: "[^a-z]*"}, service: "Palace", key: "port", want: false},
+		// 与 path.Match 相同, * 不匹配 /
+		{selector: Selector{Service: "Palace", Key: "*"}, service: "Palace", key: "a/b", want: false},
+		{selector: Selector{Service: "Palace", Key: "a/*"}, service: "Palace", key: "a/b", want: true},
+	}
+
+	for _, tt := range tests {
+		if got := tt.selector.Match(tt.service, tt.key); got != tt.want {
+			t.Errorf("%s.Match(%s, %s) = %v, want %v", tt.selector, tt.service, tt.key, got, tt.want)
+		}
+	}
+}
+
+func TestFilterMatch(t *testing.T) {
+	filter := Filter{Selectors: []Selector{
+		{Service: "Heimdallr", Key: "Job*"},
+		{Service: "Palace"},
+	}}
+
+	tests := []struct {
+		filter  Filter
+		service string
+		key     string
+		want    bool
+	}{
+		{filter: Filter{}, service: "Anything", key: "Key", want: true},
+		{filter: filter, service: "Heimdallr", key: "JobQueue", want: true},
+		{filter: filter, service: "Heimdallr", key: "Port", want: false},
+		{filter: filter, service: "Palace", key: "Port", want: true},
+		{filter: filter, service: "Octopus", key: "JobQueue", want: false},
+		{filter: NewFilter("Palace", "Port"), service: "Palace", key: "Port", want: true},
+		{filter: NewFilter("Palace", "Port"), service: "Palace", key: "Host", want: false},
+		{filter: NewFilter("Palace", ""), service: "Palace", key: "Host", want: true},
+		// service_name 与 key 按原样匹配, 通配符不生效
+		{filter: NewFilter("Palace", "a[1]"), service: "Palace", key: "a[1]", want: true},
+		{filter: NewFilter("Palace", "a[1]"), service: "Palace", key: "a1", want: false},
+		{filter: NewFilter("Palace", "db*"), service: "Palace", key: "db*", want: true},
+		{filter: NewFilter("Palace", "db*"), service: "Palace", key: "dbx", want: false},
+		{filter: NewFilter("Palace", "["), service: "Palace", key: "[", want: true},
+		{filter: NewFilter("Palace", `a\b`), service: "Palace", key: `a\b`, want: true},
+		{filter: NewFilter("Pal*", ""), service: "Palace", key: "Host", want: false},
+		{filter: NewFilter("Pal*", ""), service: "Pal*", key: "Host", want: true},
+	}
+
+	for _, tt := range tests {
+		event := &etcd.ConfigEvent{ServiceName: tt.service, Key: tt.key}
+		if got := tt.filter.Match(event); got != tt.want {
+			t.Errorf("%v.Match(%s/%s) = %v, want %v", tt.filter.Strings(), tt.service, tt.key, got, tt.want)
+		}
+	}
+}
+
+func TestExactSelector(t *testing.T) {
+	names := []string{"Palace", "a[1]", "db*", "[", "]", "a?b", `a\b`, `\`, "数据库*", "a/b"}
+	for _, name := range names {
+		selector := ExactSelector("Palace", name)
+		// 转义后的条件与ParseSelector的结果一致, 客户端登记的订阅条件可以还原
+		parsed, err := ParseSelector(selector.String())
+		if err != nil || parsed != selector {
+			t.Errorf("ParseSelector(%q) = %+v, %v, want %+v", selector.String(), parsed, err, selector)
+		}
+		if !selector.Match("Palace", name) {
+			t.Errorf("ExactSelector(Palace, %q).Match(Palace, %q) = false", name, name)
+		}
+	}
+}
+
+func TestFilterScope(t *testing.T) {
+	tests := []struct {
+		filter      Filter
+		wantService string
+		wantKey     string
+	}{
+		{filter: Filter{}},
+		{filter: NewFilter("Palace", ""), wantService: "Palace"},
+		{filter: NewFilter("Palace", "Port"), wantService: "Palace", wantKey: "Port"},
+		{filter: Filter{Selectors: []Selector{{Service: "Palace", Key: "Po*"}}}, wantService: "Palace"},
+		{filter: Filter{Selectors: []Selector{{Service: "Palace", Key: `Port\`}}}, wantService: "Palace"},
+		{filter: Filter{Selectors: []Selector{{Service: "Pal?ce", Key: "Port"}}}},
+		{filter: Filter{Selectors: []Selector{{Service: "[P]alace"}}}},
+		{filter: Filter{Selectors: []Selector{{Service: "Palace", Key: `Po\*rt`}}}, wantService: "Palace", wantKey: "Po*rt"},
+		// 精确匹配的名称中含通配符时仍能缩小范围
+		{filter: NewFilter("Palace", "Po*"), wantService: "Palace", wantKey: "Po*"},
+		{filter: NewFilter("db[1]", `a\b?`), wantService: "db[1]", wantKey: `a\b?`},
+		{filter: Filter{Selectors: []Selector{{Service: "Palace"}, {Service: "Heimdallr"}}}},
+	}
+
+	for _, tt := range tests {
+		service, key := tt.filter.Scope()
+		if service != tt.wantService || key != tt.wantKey {
+			t.Errorf("%v.Scope() = %q, %q, want %q, %q", tt.filter.Strings(), service, key, tt.wantService, tt.wantKey)
+		}
+	}
+}
+
+func TestFilterStrings(t *testing.T) {
+	tests := []struct {
+		filter Filter
+		want   []string
+	}{
+		{filter: Filter{}, want: []string{"*"}},
+		{filter: NewFilter("Palace", ""), want: []string{"Palace"}},
+		{
+			filter: Filter{Selectors: []Selector{{Service: "Palace", Key: "Port"}, {Service: "H*", Key: "Job*"}}},
+			want:   []string{"Palace/Port", "H*/Job*"},
+		},
+	}
+
+	for _, tt := range tests {
+		if got := tt.filter.Strings(); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Strings() = %v, want %v", got, tt.want)
+		}
+	}
+}
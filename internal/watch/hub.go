@@ -0,0 +1,404 @@
+// Package watch 配置变更的进程内分发
+//
+// Hub 在 ConfigPrefix 上维护唯一的etcd监听, 将事件按订阅条件分发给各个订阅者,
+// 每个订阅者持有有界的缓冲区, 消费过慢时按配置的策略断开或合并事件。
+package watch
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"nidavellir/internal/config"
+	"nidavellir/internal/etcd"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// PolicyDisconnect 缓冲区满时断开订阅者, 由客户端按版本号恢复
+	PolicyDisconnect = "disconnect"
+	// PolicyCoalesce 缓冲区满时合并同一键的事件, 只保留最新的变更
+	PolicyCoalesce = "coalesce"
+)
+
+var (
+	// ErrSlowConsumer 订阅者消费过慢被断开
+	ErrSlowConsumer = errors.New("slow consumer disconnected")
+	// ErrResync 分发中心丢失了部分事件, 订阅者需要重新同步
+	ErrResync = errors.New("watch hub lost events, resync required")
+	// errHistoryUnavailable 请求的起始版本不在历史事件范围内
+	errHistoryUnavailable = errors.New("start revision not in history")
+)
+
+// Hub 配置变更分发中心
+type Hub struct {
+	configService    *etcd.ConfigService
+	logger           *zap.Logger
+	policy           string
+	bufferSize       int
+	historySize      int
+	progressInterval time.Duration
+
+	mu          sync.RWMutex
+	subscribers map[uint64]*subscriber
+	// revision 已分发的最新版本号, 0表示尚未开始监听
+	revision int64
+	// history 最近的事件, historyFrom 之后(包含)的事件均在其中
+	history     []*etcd.ConfigEvent
+	historyFrom int64
+	// services 各服务最近一次变更的版本号, 包括删除
+	services map[string]int64
+
+	nextID       atomic.Uint64
+	dispatched   atomic.Int64
+	disconnected atomic.Int64
+}
+
+// Stats 分发中心统计信息
+type Stats struct {
+	Revision     int64             `json:"revision"`
+	Subscribers  int               `json:"subscribers"`
+	Dispatched   int64             `json:"dispatched"`
+	Disconnected int64             `json:"disconnected"`
+	HistoryFrom  int64             `json:"history_from"`
+	HistorySize  int               `json:"history_size"`
+	Policy       string            `json:"slow_consumer_policy"`
+	Details      []SubscriberStats `json:"details"`
+}
+
+// SubscriberStats 订阅者统计信息
+type SubscriberStats struct {
+	ID                uint64   `json:"id"`
+	Filter            []string `json:"filter"`
+	ConnectedAt       int64    `json:"connected_at"`
+	Queued            int      `json:"queued"`
+	Lag               int64    `json:"lag"`
+	DeliveredRevision int64    `json:"delivered_revision"`
+	Coalesced         int64    `json:"coalesced"`
+}
+
+// NewHub 创建分发中心
+func NewHub(cfg config.WatchConfig, progressInterval time.Duration, configService *etcd.ConfigService, logger *zap.Logger) *Hub {
+	policy := cfg.SlowConsumer
+	if policy != PolicyCoalesce {
+		policy = PolicyDisconnect
+	}
+	bufferSize := cfg.BufferSize
+	if bufferSize <= 0 {
+		bufferSize = 256
+	}
+
+	return &Hub{
+		configService:    configService,
+		logger:           logger,
+		policy:           policy,
+		bufferSize:       bufferSize,
+		historySize:      cfg.HistorySize,
+		progressInterval: progressInterval,
+		subscribers:      make(map[uint64]*subscriber),
+		services:         make(map[string]int64),
+	}
+}
+
+// Run 维护全局监听, 直到ctx结束
+func (h *Hub) Run(ctx context.Context) {
+	for {
+		err := h.watch(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+
+		h.logger.Warn("Watch hub stream broken, restarting", zap.Error(err))
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+// watch 从已分发版本的下一个版本开始监听, 版本未知时从当前版本开始
+func (h *Hub) watch(ctx context.Context) error {
+	h.mu.RLock()
+	revision := h.revision
+	h.mu.RUnlock()
+
+	if revision == 0 {
+		current, err := h.configService.CurrentRevision(ctx)
+		if err != nil {
+			return err
+		}
+		h.mu.Lock()
+		h.revision = current
+		h.historyFrom = current + 1
+		h.mu.Unlock()
+		revision = current
+	}
+
+	watchChan := h.configService.WatchAll(ctx, etcd.WatchOptions{
+		StartRevision:    revision + 1,
+		ProgressInterval: h.progressInterval,
+	})
+	for resp := range watchChan {
+		if resp.CompactRevision > 0 {
+			// 断线期间的事件已被压缩, 无法保证订阅者的连续性
+			h.reset(ErrResync)
+			return errors.New("watch hub revision compacted")
+		}
+		if resp.Err != nil {
+			return resp.Err
+		}
+		h.dispatch(resp)
+	}
+
+	return errors.New("watch hub stream closed")
+}
+
+// dispatch 记录事件并分发给订阅者
+func (h *Hub) dispatch(resp *etcd.WatchResponse) {
+	events := resp.Events
+	if len(events) == 0 {
+		if !resp.Progress {
+			return
+		}
+		events = []*etcd.ConfigEvent{{Type: etcd.EventTypeProgress, Revision: resp.Revision}}
+	}
+
+	h.mu.Lock()
+	for _, event := range events {
+		if event.Revision > h.revision {
+			h.revision = event.Revision
+		}
+		if event.Type == etcd.EventTypeProgress {
+			continue
+		}
+		h.services[event.ServiceName] = event.Revision
+		if h.historySize > 0 {
+			h.history = append(h.history, event)
+		}
+	}
+	h.trimHistory()
+	subscribers := make([]*subscriber, 0, len(h.subscribers))
+	for _, sub := range h.subscribers {
+		subscribers = append(subscribers, sub)
+	}
+	h.mu.Unlock()
+
+	for _, sub := range subscribers {
+		if !sub.push(events) {
+			h.removeSlow(sub)
+		}
+	}
+	h.dispatched.Add(int64(len(resp.Events)))
+}
+
+// trimHistory 按版本整体淘汰超出容量的历史事件, 不保留历史时只能从下一个版本开始订阅
+func (h *Hub) trimHistory() {
+	if h.historySize <= 0 {
+		h.historyFrom = h.revision + 1
+		return
+	}
+	if len(h.history) <= h.historySize {
+		return
+	}
+
+	cut := len(h.history) - h.historySize
+	// 同一版本的事件必须一起淘汰
+	for cut < len(h.history) && h.history[cut].Revision == h.history[cut-1].Revision {
+		cut++
+	}
+	if cut < len(h.history) {
+		h.historyFrom = h.history[cut].Revision
+	} else {
+		h.historyFrom = h.revision + 1
+	}
+	h.history = append([]*etcd.ConfigEvent(nil), h.history[cut:]...)
+}
+
+// reset 清空状态并断开所有订阅者
+func (h *Hub) reset(err error) {
+	h.mu.Lock()
+	subscribers := h.subscribers
+	h.subscribers = make(map[uint64]*subscriber)
+	h.revision = 0
+	h.history = nil
+	h.historyFrom = 0
+	h.mu.Unlock()
+
+	for _, sub := range subscribers {
+		sub.close(err)
+	}
+}
+
+// removeSlow 移除消费过慢的订阅者
+func (h *Hub) removeSlow(sub *subscriber) {
+	h.disconnected.Add(1)
+	h.unsubscribe(sub)
+	h.logger.Warn("Watch subscriber disconnected: slow consumer",
+		zap.Uint64("id", sub.id),
+		zap.Strings("filter", sub.filter.Strings()))
+}
+
+// subscribe 注册订阅者, startRevision大于0时先从历史事件中重放
+func (h *Hub) subscribe(filter Filter, startRevision int64) (*subscriber, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	sub := newSubscriber(h.nextID.Add(1), filter, h.policy, h.bufferSize)
+	// 起始版本可能领先于分发中心, 此前的事件到达时需要丢弃
+	sub.from = startRevision
+	if startRevision > 0 && (h.revision == 0 || startRevision <= h.revision) {
+		// 尚未开始监听时无法保证连续性, 同样退化为直接监听
+		if h.revision == 0 || startRevision < h.historyFrom {
+			return nil, errHistoryUnavailable
+		}
+		i := sort.Search(len(h.history), func(i int) bool {
+			return h.history[i].Revision >= startRevision
+		})
+		if !sub.push(h.history[i:]) {
+			return nil, errHistoryUnavailable
+		}
+	}
+
+	h.subscribers[sub.id] = sub
+	return sub, nil
+}
+
+// unsubscribe 注销订阅者
+func (h *Hub) unsubscribe(sub *subscriber) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.subscribers, sub.id)
+}
+
+// Watch 订阅配置变更, 返回与 ConfigService.WatchConfigs 相同格式的响应。
+// 起始版本早于保留的历史事件时, 退化为直接监听etcd。
+// 订阅者被断开时, 最后一个响应的Err为 ErrSlowConsumer 或 ErrResync。
+func (h *Hub) Watch(ctx context.Context, filter Filter, startRevision int64) <-chan *etcd.WatchResponse {
+	sub, err := h.subscribe(filter, startRevision)
+	if err != nil {
+		return h.watchDirect(ctx, filter, startRevision)
+	}
+
+	out := make(chan *etcd.WatchResponse)
+	go func() {
+		defer close(out)
+		defer h.unsubscribe(sub)
+
+		for {
+			events, err := sub.next(ctx)
+			if err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				select {
+				case out <- &etcd.WatchResponse{Err: err}:
+				case <-ctx.Done():
+				}
+				return
+			}
+
+			resp := &etcd.WatchResponse{Revision: events[len(events)-1].Revision}
+			for _, event := range events {
+				if event.Type == etcd.EventTypeProgress {
+					resp.Progress = true
+					continue
+				}
+				resp.Events = append(resp.Events, event)
+			}
+
+			select {
+			case out <- resp:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return out
+}
+
+// watchDirect 绕过分发中心直接监听etcd, 并按过滤条件筛选事件
+func (h *Hub) watchDirect(ctx context.Context, filter Filter, startRevision int64) <-chan *etcd.WatchResponse {
+	service, key := filter.Scope()
+	watchChan := h.configService.WatchConfigs(ctx, service, key, etcd.WatchOptions{
+		StartRevision:    startRevision,
+		ProgressInterval: h.progressInterval,
+	})
+
+	out := make(chan *etcd.WatchResponse)
+	go func() {
+		defer close(out)
+		for resp := range watchChan {
+			events := resp.Events[:0:0]
+			for _, event := range resp.Events {
+				if filter.Match(event) {
+					events = append(events, event)
+				}
+			}
+			if len(resp.Events) > 0 && len(events) == 0 && resp.Err == nil && resp.CompactRevision == 0 {
+				continue
+			}
+			resp.Events = events
+
+			select {
+			case out <- resp:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return out
+}
+
+// Revision 获取已分发的最新版本号
+func (h *Hub) Revision() int64 {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.revision
+}
+
+// ServiceRevisions 获取启动以来各服务最近一次变更的版本号
+func (h *Hub) ServiceRevisions() map[string]int64 {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	result := make(map[string]int64, len(h.services))
+	for service, revision := range h.services {
+		result[service] = revision
+	}
+	return result
+}
+
+// Stats 获取统计信息
+func (h *Hub) Stats() Stats {
+	h.mu.RLock()
+	stats := Stats{
+		Revision:     h.revision,
+		Subscribers:  len(h.subscribers),
+		Dispatched:   h.dispatched.Load(),
+		Disconnected: h.disconnected.Load(),
+		HistoryFrom:  h.historyFrom,
+		HistorySize:  len(h.history),
+		Policy:       h.policy,
+	}
+	subscribers := make([]*subscriber, 0, len(h.subscribers))
+	for _, sub := range h.subscribers {
+		subscribers = append(subscribers, sub)
+	}
+	h.mu.RUnlock()
+
+	stats.Details = make([]SubscriberStats, 0, len(subscribers))
+	for _, sub := range subscribers {
+		stats.Details = append(stats.Details, sub.stats(stats.Revision))
+	}
+	sort.Slice(stats.Details, func(i, j int) bool {
+		return stats.Details[i].ID < stats.Details[j].ID
+	})
+
+	return stats
+}
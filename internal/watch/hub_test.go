@@ -0,0 +1,88 @@
+package watch
+
+import (
+	"errors"
+	"testing"
+
+	"nidavellir/internal/config"
+	"nidavellir/internal/etcd"
+
+	"go.uber.org/zap"
+)
+
+// newTestHub 创建已从 revision 开始监听的分发中心, 不连接etcd
+func newTestHub(historySize int, revision int64) *Hub {
+	h := NewHub(config.WatchConfig{HistorySize: historySize}, 0, nil, zap.NewNop())
+	h.revision = revision
+	h.historyFrom = revision + 1
+	return h
+}
+
+func putEvents(revisions ...int64) *etcd.WatchResponse {
+	resp := &etcd.WatchResponse{}
+	for _, revision := range revisions {
+		resp.Events = append(resp.Events, &etcd.ConfigEvent{
+			Type:        etcd.EventTypePut,
+			ServiceName: "Palace",
+			Key:         "Port",
+			Revision:    revision,
+		})
+		resp.Revision = revision
+	}
+	return resp
+}
+
+func TestSubscribeHistory(t *testing.T) {
+	tests := []struct {
+		name          string
+		historySize   int
+		startRevision int64
+		wantErr       bool
+		wantReplay    int
+	}{
+		{name: "no history, older revision", historySize: 0, startRevision: 11, wantErr: true},
+		{name: "no history, latest revision", historySize: 0, startRevision: 13, wantErr: true},
+		{name: "no history, next revision", historySize: 0, startRevision: 14},
+		{name: "no history, no start revision", historySize: 0},
+		{name: "history, older revision", historySize: 10, startRevision: 12, wantReplay: 2},
+		{name: "history, before history", historySize: 10, startRevision: 10, wantErr: true},
+		{name: "history trimmed", historySize: 1, startRevision: 12, wantErr: true},
+		{name: "history trimmed, latest revision", historySize: 1, startRevision: 13, wantReplay: 1},
+		{name: "future revision", historySize: 10, startRevision: 20},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTestHub(tt.historySize, 10)
+			h.dispatch(putEvents(11))
+			h.dispatch(putEvents(12, 13))
+
+			sub, err := h.subscribe(Filter{}, tt.startRevision)
+			if tt.wantErr {
+				if !errors.Is(err, errHistoryUnavailable) {
+					t.Fatalf("subscribe(%d) error = %v, want errHistoryUnavailable", tt.startRevision, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("subscribe(%d) error = %v", tt.startRevision, err)
+			}
+			if got := len(sub.queue); got != tt.wantReplay {
+				t.Errorf("subscribe(%d) replayed %d events, want %d", tt.startRevision, got, tt.wantReplay)
+			}
+		})
+	}
+}
+
+func TestTrimHistoryKeepsRevisionsTogether(t *testing.T) {
+	h := newTestHub(2, 10)
+	h.dispatch(putEvents(11))
+	h.dispatch(putEvents(12, 12, 12))
+
+	if h.historyFrom != 13 {
+		t.Errorf("historyFrom = %d, want 13", h.historyFrom)
+	}
+	if len(h.history) != 0 {
+		t.Errorf("history has %d events, want 0", len(h.history))
+	}
+}
@@ -0,0 +1,163 @@
+package watch
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"nidavellir/internal/etcd"
+)
+
+// subscriber 订阅者, 持有有界的事件队列
+type subscriber struct {
+	id          uint64
+	filter      Filter
+	policy      string
+	bufferSize  int
+	connectedAt time.Time
+	// from 起始版本, 早于该版本的事件不再推送
+	from int64
+
+	mu        sync.Mutex
+	queue     []*etcd.ConfigEvent
+	delivered int64
+	coalesced int64
+	err       error
+	notify    chan struct{}
+}
+
+// newSubscriber 创建订阅者
+func newSubscriber(id uint64, filter Filter, policy string, bufferSize int) *subscriber {
+	return &subscriber{
+		id:          id,
+		filter:      filter,
+		policy:      policy,
+		bufferSize:  bufferSize,
+		connectedAt: time.Now(),
+		notify:      make(chan struct{}, 1),
+	}
+}
+
+// push 追加事件, 缓冲区满时按策略合并或断开, 返回false表示订阅者已断开
+func (s *subscriber) push(events []*etcd.ConfigEvent) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.err != nil {
+		return false
+	}
+
+	for _, event := range events {
+		if event.Revision < s.from {
+			continue
+		}
+		if event.Type == etcd.EventTypeProgress {
+			// 有待发送的事件时进度通知没有意义
+			if len(s.queue) > 0 {
+				continue
+			}
+		} else if !s.filter.Match(event) {
+			continue
+		}
+		s.queue = append(s.queue, event)
+	}
+
+	if len(s.queue) > s.bufferSize {
+		if s.policy == PolicyCoalesce {
+			s.coalesce()
+		}
+		if len(s.queue) > s.bufferSize {
+			s.queue = nil
+			s.err = ErrSlowConsumer
+		}
+	}
+
+	s.signal()
+	return s.err == nil
+}
+
+// coalesce 合并队列中同一键的事件, 仅保留最后一次变更
+func (s *subscriber) coalesce() {
+	latest := make(map[string]int, len(s.queue))
+	for i, event := range s.queue {
+		latest[event.ServiceName+"/"+event.Key] = i
+	}
+
+	merged := make([]*etcd.ConfigEvent, 0, len(latest))
+	for i, event := range s.queue {
+		if event.Type == etcd.EventTypeProgress {
+			continue
+		}
+		if latest[event.ServiceName+"/"+event.Key] == i {
+			merged = append(merged, event)
+		}
+	}
+
+	s.coalesced += int64(len(s.queue) - len(merged))
+	s.queue = merged
+}
+
+// close 以指定错误关闭订阅者
+func (s *subscriber) close(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err == nil {
+		s.err = err
+		s.queue = nil
+	}
+	s.signal()
+}
+
+// signal 通知有新的事件或状态变化
+func (s *subscriber) signal() {
+	select {
+	case s.notify <- struct{}{}:
+	default:
+	}
+}
+
+// next 取出队列中的所有事件, 队列为空时阻塞
+func (s *subscriber) next(ctx context.Context) ([]*etcd.ConfigEvent, error) {
+	for {
+		s.mu.Lock()
+		if s.err != nil {
+			err := s.err
+			s.mu.Unlock()
+			return nil, err
+		}
+		if len(s.queue) > 0 {
+			events := s.queue
+			s.queue = nil
+			s.delivered = events[len(events)-1].Revision
+			s.mu.Unlock()
+			return events, nil
+		}
+		s.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-s.notify:
+		}
+	}
+}
+
+// stats 获取订阅者统计信息
+func (s *subscriber) stats(revision int64) SubscriberStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stats := SubscriberStats{
+		ID:                s.id,
+		Filter:            s.filter.Strings(),
+		ConnectedAt:       s.connectedAt.Unix(),
+		Queued:            len(s.queue),
+		DeliveredRevision: s.delivered,
+		Coalesced:         s.coalesced,
+	}
+	if len(s.queue) > 0 {
+		// 滞后的版本数: 最早未送达事件之前的版本到当前版本
+		stats.Lag = revision - s.queue[0].Revision + 1
+	}
+	return stats
+}
@@ -14,6 +14,8 @@ import (
 	"nidavellir/initializer"
 
 	"go.uber.org/zap"
+	"nidavellir/internal/etcd"
+	"nidavellir/internal/gitops"
 	"nidavellir/internal/grpc"
 	httpSvr "nidavellir/internal/http"
 )
@@ -22,8 +24,38 @@ func main() {
 	// 初始化
 	glb := initializer.InitialSequence()
 
+	// 启动配置变更分发中心
+	hubCtx, stopHub := context.WithCancel(context.Background())
+	defer stopHub()
+	go glb.Hub.Run(hubCtx)
+
+	// 启动配置缓存与etcd健康检查
+	cacheCtx, stopCache := context.WithCancel(context.Background())
+	defer stopCache()
+	if glb.Cfg.Cache.Enable {
+		go glb.ConfigService.RunCache(cacheCtx, etcd.CacheOptions{
+			ProgressInterval: time.Duration(glb.Cfg.GRPC.WatchProgressInterval) * time.Second,
+			SnapshotPath:     glb.Cfg.Cache.SnapshotPath,
+			SnapshotInterval: time.Duration(glb.Cfg.Cache.SnapshotInterval) * time.Second,
+		})
+	}
+	if glb.Cfg.Etcd.HealthCheckInterval > 0 {
+		go glb.ConfigService.RunHealthCheck(cacheCtx, time.Duration(glb.Cfg.Etcd.HealthCheckInterval)*time.Second)
+	}
+
+	// 从目录同步服务配置
+	if glb.Cfg.Sync.Dir != "" && glb.Cfg.Sync.Interval > 0 {
+		reconciler := gitops.NewReconciler(glb.ConfigService, glb.Cfg.Sync, glb.Logger)
+		go reconciler.Run(cacheCtx, time.Duration(glb.Cfg.Sync.Interval)*time.Second)
+	}
+
+	// 定期本地备份
+	if glb.Backups.Enabled() {
+		go glb.Backups.Run(cacheCtx)
+	}
+
 	// 启动HTTP服务器
-	httpServer := httpSvr.NewServer(glb.Cfg.HTTP, glb.ConfigService, glb.Logger)
+	httpServer := httpSvr.NewServer(glb.Cfg.HTTP, glb.ConfigService, glb.Seeder, glb.Backups, glb.Diff, glb.Hub, glb.Clients, glb.Auth, glb.Logger)
 	go func() {
 		if glb.Cfg.HTTP.Enable {
 			if err := httpServer.Start(); err != nil && !errors.Is(err, http.ErrServerClosed) {
@@ -33,7 +65,7 @@ func main() {
 	}()
 
 	// 启动gRPC服务器
-	grpcServer := grpc.NewServer(glb.Cfg.GRPC, glb.ConfigService, glb.Logger)
+	grpcServer := grpc.NewServer(glb.Cfg.GRPC, glb.ConfigService, glb.Backups, glb.Diff, glb.Hub, glb.Clients, glb.Auth, glb.Logger)
 	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", glb.Cfg.GRPC.Port))
 	if err != nil {
 		glb.Logger.Fatal("Failed to listen gRPC port", zap.Error(err))
@@ -59,10 +91,25 @@ func main() {
 		zap.Int("grpc_port", glb.Cfg.GRPC.Port),
 		zap.String("grpc_uds", glb.Cfg.Twig.Address))
 
+	// SIGHUP 重新加载种子配置
+	reload := make(chan os.Signal, 1)
+	signal.Notify(reload, syscall.SIGHUP)
+	go func() {
+		for range reload {
+			glb.Logger.Info("Reloading seed configs")
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			if _, err := glb.Seeder.Reload(ctx, "", false); err != nil {
+				glb.Logger.Error("Failed to reload seed configs", zap.Error(err))
+			}
+			cancel()
+		}
+	}()
+
 	// 优雅关闭
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
+	signal.Stop(reload)
 
 	glb.Logger.Info("Shutting down servers...")
 
@@ -75,6 +122,8 @@ func main() {
 
 	// 关闭gRPC服务器
 	grpcServer.GracefulStop()
+	stopHub()
+	stopCache()
 
 	glb.Logger.Info("Servers stopped")
 	glb.Logger.Sync()
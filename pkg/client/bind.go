@@ -0,0 +1,349 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// bindTag 结构体绑定使用的标签名
+//
+//	type PalaceConfig struct {
+//		Host       string        `nidavellir:"Host,required"`
+//		Port       int           `nidavellir:"Port,default=22222"`
+//		UploadSize int64         `nidavellir:"UploadSize"`
+//		Timeout    time.Duration `nidavellir:"Timeout,default=30s"`
+//		Internal   string        `nidavellir:"-"`
+//	}
+//
+// 未设置标签的导出字段使用字段名作为配置键。
+const bindTag = "nidavellir"
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// Validator 绑定的结构体可实现此接口, 在每次填充后进行校验
+type Validator interface {
+	Validate() error
+}
+
+// Binding 服务配置与结构体的绑定, 配置变更时原子替换为新的结构体
+type Binding struct {
+	service *Service
+	typ     reflect.Type
+	current atomic.Value
+	// reloadMu 串行化重新加载, Bind 中补充的加载可能与监听协程中的加载同时进行,
+	// 避免较旧的配置覆盖较新的结果, 回调也按加载顺序执行
+	reloadMu sync.Mutex
+
+	mu       sync.RWMutex
+	err      error
+	onReload []func(interface{})
+	onError  []func(error)
+}
+
+// Bind 加载服务配置填充target (结构体指针), 并在配置变化时重新填充。
+// 变更后的配置通过 Binding.Load 获取, target本身仅保存首次加载的结果。
+// 校验失败时保留旧的结构体, 错误通过 Binding.Err 与 OnError 回调返回。
+func (c *Client) Bind(ctx context.Context, service string, target interface{}) (*Binding, error) {
+	v := reflect.ValueOf(target)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return nil, errors.New("bind target must be a non-nil pointer to struct")
+	}
+
+	svc, err := c.Service(ctx, service)
+	if err != nil {
+		return nil, err
+	}
+
+	items := svc.All()
+	if err := populate(v.Elem(), items); err != nil {
+		svc.Close()
+		return nil, fmt.Errorf("failed to bind %s: %w", service, err)
+	}
+
+	b := &Binding{
+		service: svc,
+		typ:     v.Elem().Type(),
+	}
+	b.current.Store(target)
+	svc.OnChange(func(Event) {
+		b.reload()
+	})
+
+	// 注册回调前发生的变更需要补一次加载
+	if !sameItems(items, svc.All()) {
+		b.reload()
+	}
+
+	return b, nil
+}
+
+// Load 获取当前生效的结构体指针, 类型与Bind传入的target相同
+func (b *Binding) Load() interface{} {
+	return b.current.Load()
+}
+
+// Err 获取最近一次重新加载的错误, 成功后清空
+func (b *Binding) Err() error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.err
+}
+
+// OnReload 注册重新加载成功的回调, 参数为新的结构体指针
+func (b *Binding) OnReload(fn func(interface{})) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.onReload = append(b.onReload, fn)
+}
+
+// OnError 注册重新加载失败的回调
+func (b *Binding) OnError(fn func(error)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.onError = append(b.onError, fn)
+}
+
+// Close 停止监听
+func (b *Binding) Close() {
+	b.service.Close()
+}
+
+// reload 根据最新的缓存重新填充结构体
+func (b *Binding) reload() {
+	b.reloadMu.Lock()
+	defer b.reloadMu.Unlock()
+
+	next := reflect.New(b.typ)
+	err := populate(next.Elem(), b.service.All())
+	if err != nil {
+		err = fmt.Errorf("failed to reload %s: %w", b.service.Name(), err)
+		b.service.client.logger.Warn("Config binding kept previous value",
+			zap.String("service", b.service.Name()),
+			zap.Error(err))
+	} else {
+		b.current.Store(next.Interface())
+	}
+
+	b.mu.Lock()
+	b.err = err
+	onReload, onError := b.onReload, b.onError
+	b.mu.Unlock()
+
+	if err != nil {
+		for _, fn := range onError {
+			fn(err)
+		}
+		return
+	}
+	for _, fn := range onReload {
+		fn(next.Interface())
+	}
+}
+
+// sameItems 判断两份缓存的配置值是否相同
+func sameItems(a, b map[string]*Item) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for key, item := range a {
+		other, ok := b[key]
+		if !ok || other.Value != item.Value {
+			return false
+		}
+	}
+	return true
+}
+
+// Load 以泛型方式获取当前生效的结构体指针
+func Load[T any](b *Binding) *T {
+	v, _ := b.Load().(*T)
+	return v
+}
+
+// fieldSpec 字段的绑定描述
+type fieldSpec struct {
+	key        string
+	required   bool
+	def        string
+	hasDefault bool
+}
+
+// parseTag 解析绑定标签, 返回false表示跳过该字段
+func parseTag(field reflect.StructField) (fieldSpec, bool) {
+	tag, ok := field.Tag.Lookup(bindTag)
+	if !ok {
+		return fieldSpec{key: field.Name}, true
+	}
+	if tag == "-" {
+		return fieldSpec{}, false
+	}
+
+	parts := strings.Split(tag, ",")
+	spec := fieldSpec{key: parts[0]}
+	if spec.key == "" {
+		spec.key = field.Name
+	}
+	for i := 1; i < len(parts); i++ {
+		opt := strings.TrimSpace(parts[i])
+		switch {
+		case opt == "required":
+			spec.required = true
+		case strings.HasPrefix(opt, "default="):
+			// 默认值中可能包含逗号, 将剩余部分全部视为默认值
+			spec.def = strings.Join(append([]string{strings.TrimPrefix(opt, "default=")}, parts[i+1:]...), ",")
+			spec.hasDefault = true
+			i = len(parts)
+		}
+	}
+	return spec, true
+}
+
+// populate 使用配置项填充结构体
+func populate(v reflect.Value, items map[string]*Item) error {
+	t := v.Type()
+	var errs []error
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		spec, ok := parseTag(field)
+		if !ok {
+			continue
+		}
+
+		var raw string
+		item, found := items[spec.key]
+		switch {
+		case found:
+			raw = item.Value
+		case spec.required:
+			errs = append(errs, fmt.Errorf("%s: required config is missing", spec.key))
+			continue
+		case spec.hasDefault:
+			raw = spec.def
+		default:
+			continue
+		}
+
+		if err := setField(v.Field(i), raw); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", spec.key, err))
+		}
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return err
+	}
+
+	if validator, ok := v.Addr().Interface().(Validator); ok {
+		if err := validator.Validate(); err != nil {
+			return fmt.Errorf("validation failed: %w", err)
+		}
+	}
+	return nil
+}
+
+// setField 将JSON文本或纯文本的配置值转换为字段类型
+func setField(f reflect.Value, raw string) error {
+	if f.Type() == durationType {
+		d, err := parseDuration(raw)
+		if err != nil {
+			return err
+		}
+		f.SetInt(int64(d))
+		return nil
+	}
+
+	switch f.Kind() {
+	case reflect.String:
+		f.SetString(plainString(raw))
+	case reflect.Bool:
+		b, err := parseBool(raw)
+		if err != nil {
+			return err
+		}
+		f.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := parseInt(raw)
+		if err != nil {
+			return err
+		}
+		if f.OverflowInt(n) {
+			return fmt.Errorf("value %d overflows %s", n, f.Type())
+		}
+		f.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(strings.TrimSpace(plainString(raw)), 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid uint value %q", raw)
+		}
+		if f.OverflowUint(n) {
+			return fmt.Errorf("value %d overflows %s", n, f.Type())
+		}
+		f.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(strings.TrimSpace(plainString(raw)), 64)
+		if err != nil {
+			return fmt.Errorf("invalid float value %q", raw)
+		}
+		f.SetFloat(n)
+	case reflect.Slice:
+		if !isJSONArray(raw) && f.Type().Elem().Kind() != reflect.Uint8 {
+			// 非JSON数组时按逗号分隔的列表处理
+			parts := strings.Split(plainString(raw), ",")
+			list := reflect.MakeSlice(f.Type(), 0, len(parts))
+			for _, part := range parts {
+				if part = strings.TrimSpace(part); part == "" {
+					continue
+				}
+				elem := reflect.New(f.Type().Elem()).Elem()
+				if err := setField(elem, part); err != nil {
+					return err
+				}
+				list = reflect.Append(list, elem)
+			}
+			f.Set(list)
+			return nil
+		}
+		return unmarshalField(f, raw)
+	default:
+		return unmarshalField(f, raw)
+	}
+	return nil
+}
+
+// unmarshalField 其他类型按JSON反序列化, 值为JSON字符串时解析其内容
+func unmarshalField(f reflect.Value, raw string) error {
+	data := []byte(raw)
+	if isJSONString(raw) {
+		data = []byte(plainString(raw))
+	}
+	ptr := reflect.New(f.Type())
+	if err := json.Unmarshal(data, ptr.Interface()); err != nil {
+		return fmt.Errorf("cannot convert %q to %s: %w", raw, f.Type(), err)
+	}
+	f.Set(ptr.Elem())
+	return nil
+}
+
+// isJSONArray 判断是否为JSON数组
+func isJSONArray(raw string) bool {
+	raw = strings.TrimSpace(raw)
+	return strings.HasPrefix(raw, "[") && json.Valid([]byte(raw))
+}
+
+// isJSONString 判断是否为JSON字符串
+func isJSONString(raw string) bool {
+	var s string
+	return json.Unmarshal([]byte(raw), &s) == nil
+}
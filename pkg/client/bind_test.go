@@ -0,0 +1,77 @@
+package client
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// stallConfig Port 为 stallPort 时在校验中等待, 模拟读取旧配置后较慢完成的加载
+type stallConfig struct {
+	Port int `nidavellir:"Port"`
+}
+
+const stallPort = 1
+
+var (
+	stallEntered = make(chan struct{})
+	stallRelease = make(chan struct{})
+)
+
+func (c *stallConfig) Validate() error {
+	if c.Port == stallPort {
+		stallEntered <- struct{}{}
+		<-stallRelease
+	}
+	return nil
+}
+
+func TestBindingReloadSerialized(t *testing.T) {
+	svc := &Service{
+		client: &Client{logger: zap.NewNop()},
+		name:   "Palace",
+		items:  map[string]*Item{"Port": {Key: "Port", Value: "1"}},
+	}
+	b := &Binding{service: svc, typ: reflect.TypeOf(stallConfig{})}
+	b.current.Store(&stallConfig{})
+
+	var (
+		mu       sync.Mutex
+		reloaded []int
+	)
+	b.OnReload(func(v interface{}) {
+		mu.Lock()
+		defer mu.Unlock()
+		reloaded = append(reloaded, v.(*stallConfig).Port)
+	})
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		b.reload()
+	}()
+	<-stallEntered
+
+	// 第一次加载读取旧配置后, 配置更新并触发第二次加载
+	svc.mu.Lock()
+	svc.items = map[string]*Item{"Port": {Key: "Port", Value: "2"}}
+	svc.mu.Unlock()
+	go func() {
+		defer wg.Done()
+		b.reload()
+	}()
+	time.Sleep(20 * time.Millisecond)
+	close(stallRelease)
+	wg.Wait()
+
+	if got := Load[stallConfig](b).Port; got != 2 {
+		t.Errorf("Load().Port = %d, want 2", got)
+	}
+	if want := []int{1, 2}; !reflect.DeepEqual(reloaded, want) {
+		t.Errorf("OnReload calls = %v, want %v", reloaded, want)
+	}
+}
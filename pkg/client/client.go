@@ -0,0 +1,255 @@
+// Package client Nidavellir 配置中心Go客户端
+//
+// 通过gRPC连接服务端, 地址支持 host:port 与 unix:///path (Twig) 两种形式。
+//
+//	c, err := client.New("unix:///var/run/Nidavellir.sock")
+//	svc, err := c.Service(ctx, "Palace")
+//	port := svc.Int("Port", 22222)
+//	svc.OnChange(func(e client.Event) { ... })
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	grpcConfig "nidavellir/api/proto"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+)
+
+// ErrNotFound 配置不存在
+var ErrNotFound = errors.New("config not found")
+
+// pageSize 分页获取服务与配置时每页的数量
+const pageSize = 500
+
+// Client 配置中心客户端
+type Client struct {
+	conn   *grpc.ClientConn
+	rpc    grpcConfig.ConfigServiceClient
+	opts   options
+	logger *zap.Logger
+
+	mu       sync.Mutex
+	services map[*Service]struct{}
+}
+
+// options 客户端选项
+type options struct {
+	token       string
+	name        string
+	logger      *zap.Logger
+	minBackoff  time.Duration
+	maxBackoff  time.Duration
+	dialOptions []grpc.DialOption
+}
+
+// Option 客户端选项
+type Option func(*options)
+
+// WithToken 设置访问令牌
+func WithToken(token string) Option {
+	return func(o *options) {
+		o.token = token
+	}
+}
+
+// WithClientName 设置客户端名称, 服务端在已连接客户端列表中据此区分进程
+func WithClientName(name string) Option {
+	return func(o *options) {
+		o.name = name
+	}
+}
+
+// WithLogger 设置日志记录器
+func WithLogger(logger *zap.Logger) Option {
+	return func(o *options) {
+		o.logger = logger
+	}
+}
+
+// WithBackoff 设置重连退避的最小与最大间隔
+func WithBackoff(min, max time.Duration) Option {
+	return func(o *options) {
+		o.minBackoff = min
+		o.maxBackoff = max
+	}
+}
+
+// WithDialOptions 追加gRPC连接选项
+func WithDialOptions(dialOptions ...grpc.DialOption) Option {
+	return func(o *options) {
+		o.dialOptions = append(o.dialOptions, dialOptions...)
+	}
+}
+
+// New 创建客户端, address支持 host:port 与 unix:///path
+func New(address string, opts ...Option) (*Client, error) {
+	o := options{
+		logger:     zap.NewNop(),
+		minBackoff: 500 * time.Millisecond,
+		maxBackoff: 30 * time.Second,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	dialOptions := []grpc.DialOption{
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	}
+	if o.name != "" {
+		dialOptions = append(dialOptions, grpc.WithUserAgent(o.name))
+	}
+	if o.token != "" {
+		dialOptions = append(dialOptions,
+			grpc.WithUnaryInterceptor(func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, callOpts ...grpc.CallOption) error {
+				return invoker(withToken(ctx, o.token), method, req, reply, cc, callOpts...)
+			}),
+			grpc.WithStreamInterceptor(func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, callOpts ...grpc.CallOption) (grpc.ClientStream, error) {
+				return streamer(withToken(ctx, o.token), desc, cc, method, callOpts...)
+			}),
+		)
+	}
+	dialOptions = append(dialOptions, o.dialOptions...)
+
+	conn, err := grpc.NewClient(address, dialOptions...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create grpc client: %w", err)
+	}
+
+	return &Client{
+		conn:     conn,
+		rpc:      grpcConfig.NewConfigServiceClient(conn),
+		opts:     o,
+		logger:   o.logger,
+		services: make(map[*Service]struct{}),
+	}, nil
+}
+
+// Close 停止所有服务监听并关闭客户端连接
+func (c *Client) Close() error {
+	c.mu.Lock()
+	services := make([]*Service, 0, len(c.services))
+	for s := range c.services {
+		services = append(services, s)
+	}
+	c.mu.Unlock()
+
+	for _, s := range services {
+		s.Close()
+	}
+	return c.conn.Close()
+}
+
+// track 记录正在监听的服务
+func (c *Client) track(s *Service) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.services[s] = struct{}{}
+}
+
+// untrack 移除已关闭的服务
+func (c *Client) untrack(s *Service) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.services, s)
+}
+
+// RPC 获取底层gRPC客户端
+func (c *Client) RPC() grpcConfig.ConfigServiceClient {
+	return c.rpc
+}
+
+// Get 获取单个配置项
+func (c *Client) Get(ctx context.Context, service, key string) (*Item, error) {
+	resp, err := c.rpc.GetConfig(ctx, &grpcConfig.GetConfigRequest{ServiceName: service, Key: key})
+	if err != nil {
+		return nil, err
+	}
+	if !resp.Found {
+		return nil, ErrNotFound
+	}
+	return newItem(resp.Config), nil
+}
+
+// GetAll 分页获取服务的所有配置项
+func (c *Client) GetAll(ctx context.Context, service string) (map[string]*Item, error) {
+	items := make(map[string]*Item)
+	req := &grpcConfig.GetServiceConfigsRequest{ServiceName: service, Limit: pageSize}
+	for {
+		resp, err := c.rpc.GetServiceConfigs(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		for _, config := range resp.Items {
+			items[config.Key] = newItem(config)
+		}
+		// 旧版本的服务端只返回 configs
+		if len(resp.Items) == 0 {
+			for key, config := range resp.Configs {
+				items[key] = newItem(config)
+			}
+		}
+		if resp.NextPageToken == "" {
+			return items, nil
+		}
+		req.PageToken = resp.NextPageToken
+	}
+}
+
+// Set 设置配置项, value为JSON文本时按JSON值存储, 否则按字符串存储
+func (c *Client) Set(ctx context.Context, service, key, value, description string) error {
+	resp, err := c.rpc.SetConfig(ctx, &grpcConfig.SetConfigRequest{
+		ServiceName: service,
+		Key:         key,
+		Value:       value,
+		Description: description,
+	})
+	if err != nil {
+		return err
+	}
+	if !resp.Success {
+		return errors.New(resp.Message)
+	}
+	return nil
+}
+
+// Delete 删除配置项
+func (c *Client) Delete(ctx context.Context, service, key string) error {
+	resp, err := c.rpc.DeleteConfig(ctx, &grpcConfig.DeleteConfigRequest{ServiceName: service, Key: key})
+	if err != nil {
+		return err
+	}
+	if !resp.Success {
+		return errors.New(resp.Message)
+	}
+	return nil
+}
+
+// ListServices 分页列出所有服务
+func (c *Client) ListServices(ctx context.Context) ([]string, error) {
+	var services []string
+	req := &grpcConfig.ListServicesRequest{Limit: pageSize}
+	for {
+		resp, err := c.rpc.ListServices(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		services = append(services, resp.Services...)
+		if resp.NextPageToken == "" {
+			return services, nil
+		}
+		req.PageToken = resp.NextPageToken
+	}
+}
+
+// withToken 在请求元数据中附加令牌
+func withToken(ctx context.Context, token string) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+token)
+}
@@ -0,0 +1,368 @@
+package client
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+
+	grpcConfig "nidavellir/api/proto"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// EventType 变更事件类型
+type EventType string
+
+const (
+	// EventPut 新增或更新
+	EventPut EventType = "PUT"
+	// EventDelete 删除
+	EventDelete EventType = "DELETE"
+)
+
+// 快照模式下的监听事件类型
+const (
+	eventSnapshot    = "SNAPSHOT"
+	eventSnapshotEnd = "SNAPSHOT_END"
+)
+
+// Event 配置变更事件
+type Event struct {
+	Type    EventType
+	Service string
+	Key     string
+	// Item 变更后的配置项, 删除时为nil
+	Item *Item
+	// Old 变更前的配置项, 新增时为nil
+	Old *Item
+}
+
+// Service 服务配置的本地缓存, 通过快照模式的WatchConfig加载并保持最新
+type Service struct {
+	client *Client
+	name   string
+
+	mu    sync.RWMutex
+	items map[string]*Item
+	// revision 已应用的最新版本号, 0表示需要重新获取快照
+	revision int64
+
+	cbMu      sync.RWMutex
+	callbacks []func(Event)
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// Service 以快照方式加载服务的所有配置并开始监听变化, 使用完毕后需调用Close。
+// 快照与后续变更在同一个监听流中送达, 加载与监听之间不会遗漏变更。
+func (c *Client) Service(ctx context.Context, name string) (*Service, error) {
+	watchCtx, cancel := context.WithCancel(context.Background())
+	s := &Service{
+		client: c,
+		name:   name,
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+
+	// 加载期间ctx结束时中断快照的接收
+	stop := context.AfterFunc(ctx, cancel)
+	stream, items, revision, err := s.snapshot(watchCtx)
+	stop()
+	if err != nil {
+		cancel()
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, err
+	}
+	s.items = items
+	s.revision = revision
+
+	c.track(s)
+	go s.run(watchCtx, stream)
+
+	return s, nil
+}
+
+// Name 服务名称
+func (s *Service) Name() string {
+	return s.name
+}
+
+// Close 停止监听
+func (s *Service) Close() {
+	s.cancel()
+	<-s.done
+	s.client.untrack(s)
+}
+
+// Revision 获取已应用的最新版本号
+func (s *Service) Revision() int64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.revision
+}
+
+// OnChange 注册变更回调, 回调在监听协程中按事件顺序同步执行
+func (s *Service) OnChange(fn func(Event)) {
+	s.cbMu.Lock()
+	defer s.cbMu.Unlock()
+	s.callbacks = append(s.callbacks, fn)
+}
+
+// Get 获取配置项
+func (s *Service) Get(key string) (*Item, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	item, ok := s.items[key]
+	return item, ok
+}
+
+// All 获取所有配置项的副本
+func (s *Service) All() map[string]*Item {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	items := make(map[string]*Item, len(s.items))
+	for key, item := range s.items {
+		items[key] = item
+	}
+	return items
+}
+
+// String 获取字符串配置, 不存在时返回def
+func (s *Service) String(key, def string) string {
+	item, ok := s.Get(key)
+	if !ok {
+		return def
+	}
+	return item.String()
+}
+
+// Int 获取整数配置, 不存在或无法解析时返回def
+func (s *Service) Int(key string, def int) int {
+	item, ok := s.Get(key)
+	if !ok {
+		return def
+	}
+	n, err := item.Int()
+	if err != nil {
+		return def
+	}
+	return int(n)
+}
+
+// Bool 获取布尔配置, 不存在或无法解析时返回def
+func (s *Service) Bool(key string, def bool) bool {
+	item, ok := s.Get(key)
+	if !ok {
+		return def
+	}
+	b, err := item.Bool()
+	if err != nil {
+		return def
+	}
+	return b
+}
+
+// Duration 获取时长配置, 不存在或无法解析时返回def
+func (s *Service) Duration(key string, def time.Duration) time.Duration {
+	item, ok := s.Get(key)
+	if !ok {
+		return def
+	}
+	d, err := item.Duration()
+	if err != nil {
+		return def
+	}
+	return d
+}
+
+// run 监听循环, 断线后按指数退避重连。已知版本号时从断点恢复,
+// 版本未知或已被压缩时重新获取快照。
+func (s *Service) run(ctx context.Context, stream grpcConfig.ConfigService_WatchConfigClient) {
+	defer close(s.done)
+
+	backoff := s.client.opts.minBackoff
+	for {
+		err := s.consume(stream)
+		if ctx.Err() != nil {
+			return
+		}
+
+		if status.Code(err) == codes.OutOfRange {
+			s.setRevision(0)
+		}
+
+		for {
+			s.client.logger.Warn("Watch stream broken, reconnecting",
+				zap.String("service", s.name),
+				zap.Duration("backoff", backoff),
+				zap.Error(err))
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(jitter(backoff)):
+			}
+
+			backoff *= 2
+			if backoff > s.client.opts.maxBackoff {
+				backoff = s.client.opts.maxBackoff
+			}
+
+			stream, err = s.reconnect(ctx)
+			if err == nil {
+				backoff = s.client.opts.minBackoff
+				break
+			}
+			if ctx.Err() != nil {
+				return
+			}
+		}
+	}
+}
+
+// reconnect 重新建立监听流, 已知版本号时从下一个版本开始恢复,
+// 否则重新获取快照并为断线期间的差异触发回调
+func (s *Service) reconnect(ctx context.Context) (grpcConfig.ConfigService_WatchConfigClient, error) {
+	if rev := s.Revision(); rev > 0 {
+		return s.client.rpc.WatchConfig(ctx, &grpcConfig.WatchConfigRequest{
+			ServiceName:   s.name,
+			StartRevision: rev + 1,
+		})
+	}
+
+	stream, items, revision, err := s.snapshot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s.replace(items, revision)
+	return stream, nil
+}
+
+// snapshot 以快照模式建立监听流, 读取到SNAPSHOT_END后返回快照内容与版本号
+func (s *Service) snapshot(ctx context.Context) (grpcConfig.ConfigService_WatchConfigClient, map[string]*Item, int64, error) {
+	stream, err := s.client.rpc.WatchConfig(ctx, &grpcConfig.WatchConfigRequest{
+		ServiceName: s.name,
+		Snapshot:    true,
+	})
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	items := make(map[string]*Item)
+	for {
+		resp, err := stream.Recv()
+		if err != nil {
+			return nil, nil, 0, err
+		}
+		switch resp.EventType {
+		case eventSnapshot:
+			if resp.Config != nil {
+				items[resp.Config.Key] = newItem(resp.Config)
+			}
+		case eventSnapshotEnd:
+			return stream, items, resp.Revision, nil
+		}
+	}
+}
+
+// consume 接收变更直到监听流中断
+func (s *Service) consume(stream grpcConfig.ConfigService_WatchConfigClient) error {
+	for {
+		resp, err := stream.Recv()
+		if err != nil {
+			return err
+		}
+		if resp.Config == nil {
+			// 进度通知
+			s.setRevision(resp.Revision)
+			continue
+		}
+		s.apply(EventType(resp.EventType), newItem(resp.Config), resp.Revision)
+	}
+}
+
+// setRevision 更新已应用的版本号, revision为0时重置
+func (s *Service) setRevision(revision int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if revision == 0 || revision > s.revision {
+		s.revision = revision
+	}
+}
+
+// apply 应用一条变更并触发回调, 已应用过的版本会被忽略
+func (s *Service) apply(eventType EventType, item *Item, revision int64) {
+	key := item.Key
+
+	s.mu.Lock()
+	if revision > 0 && revision < s.revision {
+		s.mu.Unlock()
+		return
+	}
+	if revision > s.revision {
+		s.revision = revision
+	}
+	old := s.items[key]
+	switch eventType {
+	case EventDelete:
+		if old == nil {
+			s.mu.Unlock()
+			return
+		}
+		delete(s.items, key)
+		item = nil
+	default:
+		eventType = EventPut
+		s.items[key] = item
+	}
+	s.mu.Unlock()
+
+	s.notify(Event{Type: eventType, Service: s.name, Key: key, Item: item, Old: old})
+}
+
+// replace 使用快照替换缓存, 并为差异触发回调
+func (s *Service) replace(items map[string]*Item, revision int64) {
+	s.mu.Lock()
+	old := s.items
+	s.items = items
+	s.revision = revision
+	s.mu.Unlock()
+
+	for key, item := range items {
+		prev, ok := old[key]
+		if ok && prev.Value == item.Value && prev.Description == item.Description {
+			continue
+		}
+		s.notify(Event{Type: EventPut, Service: s.name, Key: key, Item: item, Old: prev})
+	}
+	for key, prev := range old {
+		if _, ok := items[key]; !ok {
+			s.notify(Event{Type: EventDelete, Service: s.name, Key: key, Old: prev})
+		}
+	}
+}
+
+// notify 触发变更回调
+func (s *Service) notify(e Event) {
+	s.cbMu.RLock()
+	callbacks := s.callbacks
+	s.cbMu.RUnlock()
+
+	for _, fn := range callbacks {
+		fn(e)
+	}
+}
+
+// jitter 在退避时间上增加随机抖动, 避免大量客户端同时重连
+func jitter(d time.Duration) time.Duration {
+	if d <= 0 {
+		return 0
+	}
+	return d/2 + time.Duration(rand.Int63n(int64(d)))
+}
@@ -0,0 +1,109 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	grpcConfig "nidavellir/api/proto"
+)
+
+// Item 配置项, Value为服务端返回的JSON文本
+type Item struct {
+	Key         string
+	Value       string
+	ServiceName string
+	Description string
+	CreatedAt   int64
+	UpdatedAt   int64
+}
+
+// newItem 从protobuf配置项转换
+func newItem(config *grpcConfig.ConfigItem) *Item {
+	if config == nil {
+		return nil
+	}
+	return &Item{
+		Key:         config.Key,
+		Value:       config.Value,
+		ServiceName: config.ServiceName,
+		Description: config.Description,
+		CreatedAt:   config.CreatedAt,
+		UpdatedAt:   config.UpdatedAt,
+	}
+}
+
+// String 获取字符串形式的值, JSON字符串会去掉引号
+func (i *Item) String() string {
+	return plainString(i.Value)
+}
+
+// Int 获取整数值
+func (i *Item) Int() (int64, error) {
+	return parseInt(i.Value)
+}
+
+// Float 获取浮点数值
+func (i *Item) Float() (float64, error) {
+	return strconv.ParseFloat(strings.TrimSpace(plainString(i.Value)), 64)
+}
+
+// Bool 获取布尔值, 支持 true/false/1/0/yes/no/on/off/enable/disable
+func (i *Item) Bool() (bool, error) {
+	return parseBool(i.Value)
+}
+
+// Duration 获取时长, 支持 "1m30s" 形式, 纯数字按秒处理
+func (i *Item) Duration() (time.Duration, error) {
+	return parseDuration(i.Value)
+}
+
+// plainString JSON字符串去掉引号, 其他JSON值保持原文
+func plainString(raw string) string {
+	var s string
+	if err := json.Unmarshal([]byte(raw), &s); err == nil {
+		return s
+	}
+	return raw
+}
+
+// parseInt 解析整数, 兼容JSON数字与数字字符串
+func parseInt(raw string) (int64, error) {
+	s := strings.TrimSpace(plainString(raw))
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return n, nil
+	}
+	// 兼容 1e3 等JSON数字写法
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil || f != float64(int64(f)) {
+		return 0, fmt.Errorf("invalid int value %q", raw)
+	}
+	return int64(f), nil
+}
+
+// parseBool 解析布尔值
+func parseBool(raw string) (bool, error) {
+	switch strings.ToLower(strings.TrimSpace(plainString(raw))) {
+	case "true", "1", "yes", "y", "on", "enable", "enabled":
+		return true, nil
+	case "false", "0", "no", "n", "off", "disable", "disabled", "":
+		return false, nil
+	default:
+		return false, fmt.Errorf("invalid bool value %q", raw)
+	}
+}
+
+// parseDuration 解析时长
+func parseDuration(raw string) (time.Duration, error) {
+	s := strings.TrimSpace(plainString(raw))
+	if seconds, err := strconv.ParseFloat(s, 64); err == nil {
+		return time.Duration(seconds * float64(time.Second)), nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration value %q", raw)
+	}
+	return d, nil
+}